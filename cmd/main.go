@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
 	https "net/http"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ydoro/wishlist/config"
+	"github.com/ydoro/wishlist/internal/domain"
 	"github.com/ydoro/wishlist/internal/infra/adapter"
 	postgresDB "github.com/ydoro/wishlist/internal/infra/db/postgres"
 	"github.com/ydoro/wishlist/internal/infra/delivery/http"
@@ -29,6 +34,12 @@ func main() {
 	cfg := config.LoadConfig()
 	r := gin.Default()
 
+	// the client IP keys the login lockout, only our own proxies may forward it
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		fmt.Println("TRUSTED_PROXIES is not valid:", err)
+		return
+	}
+
 	conn, err := postgresDB.Connect(
 		cfg.DBHost,
 		cfg.DBPort,
@@ -58,42 +69,283 @@ func main() {
 	redis := adapter.NewRedisCache(*rediscfg)
 	httpClient := &https.Client{}
 
+	var mailer domain.Mailer
+	switch cfg.MailDriver {
+	case "smtp":
+		mailer = adapter.NewSMTPMailer(adapter.SMTPMailerConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		})
+	default:
+		mailOutput := os.Stdout
+		if cfg.MailLogFile != "" {
+			mailOutput, err = os.OpenFile(cfg.MailLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+			if err != nil {
+				fmt.Println("Failed to open the mail log file:", err)
+				return
+			}
+			defer mailOutput.Close()
+		}
+		mailer = adapter.NewLogMailer(cfg.MailFrom, mailOutput)
+	}
+
 	productService := services.NewFakeProductAPIService(cfg.PRODUCT_API_URL, httpClient)
 
 	// TODO - improve DI, use a factory or a DI framework
 	customerRepo := postgresDB.NewCustomerRepository(conn)
 	wishlistRepo := postgresDB.NewWishlistRepository(conn)
 	productRepo := postgresDB.NewProductRepository(conn)
+	refreshTokenRepo := postgresDB.NewRefreshTokenRepository(conn)
+	sessionRepo := postgresDB.NewSessionRepository(conn)
+	oneTimeTokenRepo := postgresDB.NewOneTimeTokenRepository(conn)
+	erasureRepo := postgresDB.NewErasureRepository(conn)
+	oidcIdentityRepo := postgresDB.NewOIDCIdentityRepository(conn)
+	wishlistCollaboratorRepo := postgresDB.NewWishlistCollaboratorRepository(conn)
+	wishlistShareRepo := postgresDB.NewWishlistShareRepository(conn)
+	wishlistReservationRepo := postgresDB.NewWishlistReservationRepository(conn)
 	idGenerator := adapter.UUIDGenerator{}
-	hasher := adapter.NewPasswordHasher(10)
-	jwtEcnoder := adapter.NewJWTEncrypter(cfg.JWTSecret)
+	argon2Params := adapter.DefaultArgon2Params
+	argon2Params.Memory = cfg.Argon2Memory
+	argon2Params.Iterations = cfg.Argon2Time
+	argon2Params.Parallelism = cfg.Argon2Threads
+	hasher := adapter.NewPasswordHasher(argon2Params)
+	tokenHasher := adapter.NewSHA256Hasher()
+	tokenGenerator := adapter.NewSecureTokenGenerator(32)
+	jwtEcnoder := adapter.NewJWTEncrypter(cfg.JWTSecret, cfg.JWTIssuer, cfg.AccessTokenTTL)
+	if cfg.JWTSigningKey != "" {
+		jwtEcnoder, err = loadAsymmetricJWTEncrypter(cfg)
+		if err != nil {
+			fmt.Println("Failed to load the JWT keys:", err)
+			return
+		}
+	}
 
-	authMiddleware := middleware.NewAuthMiddleware(jwtEcnoder)
+	mfaKey, err := base64.StdEncoding.DecodeString(cfg.MFAEncryptKey)
+	if err != nil {
+		fmt.Println("MFA_ENCRYPTION_KEY is not valid base64:", err)
+		return
+	}
+	secretEncrypter, err := adapter.NewAESGCMEncrypter(mfaKey)
+	if err != nil {
+		fmt.Println("Failed to create the MFA secret encrypter:", err)
+		return
+	}
+	mfaRepo := postgresDB.NewMFARepository(conn)
+	totp := adapter.NewTOTP(cfg.MFAIssuer)
+
+	tokenRevocationUC := usecase.NewTokenRevocationUseCase(cfg.AccessTokenTTL, redis, refreshTokenRepo, refreshTokenRepo, sessionRepo, sessionRepo)
+
+	auditRepo := postgresDB.NewAuditRepository(conn)
+	accessPolicy := usecase.NewAccessPolicyUseCase(customerRepo, idGenerator, auditRepo)
+	customerRetentionUC := usecase.NewCustomerRetentionUseCase(cfg.DeletionGrace, customerRepo, customerRepo, customerRepo, accessPolicy)
+
+	tokenIssuerUC := usecase.NewIssueAuthTokensUseCase(cfg.AccessTokenTTL, cfg.RefreshTokenTTL, jwtEcnoder, idGenerator, tokenGenerator, tokenHasher, refreshTokenRepo, sessionRepo, sessionRepo)
+	mfaLoginUC := usecase.NewMFALoginUseCase(cfg.MFAPendingTTL, redis, tokenGenerator, tokenHasher, mfaRepo, mfaRepo, mfaRepo, totp, secretEncrypter, customerRepo, customerRetentionUC, tokenIssuerUC)
+	totpEnrollmentUC := usecase.NewTOTPEnrollmentUseCase(
+		customerRepo,
+		mfaRepo,
+		mfaRepo,
+		mfaRepo,
+		mfaRepo,
+		mfaRepo,
+		mfaRepo,
+		totp,
+		secretEncrypter,
+		secretEncrypter,
+		idGenerator,
+		adapter.NewSecureTokenGenerator(10),
+		tokenHasher,
+		mfaLoginUC,
+	)
+	loginThrottleUC := usecase.NewLoginThrottleUseCase(usecase.LoginThrottleConfig{
+		FreeAttempts:       cfg.LoginFreeTries,
+		BaseDelay:          cfg.LoginBaseDelay,
+		LockoutThreshold:   cfg.LoginLockAfter,
+		IPLockoutThreshold: cfg.LoginIPLockMax,
+		LockoutDuration:    cfg.LoginLockoutTTL,
+		FailureWindow:      cfg.LoginWindowTTL,
+	}, redis)
+	oneTimeTokenThrottleUC := usecase.NewOneTimeTokenThrottleUseCase(usecase.OneTimeTokenThrottleConfig{
+		EmailLimit: cfg.MailEmailLimit,
+		IPLimit:    cfg.MailIPLimit,
+		Window:     cfg.MailLimitWindow,
+	}, redis)
+	backgroundRunner := adapter.NewGoroutineRunner(cfg.TaskTimeout)
+	go runCustomerPurge(cfg.PurgeInterval, customerRetentionUC)
 
-	authUC := usecase.NewPasswordAuthenticationUseCase(hasher, customerRepo, jwtEcnoder)
+	authUC := usecase.NewPasswordAuthenticationUseCase(hasher, customerRepo, tokenIssuerUC, mfaLoginUC, loginThrottleUC, hasher, hasher, customerRepo, customerRetentionUC)
+	refreshUC := usecase.NewRefreshTokenUseCase(tokenHasher, refreshTokenRepo, refreshTokenRepo, tokenRevocationUC, customerRepo, tokenIssuerUC)
 
-	createCustomerUC := usecase.NewCreateCustomerUseCase(customerRepo, idGenerator, adapter.NewPasswordHasher(10), customerRepo)
-	showCustomerUC := usecase.NewGetCustomerData(customerRepo)
-	updateCustomerUc := usecase.NewUpdateCustomerUseCase(customerRepo, customerRepo, customerRepo)
-	deleteCustomerUc := usecase.NewDeleteCustomerUseCase(customerRepo, customerRepo)
+	emailVerificationUC := usecase.NewEmailVerificationUseCase(
+		usecase.EmailVerificationConfig{
+			Required:  cfg.EmailVerifyReq,
+			TokenTTL:  cfg.EmailVerifyTTL,
+			VerifyURL: cfg.EmailVerifyURL,
+		},
+		customerRepo,
+		idGenerator,
+		tokenGenerator,
+		tokenHasher,
+		oneTimeTokenRepo,
+		oneTimeTokenRepo,
+		oneTimeTokenRepo,
+		customerRepo,
+		mailer,
+	)
+
+	var oidcLoginStarter domain.StartOIDCLoginUC
+	var oidcAuthUC domain.Authenticator
+	if cfg.OIDCIssuerURL != "" {
+		oidcProvider := services.NewOIDCProvider(services.OIDCProviderConfig{
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+		}, httpClient)
+		oidcUC := usecase.NewOIDCAuthenticationUseCase(
+			10*time.Minute,
+			redis,
+			oidcProvider,
+			tokenGenerator,
+			idGenerator,
+			oidcIdentityRepo,
+			oidcIdentityRepo,
+			customerRepo,
+			customerRepo,
+			customerRepo,
+			customerRepo,
+			customerRepo,
+			customerRetentionUC,
+			tokenRevocationUC,
+			mfaLoginUC,
+			tokenIssuerUC,
+		)
+		oidcLoginStarter, oidcAuthUC = oidcUC, oidcUC
+	}
+
+	apiKeyRepo := postgresDB.NewAPIKeyRepository(conn)
+	sessionUC := usecase.NewSessionUseCase(sessionRepo, sessionRepo, sessionRepo, redis, tokenRevocationUC, accessPolicy)
+	searchCustomersUC := usecase.NewSearchCustomersUseCase(customerRepo, accessPolicy)
+	preferencesRepo := postgresDB.NewPreferencesRepository(conn)
+	preferencesUC := usecase.NewPreferencesUseCase(customerRepo, preferencesRepo, preferencesRepo, accessPolicy)
+	dataExportUC := usecase.NewDataExportUseCase(
+		cfg.ExportTTL,
+		cfg.TaskTimeout,
+		redis,
+		backgroundRunner,
+		idGenerator,
+		customerRepo,
+		wishlistRepo,
+		productRepo,
+		sessionRepo,
+		auditRepo,
+		preferencesUC,
+		oidcIdentityRepo,
+		mfaRepo,
+		apiKeyRepo,
+		wishlistShareRepo,
+		wishlistCollaboratorRepo,
+		wishlistCollaboratorRepo,
+		wishlistReservationRepo,
+		accessPolicy,
+	)
+	eraseCustomerUC := usecase.NewEraseCustomerUseCase(
+		domain.WishlistRetention(cfg.WishlistRetain),
+		idGenerator,
+		adapter.NewHMACSigner([]byte(cfg.ErasureKey)),
+		customerRepo,
+		customerRepo,
+		wishlistRepo,
+		erasureRepo,
+		erasureRepo,
+		tokenRevocationUC,
+		[]domain.CustomerCacheEvicter{loginThrottleUC, dataExportUC},
+		accessPolicy,
+	)
+	apiKeyUC := usecase.NewAPIKeyUseCase(customerRepo, idGenerator, tokenGenerator, tokenHasher, apiKeyRepo, apiKeyRepo, apiKeyRepo, apiKeyRepo, accessPolicy)
+	authMiddleware := middleware.NewAuthMiddleware(jwtEcnoder, tokenRevocationUC, apiKeyUC, sessionUC)
+
+	breachedPasswords, err := adapter.NewBreachedPasswordList(cfg.BreachedPwdFile)
+	if err != nil {
+		fmt.Println("Failed to load the breached passwords list:", err)
+		return
+	}
+	passwordPolicy := usecase.NewValidatePasswordPolicyUseCase(domain.PasswordPolicyConfig{
+		MinLength:         cfg.PasswordMinLen,
+		MaxLength:         cfg.PasswordMaxLen,
+		MinCharClasses:    cfg.PasswordClasses,
+		BlockPersonalInfo: cfg.PasswordNoInfo,
+	}, breachedPasswords)
+
+	createCustomerUC := usecase.NewCreateCustomerUseCase(customerRepo, idGenerator, hasher, customerRepo, emailVerificationUC, passwordPolicy, customerRetentionUC)
+	showCustomerUC := usecase.NewGetCustomerData(customerRepo, accessPolicy)
+	updateCustomerUc := usecase.NewUpdateCustomerUseCase(customerRepo, customerRepo, customerRepo, customerRetentionUC, emailVerificationUC, accessPolicy)
+	deleteCustomerUc := usecase.NewDeleteCustomerUseCase(customerRepo, customerRepo, tokenRevocationUC, accessPolicy)
+	changePasswordUc := usecase.NewChangeCustomerPasswordUseCase(customerRepo, customerRepo, hasher, hasher, passwordPolicy, tokenRevocationUC, accessPolicy)
+	requestPasswordResetUc := usecase.NewRequestPasswordResetUseCase(cfg.PwdResetTTL, cfg.PwdResetURL, oneTimeTokenThrottleUC, backgroundRunner, customerRepo, idGenerator, tokenGenerator, tokenHasher, oneTimeTokenRepo, mailer)
+	magicLinkUC := usecase.NewMagicLinkUseCase(
+		cfg.MagicLinkTTL,
+		cfg.MagicLinkURL,
+		oneTimeTokenThrottleUC,
+		backgroundRunner,
+		customerRepo,
+		customerRepo,
+		idGenerator,
+		tokenGenerator,
+		tokenHasher,
+		oneTimeTokenRepo,
+		oneTimeTokenRepo,
+		oneTimeTokenRepo,
+		customerRepo,
+		customerRetentionUC,
+		mailer,
+		mfaLoginUC,
+		tokenIssuerUC,
+	)
+	resetPasswordUc := usecase.NewResetPasswordUseCase(tokenHasher, oneTimeTokenRepo, oneTimeTokenRepo, oneTimeTokenRepo, customerRepo, passwordPolicy, hasher, customerRepo, tokenRevocationUC, loginThrottleUC)
 
 	getProductUc := usecase.NewGetProductAndStoreIfNeededUseCase(cfg.CACHE_TTL, redis, productService, productRepo, productRepo, productRepo)
 	listProductUc := usecase.NewListProductsAndStoreUseCase(cfg.CACHE_TTL, redis, productService, productRepo, productRepo, productRepo)
 
-	createWishlistUc := usecase.NewCreateWishlistUseCase(wishlistRepo, wishlistRepo, customerRepo, idGenerator)
-	deleteWishlistUc := usecase.NewDeleteWishlistUseCase(customerRepo, wishlistRepo, wishlistRepo)
-	getWishlistUC := usecase.NewShowWishlistUseCase(wishlistRepo, customerRepo, getProductUc)
-	updateWishlistUC := usecase.NewUpdateWishListUseCase(customerRepo, wishlistRepo, wishlistRepo, getProductUc)
-	listWishlistUC := usecase.NewListCustomerWishlistsUseCase(customerRepo, wishlistRepo, getProductUc)
+	wishlistPolicy := usecase.NewWishlistAccessPolicyUseCase(wishlistCollaboratorRepo, accessPolicy)
+
+	createWishlistUc := usecase.NewCreateWishlistUseCase(wishlistRepo, wishlistRepo, customerRepo, idGenerator, accessPolicy)
+	deleteWishlistUc := usecase.NewDeleteWishlistUseCase(customerRepo, wishlistRepo, wishlistRepo, accessPolicy)
+	getWishlistUC := usecase.NewShowWishlistUseCase(wishlistRepo, customerRepo, getProductUc, wishlistPolicy)
+	updateWishlistUC := usecase.NewUpdateWishListUseCase(customerRepo, wishlistRepo, wishlistRepo, getProductUc, wishlistPolicy)
+	listWishlistUC := usecase.NewListCustomerWishlistsUseCase(customerRepo, wishlistRepo, getProductUc, accessPolicy)
+	wishlistItemsUC := usecase.NewWishlistItemsUseCase(wishlistRepo, wishlistRepo, getProductUc, wishlistPolicy)
+	wishlistReservationUC := usecase.NewWishlistReservationUseCase(customerRepo, wishlistRepo, tokenHasher, wishlistShareRepo, idGenerator, wishlistReservationRepo, wishlistReservationRepo, wishlistReservationRepo, preferencesUC, emailVerificationUC, cfg.ReservationTTL)
+	wishlistShareUC := usecase.NewWishlistShareUseCase(customerRepo, wishlistRepo, getProductUc, idGenerator, tokenGenerator, tokenHasher, wishlistShareRepo, wishlistShareRepo, wishlistShareRepo, wishlistShareRepo, wishlistReservationUC, preferencesUC, emailVerificationUC, accessPolicy)
+	wishlistCollaboratorUC := usecase.NewWishlistCollaboratorUseCase(customerRepo, customerRepo, wishlistRepo, wishlistCollaboratorRepo, wishlistCollaboratorRepo, wishlistCollaboratorRepo, wishlistCollaboratorRepo, mailer, backgroundRunner, emailVerificationUC, accessPolicy)
 
 	router := http.SetupRoutes(
 		r,
 		createCustomerUC,
 		authUC,
-		authMiddleware.Handle,
+		refreshUC,
+		tokenRevocationUC,
+		jwtEcnoder,
+		authMiddleware,
 		showCustomerUC,
 		updateCustomerUc,
 		deleteCustomerUc,
+		changePasswordUc,
+		customerRetentionUC,
+		requestPasswordResetUc,
+		resetPasswordUc,
+		magicLinkUC,
+		magicLinkUC,
+		emailVerificationUC,
+		emailVerificationUC,
+		oidcLoginStarter,
+		oidcAuthUC,
+		mfaLoginUC,
+		totpEnrollmentUC,
 		createWishlistUc,
 		deleteWishlistUc,
 		getWishlistUC,
@@ -101,7 +353,68 @@ func main() {
 		getProductUc,
 		listProductUc,
 		listWishlistUC,
+		wishlistItemsUC,
+		wishlistItemsUC,
+		apiKeyUC,
+		apiKeyUC,
+		apiKeyUC,
+		sessionUC,
+		sessionUC,
+		dataExportUC,
+		dataExportUC,
+		dataExportUC,
+		eraseCustomerUC,
+		eraseCustomerUC,
+		searchCustomersUC,
+		preferencesUC,
+		preferencesUC,
+		wishlistShareUC,
+		wishlistShareUC,
+		wishlistShareUC,
+		wishlistShareUC,
+		wishlistCollaboratorUC,
+		wishlistCollaboratorUC,
+		wishlistCollaboratorUC,
+		wishlistCollaboratorUC,
+		wishlistReservationUC,
+		wishlistReservationUC,
 	)
 
 	router.Run(fmt.Sprintf(":%s", cfg.AppPort))
 }
+
+func loadAsymmetricJWTEncrypter(cfg *config.Config) (*adapter.JWTEncrypter, error) {
+	signingKey, err := adapter.LoadJWTKeyFile(cfg.JWTSigningKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var verificationKeys []*adapter.JWTKey
+	for _, path := range cfg.JWTVerifyKeys {
+		key, err := adapter.LoadJWTKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		verificationKeys = append(verificationKeys, key)
+	}
+
+	return adapter.NewAsymmetricJWTEncrypter(cfg.JWTIssuer, cfg.AccessTokenTTL, signingKey, verificationKeys...)
+}
+
+// runCustomerPurge removes the customers whose deletion grace period is over, it runs once at
+// startup and then every interval for as long as the process lives
+func runCustomerPurge(interval time.Duration, purger domain.PurgeDeletedCustomersUC) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purger.PurgeDeletedCustomers(context.Background())
+		if err != nil {
+			fmt.Printf("[main] ERROR purging deleted customers: %v\n", err)
+		} else if purged > 0 {
+			fmt.Printf("[main] purged %d deleted customers\n", purged)
+		}
+
+		<-ticker.C
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -17,11 +18,63 @@ type Config struct {
 	DBName          string
 	DBSSL           string
 	JWTSecret       string
+	JWTIssuer       string
+	JWTSigningKey   string
+	JWTVerifyKeys   []string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 	CACHE_TTL       time.Duration
 	CACHE_URL       string
 	CACHE_PASSWORD  string
 	CACHE_DATABASE  string
 	PRODUCT_API_URL string
+	PasswordMinLen  int
+	PasswordMaxLen  int
+	PasswordClasses int
+	PasswordNoInfo  bool
+	BreachedPwdFile string
+	MailDriver      string
+	MailFrom        string
+	MailLogFile     string
+	SMTPHost        string
+	SMTPPort        string
+	SMTPUsername    string
+	SMTPPassword    string
+	PwdResetURL     string
+	PwdResetTTL     time.Duration
+	MagicLinkURL    string
+	MagicLinkTTL    time.Duration
+	EmailVerifyReq  bool
+	EmailVerifyURL  string
+	EmailVerifyTTL  time.Duration
+	OIDCIssuerURL   string
+	OIDCClientID    string
+	OIDCSecret      string
+	OIDCRedirectURL string
+	OIDCScopes      []string
+	MFAEncryptKey   string
+	MFAIssuer       string
+	MFAPendingTTL   time.Duration
+	LoginFreeTries  int64
+	LoginBaseDelay  time.Duration
+	LoginLockAfter  int64
+	LoginIPLockMax  int64
+	LoginLockoutTTL time.Duration
+	LoginWindowTTL  time.Duration
+	MailEmailLimit  int64
+	MailIPLimit     int64
+	MailLimitWindow time.Duration
+	TrustedProxies  []string
+	DeletionGrace   time.Duration
+	PurgeInterval   time.Duration
+	Argon2Memory    uint32
+	Argon2Time      uint32
+	Argon2Threads   uint8
+	ExportTTL       time.Duration
+	TaskTimeout     time.Duration
+	ErasureKey      string
+	WishlistRetain  string
+	ReservationTTL  time.Duration
 }
 
 func LoadConfig() *Config {
@@ -34,8 +87,47 @@ func LoadConfig() *Config {
 	viper.SetDefault("CACHE_URL", "redis://redis:6379")
 	viper.SetDefault("CACHE_PASSWORD", "")
 	viper.SetDefault("CACHE_DATABASE", "0")
+	viper.SetDefault("JWT_ISSUER", "wishlist-api")
+	viper.SetDefault("ACCESS_TOKEN_TTL", 15)
+	viper.SetDefault("REFRESH_TOKEN_TTL", 720)
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_MAX_LENGTH", 72)
+	viper.SetDefault("PASSWORD_MIN_CHAR_CLASSES", 2)
+	viper.SetDefault("PASSWORD_BLOCK_PERSONAL_INFO", true)
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FROM", "no-reply@wishlist.local")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("PASSWORD_RESET_URL", "http://localhost:8080/reset-password")
+	viper.SetDefault("PASSWORD_RESET_TTL", 30)
+	viper.SetDefault("MAGIC_LINK_URL", "http://localhost:8080/magic-link")
+	viper.SetDefault("MAGIC_LINK_TTL", 15)
+	viper.SetDefault("EMAIL_VERIFICATION_REQUIRED", true)
+	viper.SetDefault("EMAIL_VERIFICATION_URL", "http://localhost:8080/api/auth/verify")
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", 48)
+	viper.SetDefault("OIDC_REDIRECT_URL", "http://localhost:8080/api/auth/oidc/callback")
+	viper.SetDefault("OIDC_SCOPES", "openid,email,profile")
+	viper.SetDefault("MFA_ISSUER", "Wishlist")
+	viper.SetDefault("MFA_PENDING_TTL", 5)
+	viper.SetDefault("LOGIN_FREE_ATTEMPTS", 3)
+	viper.SetDefault("LOGIN_BASE_DELAY", 1)
+	viper.SetDefault("LOGIN_LOCKOUT_THRESHOLD", 10)
+	viper.SetDefault("LOGIN_IP_LOCKOUT_THRESHOLD", 100)
+	viper.SetDefault("LOGIN_LOCKOUT_DURATION", 15)
+	viper.SetDefault("LOGIN_FAILURE_WINDOW", 60)
+	viper.SetDefault("MAIL_LINK_EMAIL_LIMIT", 3)
+	viper.SetDefault("MAIL_LINK_IP_LIMIT", 20)
+	viper.SetDefault("MAIL_LINK_WINDOW", 60)
+	viper.SetDefault("ACCOUNT_DELETION_GRACE_PERIOD", 30)
+	viper.SetDefault("ACCOUNT_PURGE_INTERVAL", 60)
+	viper.SetDefault("ARGON2_MEMORY", 65536)
+	viper.SetDefault("ARGON2_ITERATIONS", 3)
+	viper.SetDefault("ARGON2_PARALLELISM", 2)
+	viper.SetDefault("DATA_EXPORT_TTL", 24)
+	viper.SetDefault("RESERVATION_TTL", 30)
+	viper.SetDefault("BACKGROUND_TASK_TIMEOUT", 5)
+	viper.SetDefault("ERASURE_WISHLIST_RETENTION", "delete")
 
-	return &Config{
+	cfg := &Config{
 		AppPort:         getEnv("APP_PORT"),
 		DBHost:          getEnv("DB_HOST"),
 		DBPort:          getEnv("DB_PORT"),
@@ -43,13 +135,90 @@ func LoadConfig() *Config {
 		DBPass:          getEnv("DB_PASSWORD"),
 		DBName:          getEnv("DB_NAME"),
 		DBSSL:           getEnv("DB_SSL"),
-		JWTSecret:       getEnv("JWT_SECRET"),
+		JWTSecret:       viper.GetString("JWT_SECRET"),
+		JWTIssuer:       viper.GetString("JWT_ISSUER"),
+		JWTSigningKey:   viper.GetString("JWT_SIGNING_KEY_FILE"),
+		JWTVerifyKeys:   splitList(viper.GetString("JWT_VERIFICATION_KEY_FILES")),
+		AccessTokenTTL:  time.Duration(viper.GetInt("ACCESS_TOKEN_TTL")) * time.Minute,
+		RefreshTokenTTL: time.Duration(viper.GetInt("REFRESH_TOKEN_TTL")) * time.Hour,
 		CACHE_TTL:       time.Duration(viper.GetInt("CACHE_TTL")) * time.Minute,
 		CACHE_URL:       getEnv("CACHE_URL"),
 		CACHE_PASSWORD:  getEnv("CACHE_PASSWORD"),
 		CACHE_DATABASE:  getEnv("CACHE_DATABASE"),
 		PRODUCT_API_URL: getEnv("PRODUCT_API_URL"),
+		PasswordMinLen:  viper.GetInt("PASSWORD_MIN_LENGTH"),
+		PasswordMaxLen:  viper.GetInt("PASSWORD_MAX_LENGTH"),
+		PasswordClasses: viper.GetInt("PASSWORD_MIN_CHAR_CLASSES"),
+		PasswordNoInfo:  viper.GetBool("PASSWORD_BLOCK_PERSONAL_INFO"),
+		BreachedPwdFile: viper.GetString("BREACHED_PASSWORDS_FILE"),
+		MailDriver:      viper.GetString("MAIL_DRIVER"),
+		MailFrom:        viper.GetString("MAIL_FROM"),
+		MailLogFile:     viper.GetString("MAIL_LOG_FILE"),
+		SMTPHost:        viper.GetString("SMTP_HOST"),
+		SMTPPort:        viper.GetString("SMTP_PORT"),
+		SMTPUsername:    viper.GetString("SMTP_USERNAME"),
+		SMTPPassword:    viper.GetString("SMTP_PASSWORD"),
+		PwdResetURL:     viper.GetString("PASSWORD_RESET_URL"),
+		PwdResetTTL:     time.Duration(viper.GetInt("PASSWORD_RESET_TTL")) * time.Minute,
+		MagicLinkURL:    viper.GetString("MAGIC_LINK_URL"),
+		MagicLinkTTL:    time.Duration(viper.GetInt("MAGIC_LINK_TTL")) * time.Minute,
+		EmailVerifyReq:  viper.GetBool("EMAIL_VERIFICATION_REQUIRED"),
+		EmailVerifyURL:  viper.GetString("EMAIL_VERIFICATION_URL"),
+		EmailVerifyTTL:  time.Duration(viper.GetInt("EMAIL_VERIFICATION_TTL")) * time.Hour,
+		OIDCIssuerURL:   viper.GetString("OIDC_ISSUER_URL"),
+		OIDCClientID:    viper.GetString("OIDC_CLIENT_ID"),
+		OIDCSecret:      viper.GetString("OIDC_CLIENT_SECRET"),
+		OIDCRedirectURL: viper.GetString("OIDC_REDIRECT_URL"),
+		OIDCScopes:      splitList(viper.GetString("OIDC_SCOPES")),
+		MFAEncryptKey:   getEnv("MFA_ENCRYPTION_KEY"),
+		MFAIssuer:       viper.GetString("MFA_ISSUER"),
+		MFAPendingTTL:   time.Duration(viper.GetInt("MFA_PENDING_TTL")) * time.Minute,
+		LoginFreeTries:  viper.GetInt64("LOGIN_FREE_ATTEMPTS"),
+		LoginBaseDelay:  time.Duration(viper.GetInt("LOGIN_BASE_DELAY")) * time.Second,
+		LoginLockAfter:  viper.GetInt64("LOGIN_LOCKOUT_THRESHOLD"),
+		LoginIPLockMax:  viper.GetInt64("LOGIN_IP_LOCKOUT_THRESHOLD"),
+		LoginLockoutTTL: time.Duration(viper.GetInt("LOGIN_LOCKOUT_DURATION")) * time.Minute,
+		LoginWindowTTL:  time.Duration(viper.GetInt("LOGIN_FAILURE_WINDOW")) * time.Minute,
+		MailEmailLimit:  viper.GetInt64("MAIL_LINK_EMAIL_LIMIT"),
+		MailIPLimit:     viper.GetInt64("MAIL_LINK_IP_LIMIT"),
+		MailLimitWindow: time.Duration(viper.GetInt("MAIL_LINK_WINDOW")) * time.Minute,
+		TrustedProxies:  splitList(viper.GetString("TRUSTED_PROXIES")),
+		DeletionGrace:   time.Duration(viper.GetInt("ACCOUNT_DELETION_GRACE_PERIOD")) * 24 * time.Hour,
+		PurgeInterval:   time.Duration(viper.GetInt("ACCOUNT_PURGE_INTERVAL")) * time.Minute,
+		Argon2Memory:    viper.GetUint32("ARGON2_MEMORY"),
+		Argon2Time:      viper.GetUint32("ARGON2_ITERATIONS"),
+		Argon2Threads:   uint8(viper.GetUint("ARGON2_PARALLELISM")),
+		ExportTTL:       time.Duration(viper.GetInt("DATA_EXPORT_TTL")) * time.Hour,
+		TaskTimeout:     time.Duration(viper.GetInt("BACKGROUND_TASK_TIMEOUT")) * time.Minute,
+		ErasureKey:      getEnv("ERASURE_RECEIPT_KEY"),
+		WishlistRetain:  viper.GetString("ERASURE_WISHLIST_RETENTION"),
+		ReservationTTL:  time.Duration(viper.GetInt("RESERVATION_TTL")) * 24 * time.Hour,
 	}
+
+	// JWT_SECRET is only needed for HS256, asymmetric keys replace it
+	if cfg.JWTSigningKey == "" && cfg.JWTSecret == "" {
+		log.Fatalf("either JWT_SIGNING_KEY_FILE or JWT_SECRET must be set")
+	}
+
+	if cfg.OIDCIssuerURL != "" && cfg.OIDCClientID == "" {
+		log.Fatalf("OIDC_CLIENT_ID must be set when OIDC_ISSUER_URL is set")
+	}
+
+	if cfg.WishlistRetain != "delete" && cfg.WishlistRetain != "detach" {
+		log.Fatalf("ERASURE_WISHLIST_RETENTION must be either delete or detach")
+	}
+
+	return cfg
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func getEnv(key string) string {
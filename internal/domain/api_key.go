@@ -0,0 +1,100 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/api_key_mock.go -package=mocks -source ./api_key.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeWishlistsRead  APIKeyScope = "wishlists:read"
+	APIKeyScopeWishlistsWrite APIKeyScope = "wishlists:write"
+	APIKeyScopeProducts       APIKeyScope = "products"
+)
+
+func (s APIKeyScope) Valid() bool {
+	switch s {
+	case APIKeyScopeWishlistsRead, APIKeyScopeWishlistsWrite, APIKeyScopeProducts:
+		return true
+	}
+
+	return false
+}
+
+// APIKey lets integrations act as a customer without the password, only a hash of the key is stored
+type APIKey struct {
+	ID         string        `json:"id"`
+	CustomerID string        `json:"customer_id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	KeyHash    string        `json:"-"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	CreatedAt  time.Time     `json:"created_at"`
+	RevokedAt  time.Time     `json:"revoked_at"`
+}
+
+// HasScope reports whether the key grants scope, writing wishlists implies reading them
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || (granted == APIKeyScopeWishlistsWrite && scope == APIKeyScopeWishlistsRead) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type IncommingAPIKey struct {
+	Name   string        `json:"name"`
+	Scopes []APIKeyScope `json:"scopes"`
+}
+
+// CreatedAPIKey carries the plain key, it is only available in the creation response
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyAuthenticator resolves a plain key to the key and its customer,
+// unknown, revoked and orphan keys return an AuthenticationError
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*APIKey, *Customer, error)
+}
+
+// Usecases
+
+type CreateAPIKeyUC interface {
+	CreateAPIKey(ctx context.Context, currentCustomerID string, customerID string, data IncommingAPIKey) (*CreatedAPIKey, error)
+}
+
+type ListAPIKeysUC interface {
+	ListAPIKeys(ctx context.Context, currentCustomerID string, customerID string) ([]*APIKey, error)
+}
+
+type RevokeAPIKeyUC interface {
+	RevokeAPIKey(ctx context.Context, currentCustomerID string, customerID string, keyID string) error
+}
+
+// Repositories
+
+type APIKeyCreationRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+}
+
+// ListByCustomerID returns the active keys of the customer, newest first
+type ListAPIKeysRepository interface {
+	ListByCustomerID(ctx context.Context, customerID string) ([]*APIKey, error)
+}
+
+// GetActiveByHash returns nil when no active key matches the hash
+type APIKeyByHashRepository interface {
+	GetActiveByHash(ctx context.Context, keyHash string) (*APIKey, error)
+}
+
+// Revoke returns false when the customer has no active key with that id
+type RevokeAPIKeyRepository interface {
+	Revoke(ctx context.Context, customerID string, id string, revokedAt time.Time) (bool, error)
+}
@@ -1,19 +1,41 @@
-//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/auth_mock.go -package=mocks . Authenticator,Authorizer
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/auth_mock.go -package=mocks . Authenticator,TokenIssuer,TokenRefresher
 
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type AuthMethod string
 
 const (
-	AuthMethodPassword AuthMethod = "password"
+	AuthMethodPassword     AuthMethod = "password"
+	AuthMethodRefreshToken AuthMethod = "refresh_token"
+	AuthMethodOIDC         AuthMethod = "oidc"
+	AuthMethodTOTP         AuthMethod = "totp"
+	AuthMethodAPIKey       AuthMethod = "api_key"
+	AuthMethodMagicLink    AuthMethod = "magic_link"
 )
 
+type AuthTokens struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	// MFAToken replaces the access and refresh tokens while a second factor is still required
+	MFAToken string `json:"mfa_token,omitempty"`
+}
+
 type Authenticator interface {
-	Authenticate(ctx context.Context, credentials any) (string, error)
+	Authenticate(ctx context.Context, credentials any) (*AuthTokens, error)
+}
+
+// TokenIssuer signs an access token and stores a new refresh token for the customer,
+// an empty familyID starts a new refresh token family and records a session for the client
+type TokenIssuer interface {
+	Issue(ctx context.Context, customer *Customer, familyID string, client ClientInfo) (*AuthTokens, error)
 }
 
-type Authorizer interface {
-	Authorize(ctx context.Context, token string) (string, error)
+type TokenRefresher interface {
+	Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error)
 }
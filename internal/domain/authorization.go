@@ -0,0 +1,72 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/authorization_mock.go -package=mocks -source ./authorization.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleSupport  Role = "support"
+	RoleAdmin    Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermissionCustomerRead  Permission = "customer:read"
+	PermissionCustomerWrite Permission = "customer:write"
+	// credentials are never granted to a role, only owners change their password or API keys
+	PermissionCustomerPassword Permission = "customer:password"
+	PermissionCustomerAPIKeys  Permission = "customer:api_keys"
+	PermissionWishlistRead     Permission = "wishlist:read"
+	PermissionWishlistWrite    Permission = "wishlist:write"
+	// PermissionCustomerSearch lists every customer, it is not tied to an owner
+	PermissionCustomerSearch Permission = "customer:search"
+)
+
+// RolePermissions lists what a role may do on resources owned by other customers,
+// owners can always act on their own resources
+var RolePermissions = map[Role][]Permission{
+	RoleCustomer: {},
+	RoleSupport:  {PermissionCustomerRead, PermissionWishlistRead},
+	RoleAdmin:    {PermissionCustomerRead, PermissionCustomerWrite, PermissionWishlistRead, PermissionWishlistWrite, PermissionCustomerSearch},
+}
+
+// Can reports whether the role grants the permission on resources of other customers
+func (r Role) Can(permission Permission) bool {
+	for _, granted := range RolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuditEvent records an actor accessing a resource owned by another customer
+type AuditEvent struct {
+	ID         string     `json:"id"`
+	ActorID    string     `json:"actor_id"`
+	ActorRole  Role       `json:"actor_role"`
+	Permission Permission `json:"permission"`
+	// OwnerID is empty for permissions not tied to an owner
+	OwnerID   string    `json:"owner_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Policy decides whether actorID may use permission on the resources of ownerID,
+// it returns an UnauthorizedError when it may not. ownerID is empty for permissions not tied to an owner
+type Policy interface {
+	Authorize(ctx context.Context, actorID string, permission Permission, ownerID string) error
+}
+
+// repositories
+
+type AuditEventCreationRepository interface {
+	Create(ctx context.Context, event *AuditEvent) error
+}
@@ -12,4 +12,6 @@ type Cache interface {
 	Set(ctx context.Context, key string, value string, expiration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
 	Delete(ctx context.Context, keys ...string) error
+	// Increment atomically adds one to the counter at key and resets its expiration
+	Increment(ctx context.Context, key string, expiration time.Duration) (int64, error)
 }
@@ -1,4 +1,4 @@
-//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/criptography_mock.go -package=mocks . Hasher,HashComparer,Encrypter,Decrypter
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/criptography_mock.go -package=mocks . Hasher,HashComparer,RehashChecker,Encrypter,Decrypter,Signer
 
 package domain
 
@@ -10,6 +10,11 @@ type HashComparer interface {
 	Compare(hashedPassword, password string) error
 }
 
+// RehashChecker tells whether a password hash was written with an outdated algorithm or parameters
+type RehashChecker interface {
+	NeedsRehash(hashedPassword string) bool
+}
+
 type Encrypter interface {
 	Encrypt(plainText string) (string, error)
 }
@@ -17,3 +22,9 @@ type Encrypter interface {
 type Decrypter interface {
 	Decrypt(cipherText string) (string, error)
 }
+
+// Signer produces and checks signatures of documents the API hands out, like erasure receipts
+type Signer interface {
+	Sign(payload []byte) string
+	Verify(payload []byte, signature string) bool
+}
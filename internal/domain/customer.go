@@ -1,4 +1,4 @@
-//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/customer_mock.go -package=mocks . CreateCustomerUC,ShowCustomerDataUC,CustomerCreationRepository,GetCustomerByEmailRepository,GetCustomerByIDRepository,UpdateCustomerUC,UpdateCustomerRepository,DeleteCustomerUC
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/customer_mock.go -package=mocks . CreateCustomerUC,ShowCustomerDataUC,CustomerCreationRepository,GetCustomerByEmailRepository,GetCustomerByIDRepository,UpdateCustomerUC,UpdateCustomerRepository,DeleteCustomerUC,ChangeCustomerPasswordUC,UpdateCustomerPasswordRepository,RestoreCustomerUC,PurgeDeletedCustomersUC,AccountRestorer,GetDeletedCustomerRepository,RestoreCustomerRepository,PurgeCustomersRepository
 
 package domain
 
@@ -15,6 +15,9 @@ type Customer struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
+	// VerifiedAt is zero until the customer confirms the email address
+	VerifiedAt time.Time `json:"verified_at"`
+	Role       Role      `json:"role"`
 }
 
 type IncommingCustomer struct {
@@ -27,13 +30,19 @@ type CustomerEditableFields struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
 	// NOTE - Password is intentionally omitted here to prevent accidental updates
-	// TODO - Password changes should be handled separately
+	// Password changes are handled separately by ChangeCustomerPasswordUC
+}
+
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
 }
 
 type OutgoingCustomer struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
-	Email     string    `json:"email"`
+	Email     string    `json:"email,omitempty"`
+	Role      Role      `json:"role,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -54,6 +63,28 @@ type DeleteCustomerUC interface {
 	DeleteCustomer(ctx context.Context, currentCustomerID string, customerID string) error
 }
 
+type ChangeCustomerPasswordUC interface {
+	ChangePassword(ctx context.Context, currentCustomerID string, customerID string, data PasswordChange) error
+}
+
+// RestoreCustomerUC reactivates a deleted customer that is still inside the deletion grace period
+type RestoreCustomerUC interface {
+	RestoreCustomer(ctx context.Context, currentCustomerID string, customerID string) (*OutgoingCustomer, error)
+}
+
+// PurgeDeletedCustomersUC permanently removes the customers whose grace period is over
+type PurgeDeletedCustomersUC interface {
+	PurgeDeletedCustomers(ctx context.Context) (int64, error)
+}
+
+// AccountRestorer lets a login reactivate an account deleted during the grace period,
+// GetRestorable and GetRestorableByID return nil when there is no such account
+type AccountRestorer interface {
+	GetRestorable(ctx context.Context, email string) (*Customer, error)
+	GetRestorableByID(ctx context.Context, id string) (*Customer, error)
+	Restore(ctx context.Context, customer *Customer) error
+}
+
 // repositories
 
 type CustomerCreationRepository interface {
@@ -72,3 +103,24 @@ type GetCustomerByIDRepository interface {
 type UpdateCustomerRepository interface {
 	Update(ctx context.Context, customer *Customer) error
 }
+
+// UpdatePassword is the only write path for the password column, Update never touches it
+type UpdateCustomerPasswordRepository interface {
+	UpdatePassword(ctx context.Context, customerID string, passwordHash string, updatedAt time.Time) error
+}
+
+// GetDeletedCustomerRepository only returns customers deleted at or after deletedSince
+type GetDeletedCustomerRepository interface {
+	GetDeletedByEmail(ctx context.Context, email string, deletedSince time.Time) (*Customer, error)
+	GetDeletedByID(ctx context.Context, id string, deletedSince time.Time) (*Customer, error)
+}
+
+type RestoreCustomerRepository interface {
+	Restore(ctx context.Context, customerID string, restoredAt time.Time) error
+}
+
+// PurgeDeletedBefore hard deletes the customers deleted before the given time along with
+// everything referencing them, like their wishlists, and returns how many were removed
+type PurgeCustomersRepository interface {
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,85 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/customer_search_mock.go -package=mocks -source ./customer_search.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type CustomerSort string
+
+const (
+	CustomerSortCreatedAt CustomerSort = "created_at"
+	CustomerSortName      CustomerSort = "name"
+	CustomerSortEmail     CustomerSort = "email"
+)
+
+// Value returns the field of the customer the results are ordered by, as stored in a cursor
+func (s CustomerSort) Value(customer *Customer) string {
+	switch s {
+	case CustomerSortName:
+		return customer.Name
+	case CustomerSortEmail:
+		return customer.Email
+	default:
+		return customer.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+type CustomerStatus string
+
+const (
+	CustomerStatusActive  CustomerStatus = "active"
+	CustomerStatusDeleted CustomerStatus = "deleted"
+	CustomerStatusAll     CustomerStatus = "all"
+)
+
+// CustomerCursor is the position of the last customer of a page, ties on the sort value are
+// broken by ID so no customer is skipped or repeated between pages
+type CustomerCursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+type CustomerSearch struct {
+	// Query matches the beginning of the name or the email, case insensitive
+	Query         string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Status        CustomerStatus
+	Sort          CustomerSort
+	Descending    bool
+	After         *CustomerCursor
+	Limit         int
+}
+
+type CustomerPage struct {
+	Customers []*Customer
+	// NextCursor is empty on the last page
+	NextCursor string
+}
+
+// CustomerSearchParams is the search as requested, SearchCustomers validates it into a CustomerSearch
+type CustomerSearchParams struct {
+	Query         string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Status        string
+	Sort          string
+	Order         string
+	Cursor        string
+	Limit         int
+}
+
+// Usecases
+
+type SearchCustomersUC interface {
+	SearchCustomers(ctx context.Context, currentCustomerID string, params CustomerSearchParams) (*CustomerPage, error)
+}
+
+// Repositories
+
+type SearchCustomersRepository interface {
+	Search(ctx context.Context, search CustomerSearch) ([]*Customer, error)
+}
@@ -0,0 +1,95 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/data_export_mock.go -package=mocks -source ./data_export.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExportJob tracks the background build of a CustomerDataExport, the job and
+// its artifact are both dropped once ExpiresAt is reached
+type DataExportJob struct {
+	ID          string           `json:"id"`
+	CustomerID  string           `json:"customer_id"`
+	Status      DataExportStatus `json:"status"`
+	Error       string           `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt time.Time        `json:"completed_at"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// CustomerDataExport is everything stored about a customer. The password hash and other secrets are
+// left out, like the TOTP secret, the recovery codes and the hashes of API keys and share tokens
+type CustomerDataExport struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Profile     ExportedProfile `json:"profile"`
+	Preferences *Preferences    `json:"preferences"`
+	Identities  []*OIDCIdentity `json:"identities"`
+	// MFA is nil when the customer never enrolled a second factor
+	MFA       *CustomerMFA       `json:"mfa"`
+	APIKeys   []*APIKey          `json:"api_keys"`
+	Wishlists []ExportedWishlist `json:"wishlists"`
+	// Collaborations are the invitations the customer received to wishlists of someone else
+	Collaborations []*WishlistCollaborator `json:"collaborations"`
+	Reservations   []*WishlistReservation  `json:"reservations"`
+	Sessions       []*Session              `json:"sessions"`
+	AuditEvents    []*AuditEvent           `json:"audit_events"`
+}
+
+type ExportedProfile struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Role       Role      `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// ExportedWishlist keeps the items along with the snapshot of every product we stored,
+// products that are no longer stored only appear in Items
+type ExportedWishlist struct {
+	ID            string                  `json:"id"`
+	Title         string                  `json:"title"`
+	Items         []WishlistItem          `json:"items"`
+	Products      []Product               `json:"products"`
+	Shares        []*WishlistShare        `json:"shares"`
+	Collaborators []*WishlistCollaborator `json:"collaborators"`
+}
+
+// BackgroundRunner runs a task outside of the request that started it, the task
+// receives its own context since the request one is canceled once the response is sent
+type BackgroundRunner interface {
+	Go(task func(ctx context.Context))
+}
+
+// Usecases
+
+type StartDataExportUC interface {
+	StartDataExport(ctx context.Context, currentCustomerID string, customerID string) (*DataExportJob, error)
+}
+
+type GetDataExportUC interface {
+	GetDataExport(ctx context.Context, currentCustomerID string, customerID string, jobID string) (*DataExportJob, error)
+}
+
+// DownloadDataExport returns the JSON archive of a ready job
+type DownloadDataExportUC interface {
+	DownloadDataExport(ctx context.Context, currentCustomerID string, customerID string, jobID string) ([]byte, error)
+}
+
+// Repositories
+
+// ListByCustomerID returns the events where the customer is either the actor or the owner, oldest first
+type ListAuditEventsRepository interface {
+	ListByCustomerID(ctx context.Context, customerID string) ([]*AuditEvent, error)
+}
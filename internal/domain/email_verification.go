@@ -0,0 +1,34 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/email_verification_mock.go -package=mocks -source ./email_verification.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type EmailVerificationSender interface {
+	SendVerification(ctx context.Context, customer *Customer) error
+}
+
+// VerifiedEmailGuard must be checked before sharing wishlists, inviting collaborators, reserving gifts or sending
+// any notification to the customer
+type VerifiedEmailGuard interface {
+	EnsureVerified(customer *Customer) error
+}
+
+// Usecases
+
+type VerifyEmailUC interface {
+	VerifyEmail(ctx context.Context, token string) error
+}
+
+type ResendEmailVerificationUC interface {
+	ResendVerification(ctx context.Context, currentCustomerID string) error
+}
+
+// Repositories
+
+type MarkCustomerVerifiedRepository interface {
+	MarkVerified(ctx context.Context, customerID string, verifiedAt time.Time) error
+}
@@ -0,0 +1,82 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/erasure_mock.go -package=mocks -source ./erasure.go
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WishlistRetention decides what an erasure does with the wishlists of the customer
+type WishlistRetention string
+
+const (
+	WishlistRetentionDelete WishlistRetention = "delete"
+	// WishlistRetentionDetach keeps the wishlists without an owner, they only hold product IDs
+	WishlistRetentionDetach WishlistRetention = "detach"
+)
+
+const ErasedCustomerName = "Erased customer"
+
+// ErasedCustomerEmail is the tombstone replacing the email of an erased customer, it keeps
+// the column unique without revealing anything about the original address
+func ErasedCustomerEmail(customerID string) string {
+	return fmt.Sprintf("erased+%s@erased.invalid", customerID)
+}
+
+// ErasureReceipt proves that the personal data of a customer was erased, it is signed so it
+// can be verified later even after the customer row has been purged
+type ErasureReceipt struct {
+	ID                string            `json:"id"`
+	CustomerID        string            `json:"customer_id"`
+	RequestedBy       string            `json:"requested_by"`
+	ErasedAt          time.Time         `json:"erased_at"`
+	WishlistRetention WishlistRetention `json:"wishlist_retention"`
+	// Wishlists is how many wishlists were deleted or detached according to WishlistRetention
+	Wishlists int    `json:"wishlists"`
+	Signature string `json:"signature"`
+}
+
+// Payload is the signed content of the receipt, times are truncated to the second so the
+// signature still matches once the receipt went through the database or JSON
+func (r *ErasureReceipt) Payload() []byte {
+	return []byte(fmt.Sprintf(
+		"erasure:%s:%s:%s:%d:%s:%d",
+		r.ID,
+		r.CustomerID,
+		r.RequestedBy,
+		r.ErasedAt.UTC().Unix(),
+		r.WishlistRetention,
+		r.Wishlists,
+	))
+}
+
+// CustomerCacheEvicter removes what a component cached about a customer
+type CustomerCacheEvicter interface {
+	EvictCustomer(ctx context.Context, customer *Customer) error
+}
+
+// Usecases
+
+// EraseCustomerUC scrubs the personal data of a customer, erasing twice returns the first receipt
+type EraseCustomerUC interface {
+	EraseCustomer(ctx context.Context, currentCustomerID string, customerID string) (*ErasureReceipt, error)
+}
+
+type VerifyErasureReceiptUC interface {
+	VerifyErasureReceipt(ctx context.Context, receipt *ErasureReceipt) (bool, error)
+}
+
+// Repositories
+
+// Erase anonymizes the customer, applies the wishlist retention, removes credentials, sessions
+// and identities and stores the receipt, all or nothing. It returns a ConflictError when the
+// customer was already erased or purged, e.g. by a concurrent erasure
+type EraseCustomerRepository interface {
+	Erase(ctx context.Context, receipt *ErasureReceipt) error
+}
+
+type GetErasureReceiptRepository interface {
+	GetByCustomerID(ctx context.Context, customerID string) (*ErasureReceipt, error)
+}
@@ -43,3 +43,21 @@ func IsUnauthorizedError(err error) bool {
 	}
 	return false
 }
+
+type TokenExpiredError struct {
+}
+
+func (e *TokenExpiredError) Error() string {
+	return "TokenExpiredError: the provided token has expired"
+}
+
+func NewTokenExpiredError() error {
+	return &TokenExpiredError{}
+}
+
+func IsTokenExpiredError(err error) bool {
+	if _, ok := err.(*TokenExpiredError); ok {
+		return true
+	}
+	return false
+}
@@ -104,3 +104,35 @@ func TestUnauthorizedErrorMessage(t *testing.T) {
 		assert.Contains(t, err.Error(), want)
 	})
 }
+
+func TestIsTokenExpiredError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "isTokenExpiredError",
+			err:  e.NewTokenExpiredError(),
+			want: true,
+		},
+		{
+			name: "isNotTokenExpiredError",
+			err:  e.NewUnauthorizedError(),
+			want: false,
+		},
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.IsTokenExpiredError(tt.err); got != tt.want {
+				t.Errorf("IsTokenExpiredError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
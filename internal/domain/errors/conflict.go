@@ -0,0 +1,25 @@
+package errors
+
+import "fmt"
+
+// ConflictError means the request lost a race against another one changing the same resource
+type ConflictError struct {
+	Reason string `json:"reason"`
+}
+
+func NewConflictError(reason string) error {
+	return &ConflictError{
+		Reason: reason,
+	}
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("ConflictError: %s", e.Reason)
+}
+
+func IsConflictError(err error) bool {
+	if _, ok := err.(*ConflictError); ok {
+		return true
+	}
+	return false
+}
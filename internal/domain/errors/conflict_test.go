@@ -0,0 +1,19 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+func TestConflictError(t *testing.T) {
+	err := e.NewConflictError("item is already reserved")
+
+	assert.Equal(t, "ConflictError: item is already reserved", err.Error())
+	assert.True(t, e.IsConflictError(err))
+	assert.False(t, e.IsConflictError(e.NewForbiddenError("email address is not verified")))
+	assert.False(t, e.IsConflictError(fmt.Errorf("some other error")))
+	assert.False(t, e.IsConflictError(nil))
+}
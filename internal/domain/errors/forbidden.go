@@ -0,0 +1,25 @@
+package errors
+
+import "fmt"
+
+// ForbiddenError means the customer is authenticated but can't perform the action yet
+type ForbiddenError struct {
+	Reason string `json:"reason"`
+}
+
+func NewForbiddenError(reason string) error {
+	return &ForbiddenError{
+		Reason: reason,
+	}
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("ForbiddenError: %s", e.Reason)
+}
+
+func IsForbiddenError(err error) bool {
+	if _, ok := err.(*ForbiddenError); ok {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+func TestForbiddenError(t *testing.T) {
+	err := e.NewForbiddenError("email address is not verified")
+
+	assert.Equal(t, "ForbiddenError: email address is not verified", err.Error())
+	assert.True(t, e.IsForbiddenError(err))
+	assert.False(t, e.IsForbiddenError(e.NewUnauthorizedError()))
+	assert.False(t, e.IsForbiddenError(fmt.Errorf("some other error")))
+	assert.False(t, e.IsForbiddenError(nil))
+}
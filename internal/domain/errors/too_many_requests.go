@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// TooManyRequestsError means the caller is throttled, RetryAfter tells when it may try again
+type TooManyRequestsError struct {
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+func NewTooManyRequestsError(retryAfter time.Duration) error {
+	return &TooManyRequestsError{
+		RetryAfter: retryAfter,
+	}
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("TooManyRequestsError: too many attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+func IsTooManyRequestsError(err error) bool {
+	if _, ok := err.(*TooManyRequestsError); ok {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+func TestTooManyRequestsError(t *testing.T) {
+	err := e.NewTooManyRequestsError(90*time.Second + 300*time.Millisecond)
+
+	assert.Equal(t, "TooManyRequestsError: too many attempts, retry in 1m30s", err.Error())
+	assert.True(t, e.IsTooManyRequestsError(err))
+	assert.Equal(t, 90*time.Second+300*time.Millisecond, err.(*e.TooManyRequestsError).RetryAfter)
+	assert.False(t, e.IsTooManyRequestsError(e.NewUnauthorizedError()))
+	assert.False(t, e.IsTooManyRequestsError(fmt.Errorf("some other error")))
+	assert.False(t, e.IsTooManyRequestsError(nil))
+}
@@ -1,15 +1,22 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type ValidationError struct {
 	Field string `json:"field"`
 	Err   string `json:"error"`
 }
 
-// IsValidationError checks if the given error is a ValidationError.
+// ValidationErrors reports every rule a value failed at once instead of only the first one
+type ValidationErrors []*ValidationError
+
+// IsValidationError checks if the given error is a ValidationError or a ValidationErrors list.
 func IsValidationError(err error) bool {
-	if _, ok := err.(*ValidationError); ok {
+	switch err.(type) {
+	case *ValidationError, ValidationErrors:
 		return true
 	}
 	return false
@@ -25,3 +32,23 @@ func NewRequiredFieldError(field string) error {
 		Err:   "is required",
 	}
 }
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewValidationErrors returns nil when errs is empty, the single error when there is
+// only one and a ValidationErrors list otherwise
+func NewValidationErrors(errs []*ValidationError) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return ValidationErrors(errs)
+}
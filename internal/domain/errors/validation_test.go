@@ -19,6 +19,11 @@ func TestIsValidationError(t *testing.T) {
 			err:  e.NewRequiredFieldError("email"),
 			want: true,
 		},
+		{
+			name: "ValidationErrors",
+			err:  e.ValidationErrors{{Field: "password", Err: "is too short"}},
+			want: true,
+		},
 		{
 			name: "not ValidationError",
 			err:  fmt.Errorf("some other error"),
@@ -64,3 +69,15 @@ func TestValidationErrorMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestNewValidationErrors(t *testing.T) {
+	short := &e.ValidationError{Field: "password", Err: "is too short"}
+	common := &e.ValidationError{Field: "password", Err: "is too common"}
+
+	assert.NoError(t, e.NewValidationErrors(nil))
+	assert.Equal(t, short, e.NewValidationErrors([]*e.ValidationError{short}))
+
+	err := e.NewValidationErrors([]*e.ValidationError{short, common})
+	assert.Equal(t, e.ValidationErrors{short, common}, err)
+	assert.Equal(t, "ValidationError field 'password' is too short; ValidationError field 'password' is too common", err.Error())
+}
@@ -0,0 +1,18 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/login_throttle_mock.go -package=mocks -source ./login_throttle.go
+
+package domain
+
+import "context"
+
+// LoginThrottler slows down and then locks repeated failed logins by email and by client IP
+type LoginThrottler interface {
+	// Check returns a TooManyRequestsError while the email or the IP is blocked
+	Check(ctx context.Context, email string, ip string) error
+	RegisterFailure(ctx context.Context, email string, ip string) error
+	RegisterSuccess(ctx context.Context, email string) error
+}
+
+// AccountUnlocker lifts the lockout of an email before it expires, e.g. after a password reset
+type AccountUnlocker interface {
+	Unlock(ctx context.Context, email string) error
+}
@@ -0,0 +1,15 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/mailer_mock.go -package=mocks -source ./mailer.go
+
+package domain
+
+import "context"
+
+type MailMessage struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message MailMessage) error
+}
@@ -0,0 +1,106 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/mfa_mock.go -package=mocks -source ./mfa.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CustomerMFA is the TOTP factor of a customer, Secret holds the encrypted secret
+// and EnabledAt stays zero until the enrollment is confirmed with a valid code
+type CustomerMFA struct {
+	CustomerID string    `json:"customer_id"`
+	Secret     string    `json:"-"`
+	LastStep   int64     `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	EnabledAt  time.Time `json:"enabled_at"`
+}
+
+func (m *CustomerMFA) Enabled() bool {
+	return m != nil && !m.EnabledAt.IsZero()
+}
+
+// RecoveryCode is a single use code that replaces a TOTP code when the device is lost
+type RecoveryCode struct {
+	ID         string    `json:"id"`
+	CustomerID string    `json:"customer_id"`
+	CodeHash   string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UsedAt     time.Time `json:"used_at"`
+}
+
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth:// URI authenticator apps read from a QR code
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TOTP implements RFC 6238 time based one time passwords
+type TOTP interface {
+	GenerateSecret() (string, error)
+	ProvisioningURI(secret string, accountName string) string
+	// Validate returns the time step the code belongs to so callers can reject a code used twice
+	Validate(secret string, code string, at time.Time) (int64, bool)
+}
+
+// MFAChallenger is called after the first factor succeeded, it returns nil when the customer
+// has no second factor, otherwise tokens holding only the MFA pending token. A customer deleted
+// during the grace period is passed as is, it is restored once the second factor succeeds and
+// callers restore it themselves only when Challenge returns nil
+type MFAChallenger interface {
+	Challenge(ctx context.Context, customer *Customer) (*AuthTokens, error)
+}
+
+type SecondFactorVerifier interface {
+	// VerifySecondFactor accepts either a TOTP code or an unused recovery code
+	VerifySecondFactor(ctx context.Context, customerID string, code string) error
+}
+
+// Usecases
+
+type VerifyMFAUC interface {
+	VerifyMFA(ctx context.Context, mfaToken string, code string, client ClientInfo) (*AuthTokens, error)
+}
+
+type TOTPEnrollmentUC interface {
+	StartEnrollment(ctx context.Context, currentCustomerID string) (*MFAEnrollment, error)
+	// ConfirmEnrollment enables the factor and returns the recovery codes, they are only shown once
+	ConfirmEnrollment(ctx context.Context, currentCustomerID string, code string) ([]string, error)
+	Disable(ctx context.Context, currentCustomerID string, code string) error
+}
+
+// Repositories
+
+// Save creates or replaces the factor of the customer
+type SaveCustomerMFARepository interface {
+	Save(ctx context.Context, mfa *CustomerMFA) error
+}
+
+type GetCustomerMFARepository interface {
+	GetByCustomerID(ctx context.Context, customerID string) (*CustomerMFA, error)
+}
+
+type EnableCustomerMFARepository interface {
+	Enable(ctx context.Context, customerID string, enabledAt time.Time) error
+}
+
+// MarkStepUsed stores the last accepted time step, it returns false when the step
+// is not newer than the last one so a code can't be replayed
+type MarkTOTPStepUsedRepository interface {
+	MarkStepUsed(ctx context.Context, customerID string, step int64) (bool, error)
+}
+
+// Delete removes the factor and the recovery codes of the customer
+type DeleteCustomerMFARepository interface {
+	Delete(ctx context.Context, customerID string) error
+}
+
+type ReplaceRecoveryCodesRepository interface {
+	ReplaceRecoveryCodes(ctx context.Context, customerID string, codes []RecoveryCode) error
+}
+
+// ConsumeRecoveryCode atomically marks an unused code as used, it returns false when no such code exists
+type ConsumeRecoveryCodeRepository interface {
+	ConsumeRecoveryCode(ctx context.Context, customerID string, codeHash string, usedAt time.Time) (bool, error)
+}
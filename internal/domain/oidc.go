@@ -0,0 +1,59 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/oidc_mock.go -package=mocks -source ./oidc.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// OIDCIdentity links an account at an OpenID Connect provider to a customer
+type OIDCIdentity struct {
+	ID         string    `json:"id"`
+	CustomerID string    `json:"customer_id"`
+	Issuer     string    `json:"issuer"`
+	Subject    string    `json:"subject"`
+	Email      string    `json:"email"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OIDCClaims are the claims read from a verified ID token
+type OIDCClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCProvider talks to a single OpenID Connect provider using the authorization code flow with PKCE
+type OIDCProvider interface {
+	Issuer() string
+	// AuthCodeURL returns the provider URL the customer must be redirected to, the S256
+	// challenge of codeVerifier is sent along with it
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	// Exchange redeems the code and returns the claims of the ID token once its signature,
+	// issuer, audience, expiration and nonce were checked
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*OIDCClaims, error)
+}
+
+// Usecases
+
+type StartOIDCLoginUC interface {
+	StartLogin(ctx context.Context) (string, error)
+}
+
+// Repositories
+
+type OIDCIdentityCreationRepository interface {
+	Create(ctx context.Context, identity *OIDCIdentity) error
+}
+
+type GetOIDCIdentityRepository interface {
+	GetByIssuerAndSubject(ctx context.Context, issuer string, subject string) (*OIDCIdentity, error)
+}
+
+// ListByCustomerID returns the provider accounts linked to the customer, oldest first
+type ListOIDCIdentitiesRepository interface {
+	ListByCustomerID(ctx context.Context, customerID string) ([]*OIDCIdentity, error)
+}
@@ -0,0 +1,73 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/one_time_token_mock.go -package=mocks -source ./one_time_token.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type OneTimeTokenPurpose string
+
+const (
+	OneTimeTokenPasswordReset     OneTimeTokenPurpose = "password_reset"
+	OneTimeTokenEmailVerification OneTimeTokenPurpose = "email_verification"
+	OneTimeTokenMagicLink         OneTimeTokenPurpose = "magic_link"
+)
+
+// OneTimeToken is a single use, time limited secret sent to the customer by email,
+// only the hash of the secret is stored
+type OneTimeToken struct {
+	ID         string              `json:"id"`
+	CustomerID string              `json:"customer_id"`
+	Purpose    OneTimeTokenPurpose `json:"purpose"`
+	TokenHash  string              `json:"-"`
+	CreatedAt  time.Time           `json:"created_at"`
+	ExpiresAt  time.Time           `json:"expires_at"`
+	UsedAt     time.Time           `json:"used_at"`
+}
+
+// OneTimeTokenRequestThrottler limits how often an email and a client IP can ask for a token of the same
+// purpose, so the endpoints can't be used to flood a mailbox or to probe emails in bulk
+type OneTimeTokenRequestThrottler interface {
+	// Allow counts the request and returns a TooManyRequestsError once the email or the IP is over its limit
+	Allow(ctx context.Context, purpose OneTimeTokenPurpose, email string, ip string) error
+}
+
+// Usecases
+
+// RequestPasswordResetUC emails a reset link in the background, the response is the same whether the
+// email is registered or not
+type RequestPasswordResetUC interface {
+	RequestPasswordReset(ctx context.Context, email string, ip string) error
+}
+
+type ResetPasswordUC interface {
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+}
+
+// RequestMagicLinkUC emails a single use login link in the background, the link is redeemed through an Authenticator
+type RequestMagicLinkUC interface {
+	RequestMagicLink(ctx context.Context, email string, ip string) error
+}
+
+// Repositories
+
+type OneTimeTokenCreationRepository interface {
+	Create(ctx context.Context, token *OneTimeToken) error
+}
+
+// GetActive returns the token only if it was not used and did not expire at the given time
+type ActiveOneTimeTokenRepository interface {
+	GetActive(ctx context.Context, purpose OneTimeTokenPurpose, tokenHash string, now time.Time) (*OneTimeToken, error)
+}
+
+// Consume atomically marks an active token as used, it returns nil when the token
+// was already used, expired or does not exist
+type ConsumeOneTimeTokenRepository interface {
+	Consume(ctx context.Context, purpose OneTimeTokenPurpose, tokenHash string, now time.Time) (*OneTimeToken, error)
+}
+
+type InvalidateOneTimeTokensRepository interface {
+	InvalidateForCustomer(ctx context.Context, customerID string, purpose OneTimeTokenPurpose, now time.Time) error
+}
@@ -0,0 +1,25 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/password_mock.go -package=mocks -source ./password.go
+
+package domain
+
+type PasswordPolicyConfig struct {
+	MinLength int
+	MaxLength int
+	// MinCharClasses is how many of lowercase, uppercase, digits and symbols a password must mix
+	MinCharClasses int
+	// BlockPersonalInfo rejects passwords containing the customer's name or email
+	BlockPersonalInfo bool
+}
+
+// PasswordPolicy validates a candidate password for the given customer,
+// customer can be partially filled when the account does not exist yet
+type PasswordPolicy interface {
+	Validate(password string, customer *Customer) error
+}
+
+// BreachedPasswordRange follows the k-anonymity model of breached password lists, it receives
+// the first 5 hex characters of a password SHA-1 and returns the remaining 35 of every breached
+// password sharing that prefix, so the full hash never has to leave the caller
+type BreachedPasswordRange interface {
+	Range(prefix string) ([]string, error)
+}
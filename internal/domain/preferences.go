@@ -0,0 +1,87 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/preferences_mock.go -package=mocks -source ./preferences.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type WishlistVisibility string
+
+const (
+	// WishlistVisibilityPrivate stops the customer from creating share links and hides the wishlists
+	// from the ones already created, they work again if the visibility goes back to shared
+	WishlistVisibilityPrivate WishlistVisibility = "private"
+	// WishlistVisibilityShared lets the customer create share links, anyone holding one can read the wishlist
+	WishlistVisibilityShared WishlistVisibility = "shared"
+)
+
+// NotificationSettings are opt-ins, every notification is off until the customer enables it
+type NotificationSettings struct {
+	PriceDrops   bool `json:"price_drops"`
+	BackInStock  bool `json:"back_in_stock"`
+	WeeklyDigest bool `json:"weekly_digest"`
+}
+
+// Preferences of a customer. Locale and Currency are only stored for clients to format what they show,
+// the API answers in English and returns prices as the product catalog gives them
+type Preferences struct {
+	CustomerID string `json:"customer_id"`
+	// Locale is a language tag like en or pt-BR
+	Locale string `json:"locale"`
+	// Currency is an ISO 4217 code like USD
+	Currency           string               `json:"currency"`
+	WishlistVisibility WishlistVisibility   `json:"wishlist_visibility"`
+	Notifications      NotificationSettings `json:"notifications"`
+	// RevealReservations shows the owner which items of their shared wishlists were reserved and by whom
+	RevealReservations bool      `json:"reveal_reservations"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// DefaultPreferences applies to customers who never saved theirs, wishlists are shared by default
+// since customers could share them before preferences existed
+func DefaultPreferences(customerID string) *Preferences {
+	return &Preferences{
+		CustomerID:         customerID,
+		Locale:             "en-US",
+		Currency:           "USD",
+		WishlistVisibility: WishlistVisibilityShared,
+	}
+}
+
+type PreferencesUpdate struct {
+	Locale             string
+	Currency           string
+	WishlistVisibility WishlistVisibility
+	Notifications      NotificationSettings
+	RevealReservations bool
+}
+
+// PreferencesReader gives other usecases the preferences of a customer, falling back to the
+// defaults, without any access check
+type PreferencesReader interface {
+	GetCustomerPreferences(ctx context.Context, customerID string) (*Preferences, error)
+}
+
+// Usecases
+
+type GetPreferencesUC interface {
+	GetPreferences(ctx context.Context, currentCustomerID string, customerID string) (*Preferences, error)
+}
+
+// UpdatePreferencesUC replaces every preference at once
+type UpdatePreferencesUC interface {
+	UpdatePreferences(ctx context.Context, currentCustomerID string, customerID string, data PreferencesUpdate) (*Preferences, error)
+}
+
+// Repositories
+
+// GetByCustomerID returns nil when the customer never saved preferences
+type GetPreferencesRepository interface {
+	GetByCustomerID(ctx context.Context, customerID string) (*Preferences, error)
+}
+
+type SavePreferencesRepository interface {
+	Save(ctx context.Context, preferences *Preferences) error
+}
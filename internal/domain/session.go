@@ -0,0 +1,83 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/session_mock.go -package=mocks -source ./session.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Session is a login on a device, its ID is the refresh token family ID carried by the
+// `sid` claim so every token issued for the login can be tied back to it
+type Session struct {
+	ID         string `json:"id"`
+	CustomerID string `json:"customer_id"`
+	// TokenID is the ID of the last access token issued for the session
+	TokenID   string    `json:"token_id"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+	// LastSeenAt is the last refresh or request made with an access token of the session,
+	// requests only move it forward once every few minutes
+	LastSeenAt time.Time `json:"last_seen_at"`
+	RevokedAt  time.Time `json:"revoked_at"`
+}
+
+// ClientInfo describes the device a login comes from
+type ClientInfo struct {
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+// SessionActivityRecorder keeps LastSeenAt of a session current while its access tokens are used
+type SessionActivityRecorder interface {
+	RecordActivity(ctx context.Context, sessionID string) error
+}
+
+// Usecases
+
+type ListSessionsUC interface {
+	ListSessions(ctx context.Context, currentCustomerID string, customerID string) ([]*Session, error)
+}
+
+type RevokeSessionUC interface {
+	RevokeSession(ctx context.Context, currentCustomerID string, customerID string, sessionID string) error
+}
+
+// Repositories
+
+type SessionCreationRepository interface {
+	Create(ctx context.Context, session *Session) error
+}
+
+// Touch records a new access token for the session, e.g. after a refresh
+type TouchSessionRepository interface {
+	Touch(ctx context.Context, id string, tokenID string, lastSeenAt time.Time) error
+}
+
+// MarkSeen moves LastSeenAt forward without changing the access token of the session
+type MarkSessionSeenRepository interface {
+	MarkSeen(ctx context.Context, id string, seenAt time.Time) error
+}
+
+type GetSessionRepository interface {
+	GetByID(ctx context.Context, id string) (*Session, error)
+}
+
+// ListActiveByCustomerID returns the sessions that were not revoked, most recently seen first
+type ListSessionsRepository interface {
+	ListActiveByCustomerID(ctx context.Context, customerID string) ([]*Session, error)
+}
+
+// ListByCustomerID returns every session of the customer, revoked ones included, oldest first
+type ListAllSessionsRepository interface {
+	ListByCustomerID(ctx context.Context, customerID string) ([]*Session, error)
+}
+
+type RevokeSessionRepository interface {
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+}
+
+type RevokeCustomerSessionsRepository interface {
+	RevokeAllForCustomer(ctx context.Context, customerID string, revokedAt time.Time) error
+}
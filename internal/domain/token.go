@@ -0,0 +1,104 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/token_mock.go -package=mocks -source ./token.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TokenClaims are the registered claims carried by every access token plus the
+// serialized customer data the handlers read from the request context.
+type TokenClaims struct {
+	ID        string    `json:"jti"`
+	Subject   string    `json:"sub"`
+	SessionID string    `json:"sid"`
+	Role      Role      `json:"role"`
+	Data      string    `json:"data"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+type RefreshToken struct {
+	ID         string    `json:"id"`
+	CustomerID string    `json:"customer_id"`
+	FamilyID   string    `json:"family_id"`
+	TokenHash  string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UsedAt     time.Time `json:"used_at"`
+	RevokedAt  time.Time `json:"revoked_at"`
+}
+
+type TokenSigner interface {
+	Sign(claims TokenClaims) (string, error)
+}
+
+type TokenVerifier interface {
+	Verify(token string) (*TokenClaims, error)
+}
+
+// TokenGenerator creates opaque, unguessable secrets such as refresh tokens
+type TokenGenerator interface {
+	Generate() (string, error)
+}
+
+// TokenRevoker puts tokens on a denylist until they would have expired anyway
+type TokenRevoker interface {
+	// RevokeToken revokes the given access token and ends its session
+	RevokeToken(ctx context.Context, claims *TokenClaims) error
+	// RevokeSession revokes the refresh token family and every access token carrying the session ID
+	RevokeSession(ctx context.Context, sessionID string) error
+	// RevokeAllForCustomer revokes every access and refresh token issued to the customer so far
+	RevokeAllForCustomer(ctx context.Context, customerID string) error
+}
+
+type RevokedTokenChecker interface {
+	IsRevoked(ctx context.Context, claims *TokenClaims) (bool, error)
+}
+
+// JSONWebKey is the public part of a token signing key as described by RFC 7517
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicKeyProvider exposes the keys other services need to verify our access tokens
+type PublicKeyProvider interface {
+	PublicKeys() JSONWebKeySet
+}
+
+// repositories
+
+type RefreshTokenCreationRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+}
+
+type RefreshTokenByHashRepository interface {
+	GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+}
+
+// MarkUsed flags the token as rotated, it returns false when the token was already used
+// so callers can detect concurrent or repeated use of the same refresh token
+type MarkRefreshTokenUsedRepository interface {
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
+
+type RevokeRefreshTokenFamilyRepository interface {
+	RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+}
+
+type RevokeCustomerRefreshTokensRepository interface {
+	RevokeAllForCustomer(ctx context.Context, customerID string, revokedAt time.Time) error
+}
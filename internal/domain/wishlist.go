@@ -1,20 +1,65 @@
 //go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/wishlist_mock.go -package=mocks -source ./wishlist.go
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+const (
+	DefaultWishlistItemQuantity = 1
+	MaxWishlistItemNoteLength   = 500
+)
 
 type Wishlist struct {
-	ID         string   `json:"id"`
-	CustomerId string   `json:"customer_id"`
-	Title      string   `json:"title"`
-	Items      []string `json:"items"`
+	ID         string         `json:"id"`
+	CustomerId string         `json:"customer_id"`
+	Title      string         `json:"title"`
+	Items      []WishlistItem `json:"items"`
+}
+
+// WishlistItem is a product in a wishlist along with what the customer told us about it,
+// items are shown ordered by Position
+type WishlistItem struct {
+	ProductID string    `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	Priority  int       `json:"priority"`
+	Note      string    `json:"note"`
+	AddedAt   time.Time `json:"added_at"`
+	Position  int       `json:"position"`
+}
+
+func (w *Wishlist) ProductIDs() []string {
+	ids := make([]string, 0, len(w.Items))
+	for _, item := range w.Items {
+		ids = append(ids, item.ProductID)
+	}
+	return ids
+}
+
+// Item returns the item of the product or nil when the product is not in the wishlist
+func (w *Wishlist) Item(productID string) *WishlistItem {
+	for i := range w.Items {
+		if w.Items[i].ProductID == productID {
+			return &w.Items[i]
+		}
+	}
+	return nil
 }
 
 type FullfilledWishlist struct {
 	ID       string
 	Customer *OutgoingCustomer
 	Title    string
-	Items    []Product
+	Items    []FullfilledWishlistItem
+}
+
+// FullfilledWishlistItem is the item metadata next to the resolved product
+type FullfilledWishlistItem struct {
+	WishlistItem
+	Product Product `json:"product"`
+	// Reservation is only filled on shared views
+	Reservation *ReservationStatus `json:"reservation,omitempty"`
 }
 
 // Usecases
@@ -39,6 +84,16 @@ type UpdateWishListUseCase interface {
 	UpdateWishlist(ctx context.Context, currentCustomerId string, wishlist *Wishlist) error
 }
 
+// AddWishlistItemUseCase adds a single product, adding a product already in the wishlist does nothing
+// and keeps the item as it was
+type AddWishlistItemUseCase interface {
+	AddWishlistItem(ctx context.Context, currentCustomerId string, customerId string, wishlistId string, item WishlistItem) error
+}
+
+type RemoveWishlistItemUseCase interface {
+	RemoveWishlistItem(ctx context.Context, currentCustomerId string, customerId string, wishlistId string, productId string) error
+}
+
 // Repositories
 type WishlistCreationRepository interface {
 	Create(ctx context.Context, wishlist *Wishlist) error
@@ -60,6 +115,13 @@ type UpdateWishlistRepository interface {
 	Update(ctx context.Context, wishlist *Wishlist) error
 }
 
+// AddItem and RemoveItem change the items in a single statement so concurrent edits are not lost,
+// they report whether the wishlist changed. AddItem places the item after the last one
+type WishlistItemRepository interface {
+	AddItem(ctx context.Context, wishlistId string, customerId string, item *WishlistItem) (bool, error)
+	RemoveItem(ctx context.Context, wishlistId string, customerId string, productId string) (bool, error)
+}
+
 type DeleteWishlistRepository interface {
 	DeleteWishlist(ctx context.Context, wishlistId string) error
 }
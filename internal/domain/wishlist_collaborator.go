@@ -0,0 +1,105 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/wishlist_collaborator_mock.go -package=mocks -source ./wishlist_collaborator.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type CollaboratorRole string
+
+const (
+	CollaboratorRoleViewer CollaboratorRole = "viewer"
+	CollaboratorRoleEditor CollaboratorRole = "editor"
+)
+
+// CollaboratorRolePermissions lists what a collaborator may do on the wishlist they were invited to,
+// deleting the wishlist and managing its collaborators is left to the owner
+var CollaboratorRolePermissions = map[CollaboratorRole][]Permission{
+	CollaboratorRoleViewer: {PermissionWishlistRead},
+	CollaboratorRoleEditor: {PermissionWishlistRead, PermissionWishlistWrite},
+}
+
+func (r CollaboratorRole) Valid() bool {
+	_, ok := CollaboratorRolePermissions[r]
+	return ok
+}
+
+func (r CollaboratorRole) Can(permission Permission) bool {
+	for _, granted := range CollaboratorRolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WishlistCollaborator is a customer invited to a wishlist of someone else,
+// the role only applies once the invitation is accepted
+type WishlistCollaborator struct {
+	WishlistID string           `json:"wishlist_id"`
+	CustomerID string           `json:"customer_id"`
+	Role       CollaboratorRole `json:"role"`
+	InvitedAt  time.Time        `json:"invited_at"`
+	AcceptedAt *time.Time       `json:"accepted_at,omitempty"`
+}
+
+func (c *WishlistCollaborator) Accepted() bool {
+	return c.AcceptedAt != nil
+}
+
+// WishlistPolicy decides whether actorID may use permission on a wishlist of ownerID, accepted
+// collaborators get the permissions of their role and everyone else goes through Policy
+type WishlistPolicy interface {
+	AuthorizeWishlist(ctx context.Context, actorID string, permission Permission, ownerID string, wishlistID string) error
+}
+
+// Usecases
+
+// InviteCollaboratorUC invites a customer by email, inviting them again changes their role. Emails
+// that aren't registered are ignored without an error so the owner can't probe for customers
+type InviteCollaboratorUC interface {
+	InviteCollaborator(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, email string, role CollaboratorRole) error
+}
+
+// AcceptInvitationUC is called by the invited customer, customerID is the owner of the wishlist
+type AcceptInvitationUC interface {
+	AcceptInvitation(ctx context.Context, currentCustomerID string, customerID string, wishlistID string) (*WishlistCollaborator, error)
+}
+
+type ListCollaboratorsUC interface {
+	ListCollaborators(ctx context.Context, currentCustomerID string, customerID string, wishlistID string) ([]*WishlistCollaborator, error)
+}
+
+type RemoveCollaboratorUC interface {
+	RemoveCollaborator(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, collaboratorID string) error
+}
+
+// Repositories
+
+// Save inserts the collaborator or replaces the one of the same customer in the wishlist
+type SaveCollaboratorRepository interface {
+	Save(ctx context.Context, collaborator *WishlistCollaborator) error
+}
+
+// Get returns nil when the customer was never invited to the wishlist
+type GetCollaboratorRepository interface {
+	Get(ctx context.Context, wishlistID string, customerID string) (*WishlistCollaborator, error)
+}
+
+// ListByWishlistID returns pending and accepted collaborators, oldest invitation first
+type ListCollaboratorsRepository interface {
+	ListByWishlistID(ctx context.Context, wishlistID string) ([]*WishlistCollaborator, error)
+}
+
+// ListByCustomerID returns the invitations the customer received, pending and accepted, oldest first
+type ListCollaborationsRepository interface {
+	ListByCustomerID(ctx context.Context, customerID string) ([]*WishlistCollaborator, error)
+}
+
+// Remove returns false when the customer is not a collaborator of the wishlist
+type RemoveCollaboratorRepository interface {
+	Remove(ctx context.Context, wishlistID string, customerID string) (bool, error)
+}
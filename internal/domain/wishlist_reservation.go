@@ -0,0 +1,79 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/wishlist_reservation_mock.go -package=mocks -source ./wishlist_reservation.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WishlistReservation marks an item of a shared wishlist as being bought by someone, so two people
+// don't buy the same gift. It stops counting once it is released or expires
+type WishlistReservation struct {
+	ID         string `json:"id"`
+	WishlistID string `json:"wishlist_id"`
+	ProductID  string `json:"product_id"`
+	CustomerID string `json:"-"`
+	// Anonymous hides the name of who reserved the item from everyone else, including the owner
+	Anonymous  bool       `json:"anonymous"`
+	ReservedAt time.Time  `json:"reserved_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+// Expired reports whether the reservation stopped holding the item
+func (r *WishlistReservation) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// ReservationStatus is what a viewer of a shared wishlist sees of an item reservation
+type ReservationStatus struct {
+	Reserved bool `json:"reserved"`
+	// ReservedBy is the name of who reserved the item, empty for anonymous reservations
+	ReservedBy string `json:"reserved_by,omitempty"`
+	// ReservedByMe is set on the viewer's own reservations, which can be released
+	ReservedByMe bool       `json:"reserved_by_me"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// ReservationStatusReader gives the shared view the status of every item of the wishlist as seen by
+// the viewer, without any access check. It returns nil when the viewer can't see reservations, which
+// is the case of signed out viewers and of the owner unless they opted in
+type ReservationStatusReader interface {
+	GetReservationStatuses(ctx context.Context, viewerID string, wishlist *Wishlist) (map[string]*ReservationStatus, error)
+}
+
+// Usecases
+
+// ReserveWishlistItemUC reserves an item through a share token, the owner can't reserve their own items
+type ReserveWishlistItemUC interface {
+	ReserveWishlistItem(ctx context.Context, currentCustomerID string, token string, productID string, anonymous bool) (*WishlistReservation, error)
+}
+
+// ReleaseWishlistItemUC gives the item back, only who reserved it can release it
+type ReleaseWishlistItemUC interface {
+	ReleaseWishlistItem(ctx context.Context, currentCustomerID string, token string, productID string) error
+}
+
+// Repositories
+
+// Reserve returns a ConflictError when the item already has a reservation that wasn't released and
+// hasn't expired, including one stored at the same moment
+type ReserveWishlistItemRepository interface {
+	Reserve(ctx context.Context, reservation *WishlistReservation) error
+}
+
+// ListActiveByWishlistID returns the reservations that weren't released and haven't expired at now
+type ListWishlistReservationsRepository interface {
+	ListActiveByWishlistID(ctx context.Context, wishlistID string, now time.Time) ([]*WishlistReservation, error)
+}
+
+// ListByCustomerID returns every reservation the customer made, released and expired ones included, oldest first
+type ListCustomerReservationsRepository interface {
+	ListByCustomerID(ctx context.Context, customerID string) ([]*WishlistReservation, error)
+}
+
+// Release returns false when the customer has no active reservation for the item
+type ReleaseWishlistItemRepository interface {
+	Release(ctx context.Context, wishlistID string, productID string, customerID string, releasedAt time.Time) (bool, error)
+}
@@ -0,0 +1,71 @@
+//go:generate mockgen --build_flags=--mod=mod -destination=../../mock/domain/wishlist_share_mock.go -package=mocks -source ./wishlist_share.go
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WishlistShare lets anyone holding the token read the wishlist without signing in,
+// only a hash of the token is stored
+type WishlistShare struct {
+	ID         string     `json:"id"`
+	WishlistID string     `json:"wishlist_id"`
+	CustomerID string     `json:"customer_id"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Expired reports whether the share stopped working, shares without an expiry never expire
+func (s *WishlistShare) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// CreatedWishlistShare carries the plain token, it is only available in the creation response
+type CreatedWishlistShare struct {
+	WishlistShare
+	Token string `json:"token"`
+}
+
+// Usecases
+
+type CreateWishlistShareUC interface {
+	CreateWishlistShare(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, expiresAt *time.Time) (*CreatedWishlistShare, error)
+}
+
+type ListWishlistSharesUC interface {
+	ListWishlistShares(ctx context.Context, currentCustomerID string, customerID string, wishlistID string) ([]*WishlistShare, error)
+}
+
+type RevokeWishlistShareUC interface {
+	RevokeWishlistShare(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, shareID string) error
+}
+
+// ShowSharedWishlistUC resolves a share token to a read-only wishlist, unknown, revoked and
+// expired tokens are all reported as not found. currentCustomerID is empty for viewers who didn't sign in
+type ShowSharedWishlistUC interface {
+	ShowSharedWishlist(ctx context.Context, currentCustomerID string, token string) (*FullfilledWishlist, error)
+}
+
+// Repositories
+
+type WishlistShareCreationRepository interface {
+	Create(ctx context.Context, share *WishlistShare) error
+}
+
+// ListByWishlistID returns the active shares of the wishlist, newest first
+type ListWishlistSharesRepository interface {
+	ListByWishlistID(ctx context.Context, wishlistID string) ([]*WishlistShare, error)
+}
+
+// GetActiveByHash returns nil when no share that wasn't revoked matches the hash, expired shares are returned
+type WishlistShareByHashRepository interface {
+	GetActiveByHash(ctx context.Context, tokenHash string) (*WishlistShare, error)
+}
+
+// Revoke returns false when the wishlist has no active share with that id
+type RevokeWishlistShareRepository interface {
+	Revoke(ctx context.Context, wishlistID string, id string, revokedAt time.Time) (bool, error)
+}
@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+// AESGCMEncrypter encrypts secrets stored at rest (like TOTP secrets) with AES-256-GCM,
+// the random nonce is prepended to the ciphertext
+type AESGCMEncrypter struct {
+	aead cipher.AEAD
+}
+
+func NewAESGCMEncrypter(key []byte) (*AESGCMEncrypter, error) {
+	if len(key) != 32 {
+		return nil, errors.New("the encryption key must be 32 bytes long")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESGCMEncrypter{
+		aead: aead,
+	}, nil
+}
+
+func (a *AESGCMEncrypter) Encrypt(plainText string) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := a.aead.Seal(nonce, nonce, []byte(plainText), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (a *AESGCMEncrypter) Decrypt(cipherText string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(cipherText)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < a.aead.NonceSize() {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	nonce, data := sealed[:a.aead.NonceSize()], sealed[a.aead.NonceSize():]
+	plain, err := a.aead.Open(nil, nonce, data, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
@@ -0,0 +1,46 @@
+package adapter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/infra/adapter"
+)
+
+func TestAESGCMEncrypter(t *testing.T) {
+	encrypter, err := adapter.NewAESGCMEncrypter(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	cipherText, err := encrypter.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotContains(t, cipherText, "JBSWY3DPEHPK3PXP")
+
+	// the nonce is random so the same secret never encrypts to the same value
+	other, err := encrypter.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotEqual(t, cipherText, other)
+
+	plain, err := encrypter.Decrypt(cipherText)
+	assert.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plain)
+}
+
+func TestAESGCMEncrypter_Errors(t *testing.T) {
+	_, err := adapter.NewAESGCMEncrypter([]byte("too short"))
+	assert.Error(t, err)
+
+	encrypter, err := adapter.NewAESGCMEncrypter(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	cipherText, err := encrypter.Encrypt("secret")
+	require.NoError(t, err)
+
+	wrongKey, err := adapter.NewAESGCMEncrypter(bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+	_, err = wrongKey.Decrypt(cipherText)
+	assert.Error(t, err)
+
+	_, err = encrypter.Decrypt("bm90IGVub3VnaA")
+	assert.Error(t, err)
+}
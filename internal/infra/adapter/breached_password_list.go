@@ -0,0 +1,65 @@
+package adapter
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed breached_passwords.txt
+var bundledBreachedPasswords string
+
+// BreachedPasswordList is a local k-anonymity range source backed by a file of uppercase SHA-1
+// hashes, one per line and optionally followed by `:<count>` like the Have I Been Pwned download.
+// The whole list is kept in memory grouped by the 5 characters prefix.
+type BreachedPasswordList struct {
+	ranges map[string][]string
+}
+
+// NewBreachedPasswordList loads the list from path, the bundled list of common passwords is used when path is empty
+func NewBreachedPasswordList(path string) (*BreachedPasswordList, error) {
+	if path == "" {
+		return parseBreachedPasswords(strings.NewReader(bundledBreachedPasswords))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseBreachedPasswords(file)
+}
+
+func (l *BreachedPasswordList) Range(prefix string) ([]string, error) {
+	return l.ranges[strings.ToUpper(prefix)], nil
+}
+
+func parseBreachedPasswords(r io.Reader) (*BreachedPasswordList, error) {
+	list := &BreachedPasswordList{ranges: map[string][]string{}}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(entry, ":")
+		if len(hash) != 40 {
+			return nil, fmt.Errorf("breached password list line %d is not a SHA-1 hash", line)
+		}
+
+		hash = strings.ToUpper(hash)
+		list.ranges[hash[:5]] = append(list.ranges[hash[:5]], hash[5:])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -0,0 +1,45 @@
+package adapter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/infra/adapter"
+)
+
+func TestBreachedPasswordList_Range(t *testing.T) {
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	bundled, err := adapter.NewBreachedPasswordList("")
+	assert.NoError(t, err)
+
+	suffixes, err := bundled.Range("5baa6")
+	assert.NoError(t, err)
+	assert.Contains(t, suffixes, "1E4C9B93F3F0682250B6CF8331B7EE68FD8")
+
+	suffixes, err = bundled.Range("00000")
+	assert.NoError(t, err)
+	assert.Empty(t, suffixes)
+}
+
+func TestNewBreachedPasswordList(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.txt")
+	assert.NoError(t, os.WriteFile(valid, []byte("# comment\n\n5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8:3861493\n"), 0o600))
+
+	invalid := filepath.Join(dir, "invalid.txt")
+	assert.NoError(t, os.WriteFile(invalid, []byte("not-a-hash\n"), 0o600))
+
+	list, err := adapter.NewBreachedPasswordList(valid)
+	assert.NoError(t, err)
+	suffixes, _ := list.Range("5BAA6")
+	assert.Equal(t, []string{"1E4C9B93F3F0682250B6CF8331B7EE68FD8"}, suffixes)
+
+	_, err = adapter.NewBreachedPasswordList(invalid)
+	assert.Error(t, err)
+
+	_, err = adapter.NewBreachedPasswordList(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GoroutineRunner runs every task in its own goroutine, tasks are lost if the process stops
+// before they finish so they must leave a state that can be retried
+type GoroutineRunner struct {
+	timeout time.Duration
+}
+
+func NewGoroutineRunner(timeout time.Duration) *GoroutineRunner {
+	return &GoroutineRunner{
+		timeout: timeout,
+	}
+}
+
+func (r *GoroutineRunner) Go(task func(ctx context.Context)) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		defer cancel()
+
+		// a panicking task must not bring the API down
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				fmt.Printf("[goroutine_runner] ERROR background task panicked: %v\n", recovered)
+			}
+		}()
+
+		task(ctx)
+	}()
+}
@@ -0,0 +1,35 @@
+package adapter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// HMACSigner signs with HMAC-SHA256, only whoever holds the key can verify a signature
+type HMACSigner struct {
+	key []byte
+}
+
+func NewHMACSigner(key []byte) *HMACSigner {
+	return &HMACSigner{
+		key: key,
+	}
+}
+
+func (s *HMACSigner) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *HMACSigner) Verify(payload []byte, signature string) bool {
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,20 @@
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/infra/adapter"
+)
+
+func TestHMACSigner(t *testing.T) {
+	signer := adapter.NewHMACSigner([]byte("receipt-key"))
+
+	signature := signer.Sign([]byte("erasure:receipt_123"))
+	assert.Equal(t, signature, signer.Sign([]byte("erasure:receipt_123")))
+
+	assert.True(t, signer.Verify([]byte("erasure:receipt_123"), signature))
+	assert.False(t, signer.Verify([]byte("erasure:receipt_456"), signature))
+	assert.False(t, signer.Verify([]byte("erasure:receipt_123"), "not base64!"))
+	assert.False(t, adapter.NewHMACSigner([]byte("another-key")).Verify([]byte("erasure:receipt_123"), signature))
+}
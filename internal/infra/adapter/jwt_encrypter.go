@@ -1,23 +1,113 @@
 package adapter
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
 
 type JWTEncrypter struct {
-	secret string
+	secret     string
+	issuer     string
+	ttl        time.Duration
+	signingKey *JWTKey
+	// verificationKeys always starts with the signing key
+	verificationKeys []*JWTKey
+}
+
+type jwtClaims struct {
+	Data      string `json:"data"`
+	SessionID string `json:"sid,omitempty"`
+	Role      string `json:"role,omitempty"`
+	jwt.RegisteredClaims
 }
 
-func NewJWTEncrypter(secret string) *JWTEncrypter {
+// NewJWTEncrypter creates an HS256 token signer, ttl is used as the lifetime of
+// tokens created through Encrypt and issuer is both set and required on every token
+func NewJWTEncrypter(secret string, issuer string, ttl time.Duration) *JWTEncrypter {
 	return &JWTEncrypter{
 		secret: secret,
+		issuer: issuer,
+		ttl:    ttl,
+	}
+}
+
+// NewAsymmetricJWTEncrypter signs tokens with signingKey and accepts tokens signed by it or by
+// any of the verificationKeys. During a rotation the previous key stays in verificationKeys
+// until every token it signed has expired
+func NewAsymmetricJWTEncrypter(issuer string, ttl time.Duration, signingKey *JWTKey, verificationKeys ...*JWTKey) (*JWTEncrypter, error) {
+	if signingKey == nil || !signingKey.CanSign() {
+		return nil, errors.New("the signing key must be a private key")
+	}
+
+	keys := []*JWTKey{signingKey}
+	for _, key := range verificationKeys {
+		if key.ID != signingKey.ID {
+			keys = append(keys, key)
+		}
 	}
+
+	return &JWTEncrypter{
+		issuer:           issuer,
+		ttl:              ttl,
+		signingKey:       signingKey,
+		verificationKeys: keys,
+	}, nil
 }
+
 func (j *JWTEncrypter) Encrypt(plainText string) (string, error) {
-	claims := jwt.MapClaims{
-		"data": plainText,
+	now := time.Now()
+
+	return j.Sign(domain.TokenClaims{
+		ID:        uuid.NewString(),
+		Data:      plainText,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(j.ttl),
+	})
+}
+
+func (j *JWTEncrypter) Decrypt(cipherText string) (string, error) {
+	claims, err := j.Verify(cipherText)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Data, nil
+}
+
+func (j *JWTEncrypter) Sign(claims domain.TokenClaims) (string, error) {
+	registered := jwt.RegisteredClaims{
+		ID:       claims.ID,
+		Subject:  claims.Subject,
+		Issuer:   j.issuer,
+		IssuedAt: jwt.NewNumericDate(claims.IssuedAt),
+	}
+	if !claims.ExpiresAt.IsZero() {
+		registered.ExpiresAt = jwt.NewNumericDate(claims.ExpiresAt)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(j.secret))
+	tokenClaims := jwtClaims{
+		Data:             claims.Data,
+		SessionID:        claims.SessionID,
+		Role:             string(claims.Role),
+		RegisteredClaims: registered,
+	}
+
+	var signedToken string
+	var err error
+	if j.signingKey != nil {
+		token := jwt.NewWithClaims(j.signingKey.method, tokenClaims)
+		token.Header["kid"] = j.signingKey.ID
+		signedToken, err = token.SignedString(j.signingKey.privateKey)
+	} else {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims)
+		signedToken, err = token.SignedString([]byte(j.secret))
+	}
 
 	if err != nil {
 		return "", err
@@ -25,21 +115,80 @@ func (j *JWTEncrypter) Encrypt(plainText string) (string, error) {
 	return signedToken, nil
 }
 
-func (j *JWTEncrypter) Decrypt(cipherText string) (string, error) {
-	token, err := jwt.Parse(cipherText, func(token *jwt.Token) (interface{}, error) {
+// Verify checks the signature, expiration and issuer of the given token,
+// an expired token returns a TokenExpiredError so clients know they should refresh it
+func (j *JWTEncrypter) Verify(cipherText string) (*domain.TokenClaims, error) {
+	token, err := jwt.ParseWithClaims(cipherText, &jwtClaims{}, j.verificationKey)
+
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, e.NewTokenExpiredError()
+		}
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*jwtClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.NewValidationError("invalid token", jwt.ValidationErrorSignatureInvalid)
+	}
+
+	if !claims.VerifyIssuer(j.issuer, true) {
+		return nil, jwt.NewValidationError("invalid issuer", jwt.ValidationErrorIssuer)
+	}
+
+	out := &domain.TokenClaims{
+		ID:        claims.ID,
+		Subject:   claims.Subject,
+		SessionID: claims.SessionID,
+		Role:      domain.Role(claims.Role),
+		Data:      claims.Data,
+	}
+	if claims.IssuedAt != nil {
+		out.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		out.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	return out, nil
+}
+
+// verificationKey picks the key by the kid header, the algorithm must match the key
+// otherwise a public key could be used as an HMAC secret
+func (j *JWTEncrypter) verificationKey(token *jwt.Token) (interface{}, error) {
+	if j.signingKey == nil {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, jwt.NewValidationError("unexpected signing method", jwt.ValidationErrorSignatureInvalid)
 		}
 		return []byte(j.secret), nil
-	})
+	}
 
-	if err != nil {
-		return "", err
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range j.verificationKeys {
+		if key.ID != kid {
+			continue
+		}
+
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, jwt.NewValidationError("unexpected signing method", jwt.ValidationErrorSignatureInvalid)
+		}
+		return key.publicKey, nil
+	}
+
+	return nil, jwt.NewValidationError(fmt.Sprintf("unknown key %q", kid), jwt.ValidationErrorUnverifiable)
+}
+
+// PublicKeys lists every key tokens may be signed with, it is empty for HS256 since the secret can't be shared
+func (j *JWTEncrypter) PublicKeys() domain.JSONWebKeySet {
+	set := domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+	if j.signingKey == nil {
+		return set
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims["data"].(string), nil
+	for _, key := range j.verificationKeys {
+		set.Keys = append(set.Keys, key.JWK())
 	}
 
-	return "", jwt.NewValidationError("invalid token", jwt.ValidationErrorSignatureInvalid)
+	return set
 }
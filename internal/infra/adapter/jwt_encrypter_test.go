@@ -1,10 +1,24 @@
 package adapter_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
 	"github.com/ydoro/wishlist/internal/infra/adapter"
 )
 
@@ -37,7 +51,7 @@ func TestJWTEncrypter_Encrypt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			encrypter := adapter.NewJWTEncrypter(tt.secret)
+			encrypter := adapter.NewJWTEncrypter(tt.secret, "wishlist-api", time.Minute)
 			token, err := encrypter.Encrypt(tt.plainText)
 
 			if tt.wantErr {
@@ -62,6 +76,10 @@ func TestJWTEncrypter_Encrypt(t *testing.T) {
 			claims, ok := parsedToken.Claims.(jwt.MapClaims)
 			assert.True(t, ok)
 			assert.Equal(t, tt.plainText, claims["data"])
+			assert.Equal(t, "wishlist-api", claims["iss"])
+			assert.NotEmpty(t, claims["jti"])
+			assert.NotEmpty(t, claims["iat"])
+			assert.NotEmpty(t, claims["exp"])
 		})
 	}
 }
@@ -95,11 +113,11 @@ func TestJWTEncrypter_Decrypt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			encrypter := adapter.NewJWTEncrypter("mysecret")
+			encrypter := adapter.NewJWTEncrypter("mysecret", "wishlist-api", time.Minute)
 			token, err := encrypter.Encrypt(tt.plainText)
 			assert.NoError(t, err)
 
-			decrypter := adapter.NewJWTEncrypter(tt.secret)
+			decrypter := adapter.NewJWTEncrypter(tt.secret, "wishlist-api", time.Minute)
 			decrypted, err := decrypter.Decrypt(token)
 
 			if tt.wantErr {
@@ -113,3 +131,258 @@ func TestJWTEncrypter_Decrypt(t *testing.T) {
 		})
 	}
 }
+
+func TestJWTEncrypter_Verify(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		signer      *adapter.JWTEncrypter
+		claims      domain.TokenClaims
+		expectedErr func(error) bool
+	}{
+		{
+			name:   "valid token",
+			signer: adapter.NewJWTEncrypter("mysecret", "wishlist-api", time.Minute),
+			claims: domain.TokenClaims{
+				ID:        "jti_123",
+				Subject:   "customer_123",
+				SessionID: "session_123",
+				Role:      domain.RoleAdmin,
+				Data:      "hello world",
+				IssuedAt:  now,
+				ExpiresAt: now.Add(time.Minute),
+			},
+		},
+		{
+			name:   "expired token",
+			signer: adapter.NewJWTEncrypter("mysecret", "wishlist-api", time.Minute),
+			claims: domain.TokenClaims{
+				ID:        "jti_123",
+				Subject:   "customer_123",
+				IssuedAt:  now.Add(-2 * time.Hour),
+				ExpiresAt: now.Add(-time.Hour),
+			},
+			expectedErr: e.IsTokenExpiredError,
+		},
+		{
+			name:   "different issuer",
+			signer: adapter.NewJWTEncrypter("mysecret", "someone-else", time.Minute),
+			claims: domain.TokenClaims{
+				ID:        "jti_123",
+				IssuedAt:  now,
+				ExpiresAt: now.Add(time.Minute),
+			},
+			expectedErr: func(err error) bool { return err != nil && !e.IsTokenExpiredError(err) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.signer.Sign(tt.claims)
+			assert.NoError(t, err)
+
+			verifier := adapter.NewJWTEncrypter("mysecret", "wishlist-api", time.Minute)
+			claims, err := verifier.Verify(token)
+
+			if tt.expectedErr != nil {
+				assert.True(t, tt.expectedErr(err))
+				assert.Nil(t, claims)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.claims.ID, claims.ID)
+			assert.Equal(t, tt.claims.Subject, claims.Subject)
+			assert.Equal(t, tt.claims.SessionID, claims.SessionID)
+			assert.Equal(t, tt.claims.Role, claims.Role)
+			assert.Equal(t, tt.claims.Data, claims.Data)
+			assert.Equal(t, tt.claims.ExpiresAt.Unix(), claims.ExpiresAt.Unix())
+		})
+	}
+}
+
+func writeKeyFile(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func newRSAKey(t *testing.T) *adapter.JWTKey {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key, err := adapter.LoadJWTKeyFile(writeKeyFile(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private)))
+	require.NoError(t, err)
+
+	return key
+}
+
+func newECKey(t *testing.T) (*adapter.JWTKey, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	key, err := adapter.LoadJWTKeyFile(writeKeyFile(t, "PRIVATE KEY", der))
+	require.NoError(t, err)
+
+	return key, private
+}
+
+func TestAsymmetricJWTEncrypter_SignAndVerify(t *testing.T) {
+	ecKey, _ := newECKey(t)
+	keys := map[string]*adapter.JWTKey{
+		"RS256": newRSAKey(t),
+		"ES256": ecKey,
+	}
+
+	for alg, key := range keys {
+		t.Run(alg, func(t *testing.T) {
+			encrypter, err := adapter.NewAsymmetricJWTEncrypter("wishlist-api", time.Minute, key)
+			require.NoError(t, err)
+
+			token, err := encrypter.Encrypt("hello world")
+			require.NoError(t, err)
+
+			parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, alg, parsed.Method.Alg())
+			assert.Equal(t, key.ID, parsed.Header["kid"])
+
+			data, err := encrypter.Decrypt(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "hello world", data)
+		})
+	}
+}
+
+func TestAsymmetricJWTEncrypter_Rotation(t *testing.T) {
+	oldKey := newRSAKey(t)
+	newKey, _ := newECKey(t)
+
+	oldEncrypter, err := adapter.NewAsymmetricJWTEncrypter("wishlist-api", time.Minute, oldKey)
+	require.NoError(t, err)
+	oldToken, err := oldEncrypter.Encrypt("issued before the rotation")
+	require.NoError(t, err)
+
+	rotated, err := adapter.NewAsymmetricJWTEncrypter("wishlist-api", time.Minute, newKey, oldKey)
+	require.NoError(t, err)
+
+	data, err := rotated.Decrypt(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "issued before the rotation", data)
+
+	newToken, err := rotated.Encrypt("issued after the rotation")
+	require.NoError(t, err)
+
+	// once the old key is dropped its tokens are rejected
+	dropped, err := adapter.NewAsymmetricJWTEncrypter("wishlist-api", time.Minute, newKey)
+	require.NoError(t, err)
+
+	_, err = dropped.Decrypt(oldToken)
+	assert.Error(t, err)
+
+	_, err = oldEncrypter.Decrypt(newToken)
+	assert.Error(t, err)
+}
+
+func TestAsymmetricJWTEncrypter_RejectsHMACWithPublicKey(t *testing.T) {
+	key, private := newECKey(t)
+	encrypter, err := adapter.NewAsymmetricJWTEncrypter("wishlist-api", time.Minute, key)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    "wishlist-api",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	token.Header["kid"] = key.ID
+	forged, err := token.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	require.NoError(t, err)
+
+	claims, err := encrypter.Verify(forged)
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestAsymmetricJWTEncrypter_RequiresPrivateSigningKey(t *testing.T) {
+	_, private := newECKey(t)
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	publicKey, err := adapter.LoadJWTKeyFile(writeKeyFile(t, "PUBLIC KEY", publicDER))
+	require.NoError(t, err)
+	assert.False(t, publicKey.CanSign())
+
+	_, err = adapter.NewAsymmetricJWTEncrypter("wishlist-api", time.Minute, publicKey)
+	assert.Error(t, err)
+}
+
+func TestJWTEncrypter_PublicKeys(t *testing.T) {
+	assert.Empty(t, adapter.NewJWTEncrypter("mysecret", "wishlist-api", time.Minute).PublicKeys().Keys)
+
+	signingKey, private := newECKey(t)
+	previousKey := newRSAKey(t)
+	encrypter, err := adapter.NewAsymmetricJWTEncrypter("wishlist-api", time.Minute, signingKey, previousKey)
+	require.NoError(t, err)
+
+	keys := encrypter.PublicKeys().Keys
+	require.Len(t, keys, 2)
+
+	assert.Equal(t, domain.JSONWebKey{
+		KeyType:   "EC",
+		KeyID:     signingKey.ID,
+		Use:       "sig",
+		Algorithm: "ES256",
+		Curve:     "P-256",
+		X:         base64.RawURLEncoding.EncodeToString(private.X.FillBytes(make([]byte, 32))),
+		Y:         base64.RawURLEncoding.EncodeToString(private.Y.FillBytes(make([]byte, 32))),
+	}, keys[0])
+
+	assert.Equal(t, "RSA", keys[1].KeyType)
+	assert.Equal(t, previousKey.ID, keys[1].KeyID)
+	assert.Equal(t, "RS256", keys[1].Algorithm)
+	assert.Equal(t, "AQAB", keys[1].E)
+	assert.NotEmpty(t, keys[1].N)
+}
+
+func TestParseJWTKey_Thumbprint(t *testing.T) {
+	// public key and thumbprint from RFC 7638 section 3.1
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	require.NoError(t, err)
+
+	der := x509.MarshalPKCS1PublicKey(&rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537})
+	key, err := adapter.ParseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", key.ID)
+}
+
+func TestParseJWTKey_Invalid(t *testing.T) {
+	_, err := adapter.ParseJWTKey([]byte("not a pem file"))
+	assert.Error(t, err)
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = adapter.ParseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(small)}))
+	assert.Error(t, err)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(p384)
+	require.NoError(t, err)
+	_, err = adapter.ParseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	assert.Error(t, err)
+}
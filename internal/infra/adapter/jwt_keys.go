@@ -0,0 +1,162 @@
+package adapter
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+// JWTKey is an asymmetric key used to sign or verify tokens, a key holding only the
+// public part can verify tokens but not sign them
+type JWTKey struct {
+	ID         string
+	method     jwt.SigningMethod
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+}
+
+// LoadJWTKeyFile reads an RSA or P-256 EC key from a PEM file, see ParseJWTKey
+func LoadJWTKeyFile(path string) (*JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParseJWTKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// ParseJWTKey parses a PEM encoded private (PKCS#1, PKCS#8 or SEC 1) or public (PKIX) key,
+// RSA keys sign with RS256 and P-256 keys with ES256. The key ID is the RFC 7638 thumbprint
+// of the public key so the same file always gets the same kid
+func ParseJWTKey(data []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &JWTKey{}
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.privateKey = signer
+		key.publicKey = signer.Public()
+	} else {
+		key.publicKey = parsed
+	}
+
+	switch pub := key.publicKey.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		key.method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 EC keys are supported")
+		}
+		key.method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key.publicKey)
+	}
+
+	key.ID, err = jwkThumbprint(key.JWK())
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (k *JWTKey) CanSign() bool {
+	return k.privateKey != nil
+}
+
+// JWK returns the public part of the key in JSON Web Key format
+func (k *JWTKey) JWK() domain.JSONWebKey {
+	jwk := domain.JSONWebKey{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.method.Alg(),
+	}
+
+	switch pub := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	}
+
+	return jwk
+}
+
+func jwkThumbprint(jwk domain.JSONWebKey) (string, error) {
+	// RFC 7638 only hashes the required members, in lexicographic order and without whitespace
+	var members any
+	switch jwk.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Curve, jwk.KeyType, jwk.X, jwk.Y}
+	default:
+		return "", fmt.Errorf("unsupported key type %q", jwk.KeyType)
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,43 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+// LogMailer writes every message to the given writer instead of delivering it,
+// it is meant for local development where no SMTP server is available
+type LogMailer struct {
+	from string
+	out  io.Writer
+	mu   sync.Mutex
+}
+
+func NewLogMailer(from string, out io.Writer) *LogMailer {
+	return &LogMailer{
+		from: from,
+		out:  out,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message domain.MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(
+		m.out,
+		"----- mail %s -----\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n-------------------\n",
+		time.Now().Format(time.RFC3339),
+		m.from,
+		message.To,
+		message.Subject,
+		message.Body,
+	)
+
+	return err
+}
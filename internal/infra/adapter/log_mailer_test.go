@@ -0,0 +1,28 @@
+package adapter_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/infra/adapter"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+	var out bytes.Buffer
+	mailer := adapter.NewLogMailer("no-reply@wishlist.local", &out)
+
+	err := mailer.Send(context.Background(), domain.MailMessage{
+		To:      "john@example.com",
+		Subject: "Reset your password",
+		Body:    "use this link",
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "From: no-reply@wishlist.local")
+	assert.Contains(t, out.String(), "To: john@example.com")
+	assert.Contains(t, out.String(), "Subject: Reset your password")
+	assert.Contains(t, out.String(), "use this link")
+}
@@ -1,26 +1,162 @@
 package adapter
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var errUnknownHashAlgorithm = errors.New("unknown password hash algorithm")
+
+// Argon2Params are the argon2id parameters new hashes are written with,
+// memory is in KiB
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP recommendation for argon2id
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// PasswordHasher writes argon2id hashes and verifies both argon2id and the bcrypt
+// hashes created before it, the algorithm is read from the encoded hash itself
 type PasswordHasher struct {
-	salt int
+	params Argon2Params
 }
 
-func NewPasswordHasher(salt int) *PasswordHasher {
+func NewPasswordHasher(params Argon2Params) *PasswordHasher {
 	return &PasswordHasher{
-		salt: salt,
+		params: params,
 	}
 }
 
-// Hash generates a hashed version of the given password using the bcrypt algorithm.
-// The salt value, which determines the computational cost of the hashing process, is
-// provided by the PasswordHasher instance. It returns the hashed password as a string
-// and any potential error encountered during the hashing process.
+// Hash generates an argon2id hash of the given password with a random salt. The result is
+// encoded in the PHC string format, `$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>`,
+// so the parameters it was created with can be read back when comparing it.
 func (h *PasswordHasher) Hash(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.salt)
-	return string(hash), err
+	if err := h.params.validate(); err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Iterations,
+		h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
 func (h *PasswordHasher) Compare(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if isBcrypt(hashedPassword) {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	}
+
+	params, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return errors.New("password does not match")
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether the hash was written by another algorithm or with
+// weaker parameters than the configured ones
+func (h *PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	params, _, _, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory < h.params.Memory ||
+		params.Iterations < h.params.Iterations ||
+		params.Parallelism < h.params.Parallelism ||
+		params.SaltLength < h.params.SaltLength ||
+		params.KeyLength < h.params.KeyLength
+}
+
+func (p Argon2Params) validate() error {
+	if p.Iterations < 1 || p.Parallelism < 1 || p.SaltLength < 8 || p.KeyLength < 16 {
+		return errors.New("invalid argon2id parameters")
+	}
+	if p.Memory < 8*uint32(p.Parallelism) {
+		return errors.New("argon2id memory must be at least 8KiB per lane")
+	}
+
+	return nil
+}
+
+func isBcrypt(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") ||
+		strings.HasPrefix(hashedPassword, "$2b$") ||
+		strings.HasPrefix(hashedPassword, "$2y$")
+}
+
+func decodeArgon2id(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, errUnknownHashAlgorithm
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	if err := params.validate(); err != nil {
+		return params, nil, nil, err
+	}
+
+	return params, salt, key, nil
 }
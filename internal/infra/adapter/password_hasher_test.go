@@ -1,35 +1,46 @@
 package adapter_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/ydoro/wishlist/internal/infra/adapter"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// testArgon2Params keeps the tests fast, production uses adapter.DefaultArgon2Params
+var testArgon2Params = adapter.Argon2Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
 func TestPasswordHasher_Hash(t *testing.T) {
 	cases := []struct {
 		name     string
 		password string
-		salt     int64
+		params   adapter.Argon2Params
 		wantErr  bool
 	}{
 		{
 			name:     "valid password",
 			password: "mysecretpassword",
-			salt:     10,
+			params:   testArgon2Params,
 			wantErr:  false,
 		},
 		{
 			name:     "should not return error on empty password",
 			password: "",
-			salt:     10,
+			params:   testArgon2Params,
 			wantErr:  false,
 		},
 		{
-			name:     "invalid salt",
+			name:     "invalid parameters",
 			password: "mysecretpassword",
-			salt:     int64(999999999999999999), // This is an invalid value for bcrypt
+			params:   adapter.Argon2Params{Memory: 1024, Iterations: 0, Parallelism: 1, SaltLength: 16, KeyLength: 32},
 			wantErr:  true,
 		},
 	}
@@ -37,14 +48,14 @@ func TestPasswordHasher_Hash(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			t.Parallel()
-			hasher := adapter.NewPasswordHasher(int(c.salt))
+			hasher := adapter.NewPasswordHasher(c.params)
 			hash, err := hasher.Hash(c.password)
 
 			if c.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.NotEmpty(t, hash)
+				assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
 				assert.NotEqual(t, c.password, hash)
 			}
 
@@ -89,7 +100,7 @@ func TestPasswordHasher_Compare(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			hasher := adapter.NewPasswordHasher(10)
+			hasher := adapter.NewPasswordHasher(testArgon2Params)
 
 			hashedPassword, err := hasher.Hash(tt.setupPassword)
 			assert.NoError(t, err)
@@ -104,3 +115,44 @@ func TestPasswordHasher_Compare(t *testing.T) {
 		})
 	}
 }
+
+func TestPasswordHasher_CompareBcrypt(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	hasher := adapter.NewPasswordHasher(testArgon2Params)
+
+	assert.NoError(t, hasher.Compare(string(legacy), "correctpassword"))
+	assert.Error(t, hasher.Compare(string(legacy), "wrongpassword"))
+	assert.Error(t, hasher.Compare("$md5$unknown", "correctpassword"))
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	current, err := adapter.NewPasswordHasher(testArgon2Params).Hash("password")
+	assert.NoError(t, err)
+
+	stronger := testArgon2Params
+	stronger.Iterations = 2
+
+	tests := []struct {
+		name   string
+		params adapter.Argon2Params
+		hash   string
+		want   bool
+	}{
+		{name: "bcrypt hash", params: testArgon2Params, hash: string(legacy), want: true},
+		{name: "unknown hash", params: testArgon2Params, hash: "plain", want: true},
+		{name: "same parameters", params: testArgon2Params, hash: current, want: false},
+		{name: "weaker than configured", params: stronger, hash: current, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := adapter.NewPasswordHasher(tt.params)
+			assert.Equal(t, tt.want, hasher.NeedsRehash(tt.hash))
+		})
+	}
+}
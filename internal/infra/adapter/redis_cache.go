@@ -45,3 +45,15 @@ func (c *redisCache) Get(ctx context.Context, key string) (string, error) {
 func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
 	return c.client.Del(ctx, keys...).Err()
 }
+
+func (c *redisCache) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	pipe := c.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
@@ -0,0 +1,26 @@
+package adapter
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+type SecureTokenGenerator struct {
+	size int
+}
+
+// NewSecureTokenGenerator creates a generator of url safe tokens built from size random bytes
+func NewSecureTokenGenerator(size int) *SecureTokenGenerator {
+	return &SecureTokenGenerator{
+		size: size,
+	}
+}
+
+func (g *SecureTokenGenerator) Generate() (string, error) {
+	buf := make([]byte, g.size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,30 @@
+package adapter
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+)
+
+// SHA256Hasher is a deterministic hasher meant for high entropy secrets (like refresh tokens)
+// that must be looked up by their hash, it MUST NOT be used for customer passwords
+type SHA256Hasher struct {
+}
+
+func NewSHA256Hasher() *SHA256Hasher {
+	return &SHA256Hasher{}
+}
+
+func (h *SHA256Hasher) Hash(value string) (string, error) {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (h *SHA256Hasher) Compare(hashedValue, value string) error {
+	hash, _ := h.Hash(value)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(hashedValue)) != 1 {
+		return errors.New("hash does not match the given value")
+	}
+	return nil
+}
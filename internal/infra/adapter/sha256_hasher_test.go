@@ -0,0 +1,34 @@
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/infra/adapter"
+)
+
+func TestSHA256Hasher(t *testing.T) {
+	hasher := adapter.NewSHA256Hasher()
+
+	hash, err := hasher.Hash("some-refresh-token")
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	again, _ := hasher.Hash("some-refresh-token")
+	assert.Equal(t, hash, again, "hash must be deterministic so it can be used for lookups")
+
+	assert.NoError(t, hasher.Compare(hash, "some-refresh-token"))
+	assert.Error(t, hasher.Compare(hash, "another-token"))
+}
+
+func TestSecureTokenGenerator_Generate(t *testing.T) {
+	generator := adapter.NewSecureTokenGenerator(32)
+
+	first, err := generator.Generate()
+	assert.NoError(t, err)
+	assert.Len(t, first, 43)
+
+	second, err := generator.Generate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
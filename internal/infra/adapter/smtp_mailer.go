@@ -0,0 +1,106 @@
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type SMTPMailerConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPMailer struct {
+	config SMTPMailerConfig
+}
+
+func NewSMTPMailer(config SMTPMailerConfig) *SMTPMailer {
+	return &SMTPMailer{
+		config: config,
+	}
+}
+
+// Send delivers the message through the configured server, upgrading the connection
+// with STARTTLS whenever the server supports it
+func (m *SMTPMailer) Send(ctx context.Context, message domain.MailMessage) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.config.Host, m.config.Port))
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.config.Host}); err != nil {
+			return err
+		}
+	}
+
+	if m.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+			if err := client.Auth(auth); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(m.buildMessage(message)); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (m *SMTPMailer) buildMessage(message domain.MailMessage) []byte {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("From: %s\r\n", m.config.From))
+	sb.WriteString(fmt.Sprintf("To: %s\r\n", message.To))
+	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", message.Subject))
+	sb.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	sb.WriteString("\r\n")
+
+	return []byte(sb.String())
+}
@@ -0,0 +1,128 @@
+package adapter_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/infra/adapter"
+)
+
+type stubSMTPMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// startStubSMTPServer accepts a single SMTP session and sends what it received to the returned channel
+func startStubSMTPServer(t *testing.T) (string, string, <-chan stubSMTPMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan stubSMTPMessage, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		msg := stubSMTPMessage{}
+
+		reply("220 localhost stub")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimSpace(line)
+
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				msg.from = strings.Trim(strings.TrimPrefix(cmd, "MAIL FROM:"), "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				msg.to = append(msg.to, strings.Trim(strings.TrimPrefix(cmd, "RCPT TO:"), "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 end with <CRLF>.<CRLF>")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				msg.data = data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- msg
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	host, port, received := startStubSMTPServer(t)
+
+	mailer := adapter.NewSMTPMailer(adapter.SMTPMailerConfig{
+		Host: host,
+		Port: port,
+		From: "no-reply@wishlist.local",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := mailer.Send(ctx, domain.MailMessage{
+		To:      "john@example.com",
+		Subject: "Reset your password",
+		Body:    "use this link\nthanks",
+	})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "no-reply@wishlist.local", msg.from)
+		assert.Equal(t, []string{"john@example.com"}, msg.to)
+		assert.Contains(t, msg.data, "Subject: Reset your password\r\n")
+		assert.Contains(t, msg.data, "use this link\r\nthanks")
+	case <-time.After(5 * time.Second):
+		t.Fatal("stub server did not receive the message")
+	}
+}
+
+func TestSMTPMailer_Send_ConnectionError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	mailer := adapter.NewSMTPMailer(adapter.SMTPMailerConfig{Host: host, Port: port, From: "no-reply@wishlist.local"})
+	err = mailer.Send(context.Background(), domain.MailMessage{To: "john@example.com"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP generates and checks RFC 6238 codes with the parameters every authenticator app
+// supports: HMAC-SHA1, 6 digits and 30 seconds steps
+type TOTP struct {
+	issuer string
+	period int64
+	digits int
+	// skew is how many steps before and after the current one are accepted to absorb clock drift
+	skew int64
+}
+
+func NewTOTP(issuer string) *TOTP {
+	return &TOTP{
+		issuer: issuer,
+		period: 30,
+		digits: 6,
+		skew:   1,
+	}
+}
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (t *TOTP) GenerateSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+func (t *TOTP) ProvisioningURI(secret string, accountName string) string {
+	query := url.Values{
+		"secret":    {secret},
+		"issuer":    {t.issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(t.digits)},
+		"period":    {fmt.Sprint(t.period)},
+	}
+
+	label := url.PathEscape(t.issuer + ":" + accountName)
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+func (t *TOTP) Validate(secret string, code string, at time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(code) != t.digits {
+		return 0, false
+	}
+
+	current := at.Unix() / t.period
+	for step := current - t.skew; step <= current+t.skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(t.code(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// code is the HOTP value (RFC 4226) of the given counter
+func (t *TOTP) code(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < t.digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", t.digits, value%mod)
+}
@@ -0,0 +1,75 @@
+package adapter_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/infra/adapter"
+)
+
+func TestTOTP_Validate(t *testing.T) {
+	// SHA1 test vectors from RFC 6238 appendix B, truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	totp := adapter.NewTOTP("Wishlist")
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "T=59", at: time.Unix(59, 0), code: "287082", wantStep: 1, wantOK: true},
+		{name: "T=1111111109", at: time.Unix(1111111109, 0), code: "081804", wantStep: 37037036, wantOK: true},
+		{name: "T=1234567890", at: time.Unix(1234567890, 0), code: "005924", wantStep: 41152263, wantOK: true},
+		{name: "previous step is accepted", at: time.Unix(89, 0), code: "287082", wantStep: 1, wantOK: true},
+		{name: "older steps are rejected", at: time.Unix(120, 0), code: "287082"},
+		{name: "wrong code", at: time.Unix(59, 0), code: "123456"},
+		{name: "wrong length", at: time.Unix(59, 0), code: "28708"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := totp.Validate(secret, tt.code, tt.at)
+
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantStep, step)
+			}
+		})
+	}
+}
+
+func TestTOTP_GenerateSecret(t *testing.T) {
+	totp := adapter.NewTOTP("Wishlist")
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	other, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+	assert.Len(t, decoded, 20)
+}
+
+func TestTOTP_ProvisioningURI(t *testing.T) {
+	uri := adapter.NewTOTP("Wishlist").ProvisioningURI("JBSWY3DPEHPK3PXP", "john@example.com")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Wishlist:john@example.com", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Wishlist", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
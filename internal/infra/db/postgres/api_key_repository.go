@@ -0,0 +1,100 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type apiKeyRepo struct {
+	DB *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *apiKeyRepo {
+	return &apiKeyRepo{
+		DB: db,
+	}
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	query := `INSERT INTO api_keys (id, customer_id, name, prefix, key_hash, scopes, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.DB.ExecContext(ctx, query, key.ID, key.CustomerID, key.Name, key.Prefix, key.KeyHash, pq.Array(scopes), key.CreatedAt)
+
+	return err
+}
+
+func (r *apiKeyRepo) ListByCustomerID(ctx context.Context, customerID string) ([]*domain.APIKey, error) {
+	query := `SELECT id, customer_id, name, prefix, key_hash, scopes, created_at FROM api_keys WHERE customer_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepo) GetActiveByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	query := `SELECT id, customer_id, name, prefix, key_hash, scopes, created_at FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`
+	key, err := scanAPIKey(r.DB.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, customerID string, id string, revokedAt time.Time) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND customer_id = $3 AND revoked_at IS NULL`
+	result, err := r.DB.ExecContext(ctx, query, revokedAt, id, customerID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row scanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+	var scopes []string
+	err := row.Scan(&key.ID, &key.CustomerID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&scopes), &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]domain.APIKeyScope, len(scopes))
+	for i, scope := range scopes {
+		key.Scopes[i] = domain.APIKeyScope(scope)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,45 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type auditRepo struct {
+	DB *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *auditRepo {
+	return &auditRepo{
+		DB: db,
+	}
+}
+
+func (r *auditRepo) Create(ctx context.Context, event *domain.AuditEvent) error {
+	query := `INSERT INTO audit_events (id, actor_id, actor_role, permission, owner_id, created_at) VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6)`
+	_, err := r.DB.ExecContext(ctx, query, event.ID, event.ActorID, event.ActorRole, event.Permission, event.OwnerID, event.CreatedAt)
+
+	return err
+}
+
+func (r *auditRepo) ListByCustomerID(ctx context.Context, customerID string) ([]*domain.AuditEvent, error) {
+	query := `SELECT id, actor_id, actor_role, permission, COALESCE(owner_id::text, ''), created_at FROM audit_events WHERE actor_id = $1 OR owner_id = $1 ORDER BY created_at`
+	rows, err := r.DB.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*domain.AuditEvent{}
+	for rows.Next() {
+		event := &domain.AuditEvent{}
+		if err := rows.Scan(&event.ID, &event.ActorID, &event.ActorRole, &event.Permission, &event.OwnerID, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -3,10 +3,15 @@ package postgresDB
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ydoro/wishlist/internal/domain"
 )
 
+const customerColumns = `id, name, email, password, created_at, updated_at, deleted_at, verified_at, role`
+
 type customerRepo struct {
 	DB *sql.DB
 }
@@ -18,42 +23,24 @@ func NewCustomerRepository(db *sql.DB) *customerRepo {
 }
 
 func (r *customerRepo) Create(ctx context.Context, customer *domain.Customer) error {
-	query := `INSERT INTO customers (id, name, email, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.DB.ExecContext(ctx, query, customer.ID, customer.Name, customer.Email, customer.Password, customer.CreatedAt, customer.UpdatedAt)
+	query := `INSERT INTO customers (id, name, email, password, created_at, updated_at, verified_at, role) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := r.DB.ExecContext(ctx, query, customer.ID, customer.Name, customer.Email, nullString(customer.Password), customer.CreatedAt, customer.UpdatedAt, nullTime(customer.VerifiedAt), customer.Role)
 
 	return err
 }
 
 func (r *customerRepo) GetByEmail(ctx context.Context, email string) (*domain.Customer, error) {
-	query := `SELECT id, name, email, password, created_at, updated_at FROM customers WHERE email = $1 AND deleted_at IS NULL`
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE email = $1 AND deleted_at IS NULL`
 	row := r.DB.QueryRowContext(ctx, query, email)
 
-	customer := &domain.Customer{}
-	err := row.Scan(&customer.ID, &customer.Name, &customer.Email, &customer.Password, &customer.CreatedAt, &customer.UpdatedAt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
-
-	return customer, nil
+	return scanCustomer(row)
 }
 
 func (r *customerRepo) GetByID(ctx context.Context, id string) (*domain.Customer, error) {
-	query := `SELECT id, name, email, password, created_at, updated_at FROM customers WHERE id = $1 AND deleted_at IS NULL`
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = $1 AND deleted_at IS NULL`
 	row := r.DB.QueryRowContext(ctx, query, id)
 
-	customer := &domain.Customer{}
-	err := row.Scan(&customer.ID, &customer.Name, &customer.Email, &customer.Password, &customer.CreatedAt, &customer.UpdatedAt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
-
-	return customer, nil
+	return scanCustomer(row)
 }
 
 func (r *customerRepo) Update(ctx context.Context, customer *domain.Customer) error {
@@ -61,8 +48,9 @@ func (r *customerRepo) Update(ctx context.Context, customer *domain.Customer) er
 		SET name = $1, 
 			email = $2, 
 			updated_at = $3, 
-			deleted_at = $4 
-		WHERE id = $5`
+			deleted_at = $4,
+			verified_at = $5
+		WHERE id = $6`
 
 	result, err := r.DB.ExecContext(
 		ctx,
@@ -70,7 +58,8 @@ func (r *customerRepo) Update(ctx context.Context, customer *domain.Customer) er
 		customer.Name,
 		customer.Email,
 		customer.UpdatedAt,
-		customer.DeletedAt,
+		nullTime(customer.DeletedAt),
+		nullTime(customer.VerifiedAt),
 		customer.ID,
 	)
 	if err != nil {
@@ -88,3 +77,176 @@ func (r *customerRepo) Update(ctx context.Context, customer *domain.Customer) er
 
 	return nil
 }
+
+func (r *customerRepo) UpdatePassword(ctx context.Context, customerID string, passwordHash string, updatedAt time.Time) error {
+	query := `UPDATE customers SET password = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := r.DB.ExecContext(ctx, query, nullString(passwordHash), updatedAt, customerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *customerRepo) MarkVerified(ctx context.Context, customerID string, verifiedAt time.Time) error {
+	query := `UPDATE customers SET verified_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, query, verifiedAt, customerID)
+
+	return err
+}
+
+func (r *customerRepo) GetDeletedByEmail(ctx context.Context, email string, deletedSince time.Time) (*domain.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE email = $1 AND deleted_at >= $2 AND erased_at IS NULL`
+	row := r.DB.QueryRowContext(ctx, query, email, deletedSince)
+
+	return scanCustomer(row)
+}
+
+func (r *customerRepo) GetDeletedByID(ctx context.Context, id string, deletedSince time.Time) (*domain.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = $1 AND deleted_at >= $2 AND erased_at IS NULL`
+	row := r.DB.QueryRowContext(ctx, query, id, deletedSince)
+
+	return scanCustomer(row)
+}
+
+func (r *customerRepo) Restore(ctx context.Context, customerID string, restoredAt time.Time) error {
+	query := `UPDATE customers SET deleted_at = NULL, updated_at = $1 WHERE id = $2 AND deleted_at IS NOT NULL AND erased_at IS NULL`
+
+	result, err := r.DB.ExecContext(ctx, query, restoredAt, customerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PurgeDeletedBefore relies on the ON DELETE CASCADE foreign keys to remove wishlists, tokens,
+// sessions and the rest of the customer data, audit events only keep the customer id
+func (r *customerRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM customers WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	result, err := r.DB.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// customerSortColumns maps the sorts to columns, each one has an index on (column, id) for the keyset pagination
+var customerSortColumns = map[domain.CustomerSort]string{
+	domain.CustomerSortCreatedAt: "created_at",
+	domain.CustomerSortName:      "name",
+	domain.CustomerSortEmail:     "email",
+}
+
+// likePrefixEscaper keeps the wildcards typed by the user from matching anything
+var likePrefixEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *customerRepo) Search(ctx context.Context, search domain.CustomerSearch) ([]*domain.Customer, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	switch search.Status {
+	case domain.CustomerStatusActive:
+		conditions = append(conditions, "deleted_at IS NULL")
+	case domain.CustomerStatusDeleted:
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	}
+
+	if search.Query != "" {
+		prefix := arg(likePrefixEscaper.Replace(search.Query) + "%")
+		conditions = append(conditions, fmt.Sprintf("(lower(name) LIKE %s OR lower(email) LIKE %s)", prefix, prefix))
+	}
+
+	if !search.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(search.CreatedAfter))
+	}
+
+	if !search.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(search.CreatedBefore))
+	}
+
+	column := customerSortColumns[search.Sort]
+	direction, comparison := "ASC", ">"
+	if search.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if search.After != nil {
+		var value interface{} = search.After.Value
+		if search.Sort == domain.CustomerSortCreatedAt {
+			createdAt, err := time.Parse(time.RFC3339Nano, search.After.Value)
+			if err != nil {
+				return nil, err
+			}
+			value = createdAt
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s)", column, comparison, arg(value), arg(search.After.ID)))
+	}
+
+	query := `SELECT ` + customerColumns + ` FROM customers`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %s", column, direction, direction, arg(search.Limit))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []*domain.Customer{}
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+
+	return customers, rows.Err()
+}
+
+func scanCustomer(row scanner) (*domain.Customer, error) {
+	customer := &domain.Customer{}
+	var password sql.NullString
+	var deletedAt, verifiedAt sql.NullTime
+	err := row.Scan(&customer.ID, &customer.Name, &customer.Email, &password, &customer.CreatedAt, &customer.UpdatedAt, &deletedAt, &verifiedAt, &customer.Role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	customer.Password = password.String
+	customer.DeletedAt = deletedAt.Time
+	customer.VerifiedAt = verifiedAt.Time
+
+	return customer, nil
+}
@@ -0,0 +1,106 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type erasureRepo struct {
+	DB *sql.DB
+}
+
+func NewErasureRepository(db *sql.DB) *erasureRepo {
+	return &erasureRepo{
+		DB: db,
+	}
+}
+
+// credentialTables hold nothing worth keeping once the customer is anonymous
+var credentialTables = []string{
+	"customer_identities",
+	"customer_recovery_codes",
+	"customer_mfa",
+	"api_keys",
+	"customer_sessions",
+	"refresh_tokens",
+	"customer_tokens",
+	"wishlist_shares",
+	"wishlist_collaborators",
+	"wishlist_reservations",
+}
+
+func (r *erasureRepo) Erase(ctx context.Context, receipt *domain.ErasureReceipt) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// deleted_at is kept when already set so the purge still runs at the end of the original grace period
+	query := `UPDATE customers
+		SET name = $1,
+			email = $2,
+			password = NULL,
+			verified_at = NULL,
+			updated_at = $3,
+			deleted_at = COALESCE(deleted_at, $3),
+			erased_at = $3
+		WHERE id = $4 AND erased_at IS NULL`
+
+	result, err := tx.ExecContext(ctx, query, domain.ErasedCustomerName, domain.ErasedCustomerEmail(receipt.CustomerID), receipt.ErasedAt, receipt.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// the row lock makes a concurrent erasure wait for this one and then match nothing
+	if rowsAffected == 0 {
+		return e.NewConflictError("customer is already erased")
+	}
+
+	wishlistQuery := `DELETE FROM wishlists WHERE customer_id = $1`
+	if receipt.WishlistRetention == domain.WishlistRetentionDetach {
+		wishlistQuery = `UPDATE wishlists SET customer_id = NULL WHERE customer_id = $1`
+	}
+
+	if _, err := tx.ExecContext(ctx, wishlistQuery, receipt.CustomerID); err != nil {
+		return err
+	}
+
+	for _, table := range credentialTables {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE customer_id = $1`, receipt.CustomerID); err != nil {
+			return err
+		}
+	}
+
+	insert := `INSERT INTO erasure_receipts (id, customer_id, requested_by, erased_at, wishlist_retention, wishlists, signature) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.ExecContext(ctx, insert, receipt.ID, receipt.CustomerID, receipt.RequestedBy, receipt.ErasedAt, receipt.WishlistRetention, receipt.Wishlists, receipt.Signature)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *erasureRepo) GetByCustomerID(ctx context.Context, customerID string) (*domain.ErasureReceipt, error) {
+	query := `SELECT id, customer_id, requested_by, erased_at, wishlist_retention, wishlists, signature FROM erasure_receipts WHERE customer_id = $1`
+	row := r.DB.QueryRowContext(ctx, query, customerID)
+
+	receipt := &domain.ErasureReceipt{}
+	err := row.Scan(&receipt.ID, &receipt.CustomerID, &receipt.RequestedBy, &receipt.ErasedAt, &receipt.WishlistRetention, &receipt.Wishlists, &receipt.Signature)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,23 @@
+package postgresDB
+
+import (
+	"database/sql"
+	"time"
+)
+
+// nullTime maps the zero time used by the domain to NULL, writing a zero time.Time
+// would store '0001-01-01' and break every "IS NULL" filter
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{
+		Time:  t,
+		Valid: !t.IsZero(),
+	}
+}
+
+// nullString stores empty strings as NULL, e.g. the password of customers created through an identity provider
+func nullString(s string) sql.NullString {
+	return sql.NullString{
+		String: s,
+		Valid:  s != "",
+	}
+}
@@ -0,0 +1,128 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type mfaRepo struct {
+	DB *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) *mfaRepo {
+	return &mfaRepo{
+		DB: db,
+	}
+}
+
+func (r *mfaRepo) Save(ctx context.Context, mfa *domain.CustomerMFA) error {
+	query := `INSERT INTO customer_mfa (customer_id, secret, last_step, created_at, enabled_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (customer_id) DO UPDATE 
+		SET secret = EXCLUDED.secret, 
+			last_step = EXCLUDED.last_step, 
+			created_at = EXCLUDED.created_at, 
+			enabled_at = EXCLUDED.enabled_at`
+	_, err := r.DB.ExecContext(ctx, query, mfa.CustomerID, mfa.Secret, mfa.LastStep, mfa.CreatedAt, nullTime(mfa.EnabledAt))
+
+	return err
+}
+
+func (r *mfaRepo) GetByCustomerID(ctx context.Context, customerID string) (*domain.CustomerMFA, error) {
+	query := `SELECT customer_id, secret, last_step, created_at, enabled_at FROM customer_mfa WHERE customer_id = $1`
+	row := r.DB.QueryRowContext(ctx, query, customerID)
+
+	mfa := &domain.CustomerMFA{}
+	var enabledAt sql.NullTime
+	err := row.Scan(&mfa.CustomerID, &mfa.Secret, &mfa.LastStep, &mfa.CreatedAt, &enabledAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	mfa.EnabledAt = enabledAt.Time
+
+	return mfa, nil
+}
+
+func (r *mfaRepo) Enable(ctx context.Context, customerID string, enabledAt time.Time) error {
+	query := `UPDATE customer_mfa SET enabled_at = $1 WHERE customer_id = $2`
+	_, err := r.DB.ExecContext(ctx, query, enabledAt, customerID)
+
+	return err
+}
+
+func (r *mfaRepo) MarkStepUsed(ctx context.Context, customerID string, step int64) (bool, error) {
+	query := `UPDATE customer_mfa SET last_step = $1 WHERE customer_id = $2 AND last_step < $1`
+
+	result, err := r.DB.ExecContext(ctx, query, step, customerID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *mfaRepo) Delete(ctx context.Context, customerID string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM customer_recovery_codes WHERE customer_id = $1`, customerID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM customer_mfa WHERE customer_id = $1`, customerID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepo) ReplaceRecoveryCodes(ctx context.Context, customerID string, codes []domain.RecoveryCode) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM customer_recovery_codes WHERE customer_id = $1`, customerID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO customer_recovery_codes (id, customer_id, code_hash, created_at) VALUES ($1, $2, $3, $4)`
+	for _, code := range codes {
+		if _, err := tx.ExecContext(ctx, query, code.ID, customerID, code.CodeHash, code.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepo) ConsumeRecoveryCode(ctx context.Context, customerID string, codeHash string, usedAt time.Time) (bool, error) {
+	query := `UPDATE customer_recovery_codes SET used_at = $1 WHERE customer_id = $2 AND code_hash = $3 AND used_at IS NULL`
+
+	result, err := r.DB.ExecContext(ctx, query, usedAt, customerID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
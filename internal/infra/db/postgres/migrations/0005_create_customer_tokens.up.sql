@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS customer_tokens (
+    id UUID PRIMARY KEY,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    purpose VARCHAR(50) NOT NULL, -- password_reset, ...
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_tokens_customer_purpose ON customer_tokens (customer_id, purpose);
@@ -0,0 +1,2 @@
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP; -- null until the customer confirms the email address
+UPDATE customers SET verified_at = created_at WHERE verified_at IS NULL; -- customers from before verification existed keep access
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS customer_identities (
+    id UUID PRIMARY KEY,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL, -- OpenID Connect issuer URL
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100) NOT NULL, -- email claimed by the provider when the identity was linked
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_identities_customer_id ON customer_identities (customer_id);
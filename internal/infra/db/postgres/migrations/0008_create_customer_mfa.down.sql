@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS customer_recovery_codes;
+DROP TABLE IF EXISTS customer_mfa;
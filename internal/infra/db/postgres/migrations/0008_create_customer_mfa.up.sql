@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS customer_mfa (
+    customer_id UUID PRIMARY KEY REFERENCES customers(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL, -- AES-GCM encrypted TOTP secret
+    last_step BIGINT NOT NULL DEFAULT 0, -- last accepted TOTP time step, prevents code replay
+    created_at TIMESTAMP DEFAULT NOW(),
+    enabled_at TIMESTAMP -- null until the enrollment is confirmed
+);
+
+CREATE TABLE IF NOT EXISTS customer_recovery_codes (
+    id UUID PRIMARY KEY,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    used_at TIMESTAMP,
+    UNIQUE (customer_id, code_hash)
+);
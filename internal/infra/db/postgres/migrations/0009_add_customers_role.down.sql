@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS audit_events;
+ALTER TABLE customers DROP COLUMN IF EXISTS role;
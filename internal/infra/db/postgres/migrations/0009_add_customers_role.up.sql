@@ -0,0 +1,13 @@
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'customer';
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY,
+    actor_id UUID NOT NULL,
+    actor_role VARCHAR(20) NOT NULL,
+    permission VARCHAR(50) NOT NULL,
+    owner_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_owner_id ON audit_events(owner_id);
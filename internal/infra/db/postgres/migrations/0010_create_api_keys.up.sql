@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL, -- first characters of the key, shown so customers can tell keys apart
+    key_hash VARCHAR(64) UNIQUE NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_customer_id ON api_keys (customer_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS customer_sessions (
+    id UUID PRIMARY KEY, -- refresh token family ID, carried by the sid claim
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    token_id UUID NOT NULL, -- last access token issued for the session
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_sessions_customer_id ON customer_sessions (customer_id);
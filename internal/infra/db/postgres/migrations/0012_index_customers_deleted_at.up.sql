@@ -0,0 +1,2 @@
+-- used by the purge job looking for customers past the deletion grace period
+CREATE INDEX IF NOT EXISTS idx_customers_deleted_at ON customers (deleted_at) WHERE deleted_at IS NOT NULL;
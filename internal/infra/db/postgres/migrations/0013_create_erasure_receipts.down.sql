@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS erasure_receipts;
+ALTER TABLE customers DROP COLUMN IF EXISTS erased_at;
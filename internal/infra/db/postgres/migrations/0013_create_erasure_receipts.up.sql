@@ -0,0 +1,12 @@
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP; -- erased customers only keep a tombstone and can't be restored
+
+-- no foreign key on purpose, a receipt must outlive the purge of the customer it proves was erased
+CREATE TABLE IF NOT EXISTS erasure_receipts (
+    id UUID PRIMARY KEY,
+    customer_id UUID UNIQUE NOT NULL,
+    requested_by UUID NOT NULL,
+    erased_at TIMESTAMP NOT NULL,
+    wishlist_retention VARCHAR(20) NOT NULL, -- delete or detach
+    wishlists INTEGER NOT NULL,
+    signature VARCHAR(64) NOT NULL
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_customers_email_id;
+DROP INDEX IF EXISTS idx_customers_name_id;
+DROP INDEX IF EXISTS idx_customers_created_at_id;
+DROP INDEX IF EXISTS idx_customers_lower_email_pattern;
+DROP INDEX IF EXISTS idx_customers_lower_name_pattern;
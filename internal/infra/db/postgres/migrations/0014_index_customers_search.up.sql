@@ -0,0 +1,8 @@
+-- prefix search on name and email, text_pattern_ops lets LIKE 'abc%' use the index whatever the collation
+CREATE INDEX IF NOT EXISTS idx_customers_lower_name_pattern ON customers (lower(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_customers_lower_email_pattern ON customers (lower(email) text_pattern_ops);
+
+-- keyset pagination of the admin listing, one index per sort
+CREATE INDEX IF NOT EXISTS idx_customers_created_at_id ON customers (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_customers_name_id ON customers (name, id);
+CREATE INDEX IF NOT EXISTS idx_customers_email_id ON customers (email, id);
@@ -0,0 +1,11 @@
+-- customers without a row use the defaults from domain.DefaultPreferences
+CREATE TABLE IF NOT EXISTS customer_preferences (
+    customer_id UUID PRIMARY KEY REFERENCES customers(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    currency CHAR(3) NOT NULL, -- ISO 4217
+    wishlist_visibility VARCHAR(20) NOT NULL,
+    notify_price_drops BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_back_in_stock BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_weekly_digest BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
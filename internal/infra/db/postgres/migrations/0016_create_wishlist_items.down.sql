@@ -0,0 +1,11 @@
+ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS items TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE wishlists w SET items = i.items
+FROM (
+    SELECT wishlist_id, array_agg(product_id ORDER BY position, added_at) AS items
+    FROM wishlist_items
+    GROUP BY wishlist_id
+) i
+WHERE i.wishlist_id = w.id;
+
+DROP TABLE IF EXISTS wishlist_items;
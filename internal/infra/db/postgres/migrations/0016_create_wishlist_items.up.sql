@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    wishlist_id UUID NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+    product_id VARCHAR(255) NOT NULL, -- no foreign key, products may come from the external catalog
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    priority INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (wishlist_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_position ON wishlist_items (wishlist_id, position);
+
+-- the array kept the order in which products were added, duplicates keep their first position
+INSERT INTO wishlist_items (wishlist_id, product_id, position)
+SELECT w.id, i.product_id, MIN(i.ordinality) - 1
+FROM wishlists w, unnest(w.items) WITH ORDINALITY AS i(product_id, ordinality)
+GROUP BY w.id, i.product_id
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE wishlists DROP COLUMN IF EXISTS items;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS wishlist_shares (
+    id UUID PRIMARY KEY,
+    wishlist_id UUID NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_shares_wishlist_id ON wishlist_shares (wishlist_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS wishlist_collaborators (
+    wishlist_id UUID NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL, -- viewer or editor
+    invited_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMP, -- NULL while the invitation is pending
+    PRIMARY KEY (wishlist_id, customer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_collaborators_customer_id ON wishlist_collaborators (customer_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS wishlist_reservations;
+ALTER TABLE customer_preferences DROP COLUMN IF EXISTS reveal_reservations;
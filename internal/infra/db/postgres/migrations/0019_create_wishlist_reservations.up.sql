@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS wishlist_reservations (
+    id UUID PRIMARY KEY,
+    wishlist_id UUID NOT NULL,
+    product_id VARCHAR(255) NOT NULL,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    reserved_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    released_at TIMESTAMP, -- NULL while the reservation holds the item, set on release or once it is found expired
+    -- removing the item from the wishlist releases it for good
+    FOREIGN KEY (wishlist_id, product_id) REFERENCES wishlist_items (wishlist_id, product_id) ON DELETE CASCADE
+);
+
+-- at most one active reservation per item, two concurrent reservations can't both be stored
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlist_reservations_active ON wishlist_reservations (wishlist_id, product_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_wishlist_reservations_customer_id ON wishlist_reservations (customer_id);
+
+ALTER TABLE customer_preferences ADD COLUMN IF NOT EXISTS reveal_reservations BOOLEAN NOT NULL DEFAULT FALSE;
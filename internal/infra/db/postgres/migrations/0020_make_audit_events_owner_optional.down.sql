@@ -0,0 +1,2 @@
+DELETE FROM audit_events WHERE owner_id IS NULL;
+ALTER TABLE audit_events ALTER COLUMN owner_id SET NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE audit_events ALTER COLUMN owner_id DROP NOT NULL; -- null for permissions not tied to an owner, like customer:search
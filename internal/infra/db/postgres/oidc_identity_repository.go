@@ -0,0 +1,62 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type oidcIdentityRepo struct {
+	DB *sql.DB
+}
+
+func NewOIDCIdentityRepository(db *sql.DB) *oidcIdentityRepo {
+	return &oidcIdentityRepo{
+		DB: db,
+	}
+}
+
+func (r *oidcIdentityRepo) Create(ctx context.Context, identity *domain.OIDCIdentity) error {
+	query := `INSERT INTO customer_identities (id, customer_id, issuer, subject, email, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.DB.ExecContext(ctx, query, identity.ID, identity.CustomerID, identity.Issuer, identity.Subject, identity.Email, identity.CreatedAt)
+
+	return err
+}
+
+func (r *oidcIdentityRepo) GetByIssuerAndSubject(ctx context.Context, issuer string, subject string) (*domain.OIDCIdentity, error) {
+	query := `SELECT id, customer_id, issuer, subject, email, created_at FROM customer_identities WHERE issuer = $1 AND subject = $2`
+	row := r.DB.QueryRowContext(ctx, query, issuer, subject)
+
+	identity := &domain.OIDCIdentity{}
+	err := row.Scan(&identity.ID, &identity.CustomerID, &identity.Issuer, &identity.Subject, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (r *oidcIdentityRepo) ListByCustomerID(ctx context.Context, customerID string) ([]*domain.OIDCIdentity, error) {
+	query := `SELECT id, customer_id, issuer, subject, email, created_at FROM customer_identities WHERE customer_id = $1 ORDER BY created_at`
+	rows, err := r.DB.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []*domain.OIDCIdentity{}
+	for rows.Next() {
+		identity := &domain.OIDCIdentity{}
+		err := rows.Scan(&identity.ID, &identity.CustomerID, &identity.Issuer, &identity.Subject, &identity.Email, &identity.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
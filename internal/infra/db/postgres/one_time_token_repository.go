@@ -0,0 +1,70 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type oneTimeTokenRepo struct {
+	DB *sql.DB
+}
+
+func NewOneTimeTokenRepository(db *sql.DB) *oneTimeTokenRepo {
+	return &oneTimeTokenRepo{
+		DB: db,
+	}
+}
+
+func (r *oneTimeTokenRepo) Create(ctx context.Context, token *domain.OneTimeToken) error {
+	query := `INSERT INTO customer_tokens (id, customer_id, purpose, token_hash, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.DB.ExecContext(ctx, query, token.ID, token.CustomerID, token.Purpose, token.TokenHash, token.CreatedAt, token.ExpiresAt)
+
+	return err
+}
+
+func (r *oneTimeTokenRepo) GetActive(ctx context.Context, purpose domain.OneTimeTokenPurpose, tokenHash string, now time.Time) (*domain.OneTimeToken, error) {
+	query := `SELECT id, customer_id, purpose, token_hash, created_at, expires_at 
+		FROM customer_tokens 
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3`
+	row := r.DB.QueryRowContext(ctx, query, tokenHash, purpose, now)
+
+	return scanOneTimeToken(row)
+}
+
+func (r *oneTimeTokenRepo) Consume(ctx context.Context, purpose domain.OneTimeTokenPurpose, tokenHash string, now time.Time) (*domain.OneTimeToken, error) {
+	query := `UPDATE customer_tokens 
+		SET used_at = $3 
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3
+		RETURNING id, customer_id, purpose, token_hash, created_at, expires_at`
+	row := r.DB.QueryRowContext(ctx, query, tokenHash, purpose, now)
+
+	token, err := scanOneTimeToken(row)
+	if token != nil {
+		token.UsedAt = now
+	}
+
+	return token, err
+}
+
+func (r *oneTimeTokenRepo) InvalidateForCustomer(ctx context.Context, customerID string, purpose domain.OneTimeTokenPurpose, now time.Time) error {
+	query := `UPDATE customer_tokens SET used_at = $3 WHERE customer_id = $1 AND purpose = $2 AND used_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, query, customerID, purpose, now)
+
+	return err
+}
+
+func scanOneTimeToken(row *sql.Row) (*domain.OneTimeToken, error) {
+	token := &domain.OneTimeToken{}
+	err := row.Scan(&token.ID, &token.CustomerID, &token.Purpose, &token.TokenHash, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,75 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type preferencesRepo struct {
+	DB *sql.DB
+}
+
+func NewPreferencesRepository(db *sql.DB) *preferencesRepo {
+	return &preferencesRepo{
+		DB: db,
+	}
+}
+
+func (r *preferencesRepo) GetByCustomerID(ctx context.Context, customerID string) (*domain.Preferences, error) {
+	query := `SELECT customer_id, locale, currency, wishlist_visibility, notify_price_drops, notify_back_in_stock, notify_weekly_digest, reveal_reservations, updated_at
+		FROM customer_preferences WHERE customer_id = $1`
+	row := r.DB.QueryRowContext(ctx, query, customerID)
+
+	preferences := &domain.Preferences{}
+	err := row.Scan(
+		&preferences.CustomerID,
+		&preferences.Locale,
+		&preferences.Currency,
+		&preferences.WishlistVisibility,
+		&preferences.Notifications.PriceDrops,
+		&preferences.Notifications.BackInStock,
+		&preferences.Notifications.WeeklyDigest,
+		&preferences.RevealReservations,
+		&preferences.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (r *preferencesRepo) Save(ctx context.Context, preferences *domain.Preferences) error {
+	query := `INSERT INTO customer_preferences (customer_id, locale, currency, wishlist_visibility, notify_price_drops, notify_back_in_stock, notify_weekly_digest, reveal_reservations, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (customer_id) DO UPDATE SET
+			locale = EXCLUDED.locale,
+			currency = EXCLUDED.currency,
+			wishlist_visibility = EXCLUDED.wishlist_visibility,
+			notify_price_drops = EXCLUDED.notify_price_drops,
+			notify_back_in_stock = EXCLUDED.notify_back_in_stock,
+			notify_weekly_digest = EXCLUDED.notify_weekly_digest,
+			reveal_reservations = EXCLUDED.reveal_reservations,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.DB.ExecContext(
+		ctx,
+		query,
+		preferences.CustomerID,
+		preferences.Locale,
+		preferences.Currency,
+		preferences.WishlistVisibility,
+		preferences.Notifications.PriceDrops,
+		preferences.Notifications.BackInStock,
+		preferences.Notifications.WeeklyDigest,
+		preferences.RevealReservations,
+		preferences.UpdatedAt,
+	)
+
+	return err
+}
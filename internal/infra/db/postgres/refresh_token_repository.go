@@ -0,0 +1,75 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type refreshTokenRepo struct {
+	DB *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *refreshTokenRepo {
+	return &refreshTokenRepo{
+		DB: db,
+	}
+}
+
+func (r *refreshTokenRepo) Create(ctx context.Context, token *domain.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, customer_id, family_id, token_hash, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.DB.ExecContext(ctx, query, token.ID, token.CustomerID, token.FamilyID, token.TokenHash, token.CreatedAt, token.ExpiresAt)
+
+	return err
+}
+
+func (r *refreshTokenRepo) GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	query := `SELECT id, customer_id, family_id, token_hash, created_at, expires_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = $1`
+	row := r.DB.QueryRowContext(ctx, query, tokenHash)
+
+	token := &domain.RefreshToken{}
+	var usedAt, revokedAt sql.NullTime
+	err := row.Scan(&token.ID, &token.CustomerID, &token.FamilyID, &token.TokenHash, &token.CreatedAt, &token.ExpiresAt, &usedAt, &revokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	token.UsedAt = usedAt.Time
+	token.RevokedAt = revokedAt.Time
+
+	return token, nil
+}
+
+func (r *refreshTokenRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL AND revoked_at IS NULL`
+	result, err := r.DB.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepo) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, query, revokedAt, familyID)
+
+	return err
+}
+
+func (r *refreshTokenRepo) RevokeAllForCustomer(ctx context.Context, customerID string, revokedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE customer_id = $2 AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, query, revokedAt, customerID)
+
+	return err
+}
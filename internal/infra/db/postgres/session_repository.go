@@ -0,0 +1,120 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type sessionRepo struct {
+	DB *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *sessionRepo {
+	return &sessionRepo{
+		DB: db,
+	}
+}
+
+func (r *sessionRepo) Create(ctx context.Context, session *domain.Session) error {
+	query := `INSERT INTO customer_sessions (id, customer_id, token_id, user_agent, ip, created_at, last_seen_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.DB.ExecContext(ctx, query, session.ID, session.CustomerID, session.TokenID, session.UserAgent, session.IP, session.CreatedAt, session.LastSeenAt)
+
+	return err
+}
+
+func (r *sessionRepo) Touch(ctx context.Context, id string, tokenID string, lastSeenAt time.Time) error {
+	query := `UPDATE customer_sessions SET token_id = $1, last_seen_at = $2 WHERE id = $3`
+	_, err := r.DB.ExecContext(ctx, query, tokenID, lastSeenAt, id)
+
+	return err
+}
+
+func (r *sessionRepo) MarkSeen(ctx context.Context, id string, seenAt time.Time) error {
+	query := `UPDATE customer_sessions SET last_seen_at = $1 WHERE id = $2 AND last_seen_at < $1`
+	_, err := r.DB.ExecContext(ctx, query, seenAt, id)
+
+	return err
+}
+
+func (r *sessionRepo) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	query := `SELECT id, customer_id, token_id, user_agent, ip, created_at, last_seen_at, revoked_at FROM customer_sessions WHERE id = $1`
+	session, err := scanSession(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepo) ListActiveByCustomerID(ctx context.Context, customerID string) ([]*domain.Session, error) {
+	query := `SELECT id, customer_id, token_id, user_agent, ip, created_at, last_seen_at, revoked_at FROM customer_sessions WHERE customer_id = $1 AND revoked_at IS NULL ORDER BY last_seen_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*domain.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *sessionRepo) ListByCustomerID(ctx context.Context, customerID string) ([]*domain.Session, error) {
+	query := `SELECT id, customer_id, token_id, user_agent, ip, created_at, last_seen_at, revoked_at FROM customer_sessions WHERE customer_id = $1 ORDER BY created_at`
+	rows, err := r.DB.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*domain.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *sessionRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	query := `UPDATE customer_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, query, revokedAt, id)
+
+	return err
+}
+
+func (r *sessionRepo) RevokeAllForCustomer(ctx context.Context, customerID string, revokedAt time.Time) error {
+	query := `UPDATE customer_sessions SET revoked_at = $1 WHERE customer_id = $2 AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, query, revokedAt, customerID)
+
+	return err
+}
+
+func scanSession(row scanner) (*domain.Session, error) {
+	session := &domain.Session{}
+	var revokedAt sql.NullTime
+	err := row.Scan(&session.ID, &session.CustomerID, &session.TokenID, &session.UserAgent, &session.IP, &session.CreatedAt, &session.LastSeenAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	session.RevokedAt = revokedAt.Time
+
+	return session, nil
+}
@@ -0,0 +1,111 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type wishlistCollaboratorRepo struct {
+	DB *sql.DB
+}
+
+func NewWishlistCollaboratorRepository(db *sql.DB) *wishlistCollaboratorRepo {
+	return &wishlistCollaboratorRepo{
+		DB: db,
+	}
+}
+
+func (r *wishlistCollaboratorRepo) Save(ctx context.Context, collaborator *domain.WishlistCollaborator) error {
+	query := `INSERT INTO wishlist_collaborators (wishlist_id, customer_id, role, invited_at, accepted_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wishlist_id, customer_id) DO UPDATE SET role = EXCLUDED.role, invited_at = EXCLUDED.invited_at, accepted_at = EXCLUDED.accepted_at`
+	_, err := r.DB.ExecContext(ctx, query, collaborator.WishlistID, collaborator.CustomerID, string(collaborator.Role), collaborator.InvitedAt, collaborator.AcceptedAt)
+
+	return err
+}
+
+func (r *wishlistCollaboratorRepo) Get(ctx context.Context, wishlistID string, customerID string) (*domain.WishlistCollaborator, error) {
+	query := `SELECT wishlist_id, customer_id, role, invited_at, accepted_at FROM wishlist_collaborators WHERE wishlist_id = $1 AND customer_id = $2`
+	collaborator, err := scanWishlistCollaborator(r.DB.QueryRowContext(ctx, query, wishlistID, customerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return collaborator, nil
+}
+
+func (r *wishlistCollaboratorRepo) ListByWishlistID(ctx context.Context, wishlistID string) ([]*domain.WishlistCollaborator, error) {
+	query := `SELECT wishlist_id, customer_id, role, invited_at, accepted_at FROM wishlist_collaborators WHERE wishlist_id = $1 ORDER BY invited_at`
+	rows, err := r.DB.QueryContext(ctx, query, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collaborators := []*domain.WishlistCollaborator{}
+	for rows.Next() {
+		collaborator, err := scanWishlistCollaborator(rows)
+		if err != nil {
+			return nil, err
+		}
+		collaborators = append(collaborators, collaborator)
+	}
+
+	return collaborators, rows.Err()
+}
+
+func (r *wishlistCollaboratorRepo) ListByCustomerID(ctx context.Context, customerID string) ([]*domain.WishlistCollaborator, error) {
+	query := `SELECT wishlist_id, customer_id, role, invited_at, accepted_at FROM wishlist_collaborators WHERE customer_id = $1 ORDER BY invited_at`
+	rows, err := r.DB.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collaborations := []*domain.WishlistCollaborator{}
+	for rows.Next() {
+		collaboration, err := scanWishlistCollaborator(rows)
+		if err != nil {
+			return nil, err
+		}
+		collaborations = append(collaborations, collaboration)
+	}
+
+	return collaborations, rows.Err()
+}
+
+func (r *wishlistCollaboratorRepo) Remove(ctx context.Context, wishlistID string, customerID string) (bool, error) {
+	query := `DELETE FROM wishlist_collaborators WHERE wishlist_id = $1 AND customer_id = $2`
+	result, err := r.DB.ExecContext(ctx, query, wishlistID, customerID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func scanWishlistCollaborator(row scanner) (*domain.WishlistCollaborator, error) {
+	collaborator := &domain.WishlistCollaborator{}
+	var role string
+	var acceptedAt sql.NullTime
+	err := row.Scan(&collaborator.WishlistID, &collaborator.CustomerID, &role, &collaborator.InvitedAt, &acceptedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator.Role = domain.CollaboratorRole(role)
+	if acceptedAt.Valid {
+		collaborator.AcceptedAt = &acceptedAt.Time
+	}
+
+	return collaborator, nil
+}
@@ -19,17 +19,30 @@ func NewWishlistRepository(db *sql.DB) *wishlistRepo {
 }
 
 func (r *wishlistRepo) Create(ctx context.Context, wishlist *domain.Wishlist) error {
-	query := `INSERT INTO wishlists (id, customer_id, title, items) VALUES ($1, $2, $3, $4)`
-	_, err := r.DB.ExecContext(ctx, query, wishlist.ID, wishlist.CustomerId, wishlist.Title, pq.Array(wishlist.Items))
-	return err
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO wishlists (id, customer_id, title) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, query, wishlist.ID, wishlist.CustomerId, wishlist.Title); err != nil {
+		return err
+	}
+
+	if err := saveWishlistItems(ctx, tx, wishlist); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *wishlistRepo) GetById(ctx context.Context, wishlistId string) (*domain.Wishlist, error) {
-	query := `SELECT id, customer_id, title, items FROM wishlists WHERE id = $1`
+	query := `SELECT id, customer_id, title FROM wishlists WHERE id = $1`
 	row := r.DB.QueryRowContext(ctx, query, wishlistId)
 
 	wishlist := &domain.Wishlist{}
-	err := row.Scan(&wishlist.ID, &wishlist.CustomerId, &wishlist.Title, pq.Array(&wishlist.Items))
+	err := row.Scan(&wishlist.ID, &wishlist.CustomerId, &wishlist.Title)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -37,15 +50,19 @@ func (r *wishlistRepo) GetById(ctx context.Context, wishlistId string) (*domain.
 		return nil, err
 	}
 
+	if err := r.loadItems(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
 	return wishlist, nil
 }
 
 func (r *wishlistRepo) GetByTitle(ctx context.Context, customerId string, title string) (*domain.Wishlist, error) {
-	query := `SELECT id, customer_id, title, items FROM wishlists WHERE customer_id = $1 AND title = $2`
+	query := `SELECT id, customer_id, title FROM wishlists WHERE customer_id = $1 AND title = $2`
 	row := r.DB.QueryRowContext(ctx, query, customerId, title)
 
 	wishlist := &domain.Wishlist{}
-	err := row.Scan(&wishlist.ID, &wishlist.CustomerId, &wishlist.Title, pq.Array(&wishlist.Items))
+	err := row.Scan(&wishlist.ID, &wishlist.CustomerId, &wishlist.Title)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -53,11 +70,15 @@ func (r *wishlistRepo) GetByTitle(ctx context.Context, customerId string, title
 		return nil, err
 	}
 
+	if err := r.loadItems(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
 	return wishlist, nil
 }
 
 func (r *wishlistRepo) GetByCustomerId(ctx context.Context, customerId string) ([]*domain.Wishlist, error) {
-	query := `SELECT id, customer_id, title, items FROM wishlists WHERE customer_id = $1`
+	query := `SELECT id, customer_id, title FROM wishlists WHERE customer_id = $1`
 	rows, err := r.DB.QueryContext(ctx, query, customerId)
 	if err != nil {
 		return nil, err
@@ -67,19 +88,34 @@ func (r *wishlistRepo) GetByCustomerId(ctx context.Context, customerId string) (
 	var wishlists []*domain.Wishlist
 	for rows.Next() {
 		wishlist := &domain.Wishlist{}
-		err := rows.Scan(&wishlist.ID, &wishlist.CustomerId, &wishlist.Title, pq.Array(&wishlist.Items))
+		err := rows.Scan(&wishlist.ID, &wishlist.CustomerId, &wishlist.Title)
 		if err != nil {
 			return nil, err
 		}
 		wishlists = append(wishlists, wishlist)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, wishlists...); err != nil {
+		return nil, err
+	}
+
 	return wishlists, nil
 }
 
+// Update replaces the title and every item of the wishlist
 func (r *wishlistRepo) Update(ctx context.Context, wishlist *domain.Wishlist) error {
-	query := `UPDATE wishlists SET items = $1, title = $4 WHERE id = $2 AND customer_id = $3`
-	result, err := r.DB.ExecContext(ctx, query, pq.Array(wishlist.Items), wishlist.ID, wishlist.CustomerId, wishlist.Title)
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE wishlists SET title = $3 WHERE id = $1 AND customer_id = $2`
+	result, err := tx.ExecContext(ctx, query, wishlist.ID, wishlist.CustomerId, wishlist.Title)
 	if err != nil {
 		return err
 	}
@@ -93,8 +129,106 @@ func (r *wishlistRepo) Update(ctx context.Context, wishlist *domain.Wishlist) er
 		return sql.ErrNoRows
 	}
 
+	// items still in the list are updated in place, deleting them would release their reservations
+	removed := `DELETE FROM wishlist_items WHERE wishlist_id = $1 AND NOT (product_id = ANY($2))`
+	if _, err := tx.ExecContext(ctx, removed, wishlist.ID, pq.Array(wishlist.ProductIDs())); err != nil {
+		return err
+	}
+
+	if err := saveWishlistItems(ctx, tx, wishlist); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddItem inserts the product after the last item unless it is already there, the primary key
+// keeps concurrent adds of the same product from duplicating it
+func (r *wishlistRepo) AddItem(ctx context.Context, wishlistId string, customerId string, item *domain.WishlistItem) (bool, error) {
+	query := `INSERT INTO wishlist_items (wishlist_id, product_id, quantity, priority, note, added_at, position)
+		SELECT w.id, $3, $4, $5, $6, $7, (SELECT COALESCE(MAX(position) + 1, 0) FROM wishlist_items WHERE wishlist_id = w.id)
+		FROM wishlists w WHERE w.id = $1 AND w.customer_id = $2
+		ON CONFLICT (wishlist_id, product_id) DO NOTHING`
+
+	return r.execItemChange(ctx, query, wishlistId, customerId, item.ProductID, item.Quantity, item.Priority, item.Note, item.AddedAt)
+}
+
+func (r *wishlistRepo) RemoveItem(ctx context.Context, wishlistId string, customerId string, productId string) (bool, error) {
+	query := `DELETE FROM wishlist_items i USING wishlists w
+		WHERE i.wishlist_id = w.id AND w.id = $1 AND w.customer_id = $2 AND i.product_id = $3`
+
+	return r.execItemChange(ctx, query, wishlistId, customerId, productId)
+}
+
+func (r *wishlistRepo) execItemChange(ctx context.Context, query string, args ...any) (bool, error) {
+	result, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// loadItems fills the items of every given wishlist with a single query
+func (r *wishlistRepo) loadItems(ctx context.Context, wishlists ...*domain.Wishlist) error {
+	if len(wishlists) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domain.Wishlist, len(wishlists))
+	ids := make([]string, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		wishlist.Items = []domain.WishlistItem{}
+		byID[wishlist.ID] = wishlist
+		ids = append(ids, wishlist.ID)
+	}
+
+	query := `SELECT wishlist_id, product_id, quantity, priority, note, added_at, position
+		FROM wishlist_items WHERE wishlist_id = ANY($1) ORDER BY position, added_at`
+	rows, err := r.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var wishlistID string
+		var item domain.WishlistItem
+		err := rows.Scan(&wishlistID, &item.ProductID, &item.Quantity, &item.Priority, &item.Note, &item.AddedAt, &item.Position)
+		if err != nil {
+			return err
+		}
+
+		wishlist := byID[wishlistID]
+		wishlist.Items = append(wishlist.Items, item)
+	}
+
+	return rows.Err()
+}
+
+func saveWishlistItems(ctx context.Context, tx *sql.Tx, wishlist *domain.Wishlist) error {
+	query := `INSERT INTO wishlist_items (wishlist_id, product_id, quantity, priority, note, added_at, position) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (wishlist_id, product_id) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			priority = EXCLUDED.priority,
+			note = EXCLUDED.note,
+			added_at = EXCLUDED.added_at,
+			position = EXCLUDED.position`
+	for _, item := range wishlist.Items {
+		_, err := tx.ExecContext(ctx, query, wishlist.ID, item.ProductID, item.Quantity, item.Priority, item.Note, item.AddedAt, item.Position)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
 func (r *wishlistRepo) DeleteWishlist(ctx context.Context, wishlistId string) error {
 	query := `DELETE FROM wishlists WHERE id = $1`
 	result, err := r.DB.ExecContext(ctx, query, wishlistId)
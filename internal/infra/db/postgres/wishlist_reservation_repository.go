@@ -0,0 +1,144 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+// uniqueViolation is the postgres error code of a unique index rejecting a row
+const uniqueViolation = "23505"
+
+type wishlistReservationRepo struct {
+	DB *sql.DB
+}
+
+func NewWishlistReservationRepository(db *sql.DB) *wishlistReservationRepo {
+	return &wishlistReservationRepo{
+		DB: db,
+	}
+}
+
+// Reserve releases the expired reservation of the item, if any, before storing the new one. The partial
+// unique index decides between concurrent reservations, the one committed last gets a ConflictError
+func (r *wishlistReservationRepo) Reserve(ctx context.Context, reservation *domain.WishlistReservation) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	expire := `UPDATE wishlist_reservations SET released_at = expires_at
+		WHERE wishlist_id = $1 AND product_id = $2 AND released_at IS NULL AND expires_at <= $3`
+	if _, err := tx.ExecContext(ctx, expire, reservation.WishlistID, reservation.ProductID, reservation.ReservedAt); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO wishlist_reservations (id, wishlist_id, product_id, customer_id, anonymous, reserved_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		reservation.ID,
+		reservation.WishlistID,
+		reservation.ProductID,
+		reservation.CustomerID,
+		reservation.Anonymous,
+		reservation.ReservedAt,
+		reservation.ExpiresAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return e.NewConflictError("item is already reserved")
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *wishlistReservationRepo) ListActiveByWishlistID(ctx context.Context, wishlistID string, now time.Time) ([]*domain.WishlistReservation, error) {
+	query := `SELECT id, wishlist_id, product_id, customer_id, anonymous, reserved_at, expires_at FROM wishlist_reservations
+		WHERE wishlist_id = $1 AND released_at IS NULL AND expires_at > $2`
+	rows, err := r.DB.QueryContext(ctx, query, wishlistID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []*domain.WishlistReservation{}
+	for rows.Next() {
+		reservation := &domain.WishlistReservation{}
+		err := rows.Scan(
+			&reservation.ID,
+			&reservation.WishlistID,
+			&reservation.ProductID,
+			&reservation.CustomerID,
+			&reservation.Anonymous,
+			&reservation.ReservedAt,
+			&reservation.ExpiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+func (r *wishlistReservationRepo) ListByCustomerID(ctx context.Context, customerID string) ([]*domain.WishlistReservation, error) {
+	query := `SELECT id, wishlist_id, product_id, customer_id, anonymous, reserved_at, expires_at, released_at FROM wishlist_reservations
+		WHERE customer_id = $1 ORDER BY reserved_at`
+	rows, err := r.DB.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []*domain.WishlistReservation{}
+	for rows.Next() {
+		reservation := &domain.WishlistReservation{}
+		var releasedAt sql.NullTime
+		err := rows.Scan(
+			&reservation.ID,
+			&reservation.WishlistID,
+			&reservation.ProductID,
+			&reservation.CustomerID,
+			&reservation.Anonymous,
+			&reservation.ReservedAt,
+			&reservation.ExpiresAt,
+			&releasedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if releasedAt.Valid {
+			reservation.ReleasedAt = &releasedAt.Time
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+func (r *wishlistReservationRepo) Release(ctx context.Context, wishlistID string, productID string, customerID string, releasedAt time.Time) (bool, error) {
+	query := `UPDATE wishlist_reservations SET released_at = $4
+		WHERE wishlist_id = $1 AND product_id = $2 AND customer_id = $3 AND released_at IS NULL AND expires_at > $4`
+	result, err := r.DB.ExecContext(ctx, query, wishlistID, productID, customerID, releasedAt)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,89 @@
+package postgresDB
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type wishlistShareRepo struct {
+	DB *sql.DB
+}
+
+func NewWishlistShareRepository(db *sql.DB) *wishlistShareRepo {
+	return &wishlistShareRepo{
+		DB: db,
+	}
+}
+
+func (r *wishlistShareRepo) Create(ctx context.Context, share *domain.WishlistShare) error {
+	query := `INSERT INTO wishlist_shares (id, wishlist_id, customer_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.DB.ExecContext(ctx, query, share.ID, share.WishlistID, share.CustomerID, share.TokenHash, share.ExpiresAt, share.CreatedAt)
+
+	return err
+}
+
+func (r *wishlistShareRepo) ListByWishlistID(ctx context.Context, wishlistID string) ([]*domain.WishlistShare, error) {
+	query := `SELECT id, wishlist_id, customer_id, token_hash, expires_at, created_at FROM wishlist_shares WHERE wishlist_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []*domain.WishlistShare{}
+	for rows.Next() {
+		share, err := scanWishlistShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
+
+func (r *wishlistShareRepo) GetActiveByHash(ctx context.Context, tokenHash string) (*domain.WishlistShare, error) {
+	query := `SELECT id, wishlist_id, customer_id, token_hash, expires_at, created_at FROM wishlist_shares WHERE token_hash = $1 AND revoked_at IS NULL`
+	share, err := scanWishlistShare(r.DB.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return share, nil
+}
+
+func (r *wishlistShareRepo) Revoke(ctx context.Context, wishlistID string, id string, revokedAt time.Time) (bool, error) {
+	query := `UPDATE wishlist_shares SET revoked_at = $1 WHERE id = $2 AND wishlist_id = $3 AND revoked_at IS NULL`
+	result, err := r.DB.ExecContext(ctx, query, revokedAt, id, wishlistID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func scanWishlistShare(row scanner) (*domain.WishlistShare, error) {
+	share := &domain.WishlistShare{}
+	var expiresAt sql.NullTime
+	err := row.Scan(&share.ID, &share.WishlistID, &share.CustomerID, &share.TokenHash, &expiresAt, &share.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		share.ExpiresAt = &expiresAt.Time
+	}
+
+	return share, nil
+}
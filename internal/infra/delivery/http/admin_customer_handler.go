@@ -0,0 +1,90 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type adminCustomerHandler struct {
+	searchCustomersUC domain.SearchCustomersUC
+}
+
+func NewAdminCustomerHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	searchCustomersUC domain.SearchCustomersUC,
+) {
+	handler := &adminCustomerHandler{
+		searchCustomersUC: searchCustomersUC,
+	}
+
+	adminRoutes := r.Group("/admin/customers")
+	adminRoutes.Use(auth)
+	adminRoutes.GET("", handler.SearchCustomers)
+}
+
+// SearchCustomers godoc
+// @Summary Lists and searches customers
+// @Description Admins only, every search is audited. Pages are linked by next_cursor, which must be sent back with the same filters and sort
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "prefix of the name or email"
+// @Param created_after query string false "RFC 3339 date, inclusive"
+// @Param created_before query string false "RFC 3339 date, exclusive"
+// @Param status query string false "active (default), deleted or all"
+// @Param sort query string false "created_at (default), name or email"
+// @Param order query string false "asc (default) or desc"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "page size, 20 by default and 100 at most"
+// @Success 200 {object} outputs.CustomerPageResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/admin/customers [get]
+func (h *adminCustomerHandler) SearchCustomers(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	var query inputs.CustomerSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	page, err := h.searchCustomersUC.SearchCustomers(c, currentCustomer.ID, domain.CustomerSearchParams(query))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	out := outputs.CustomerPageResponse{
+		Customers:  make([]outputs.AdminCustomerResponse, len(page.Customers)),
+		NextCursor: page.NextCursor,
+	}
+	for i, customer := range page.Customers {
+		out.Customers[i] = outputs.AdminCustomerResponse{
+			ID:        customer.ID,
+			Name:      customer.Name,
+			Email:     customer.Email,
+			Role:      string(customer.Role),
+			CreatedAt: customer.CreatedAt,
+			UpdatedAt: customer.UpdatedAt,
+		}
+		if !customer.VerifiedAt.IsZero() {
+			out.Customers[i].VerifiedAt = &customer.VerifiedAt
+		}
+		if !customer.DeletedAt.IsZero() {
+			out.Customers[i].DeletedAt = &customer.DeletedAt
+		}
+	}
+
+	c.JSON(200, out)
+}
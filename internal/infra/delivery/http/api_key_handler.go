@@ -0,0 +1,157 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type apiKeyHandler struct {
+	createAPIKeyUC domain.CreateAPIKeyUC
+	listAPIKeysUC  domain.ListAPIKeysUC
+	revokeAPIKeyUC domain.RevokeAPIKeyUC
+}
+
+// SetupAPIKeyHandler registers the key management routes, auth must not accept API keys
+// otherwise a leaked key could be used to mint new ones
+func SetupAPIKeyHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	createAPIKeyUC domain.CreateAPIKeyUC,
+	listAPIKeysUC domain.ListAPIKeysUC,
+	revokeAPIKeyUC domain.RevokeAPIKeyUC,
+) {
+	handler := &apiKeyHandler{
+		createAPIKeyUC: createAPIKeyUC,
+		listAPIKeysUC:  listAPIKeysUC,
+		revokeAPIKeyUC: revokeAPIKeyUC,
+	}
+
+	apiKeyRoutes := r.Group("/:customerId/api-keys")
+	apiKeyRoutes.Use(auth)
+	apiKeyRoutes.POST("/", handler.CreateAPIKey)
+	apiKeyRoutes.GET("/", handler.ListAPIKeys)
+	apiKeyRoutes.DELETE("/:keyId", handler.RevokeAPIKey)
+}
+
+// CreateAPIKey godoc
+// @Summary Creates an API key for server-to-server access
+// @Description The key is only returned once, send it as `Authorization: ApiKey <key>`
+// @Tags api keys
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param key body inputs.CreateAPIKeyInput true "key name and scopes"
+// @Success 201 {object} outputs.CreatedAPIKeyResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/api-keys [post]
+func (h *apiKeyHandler) CreateAPIKey(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	var input inputs.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	scopes := make([]domain.APIKeyScope, len(input.Scopes))
+	for i, scope := range input.Scopes {
+		scopes[i] = domain.APIKeyScope(scope)
+	}
+
+	key, err := h.createAPIKeyUC.CreateAPIKey(c, currentCustomer.ID, c.Param("customerId"), domain.IncommingAPIKey{
+		Name:   input.Name,
+		Scopes: scopes,
+	})
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, outputs.CreatedAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(&key.APIKey),
+		Key:            key.Key,
+	})
+}
+
+// ListAPIKeys godoc
+// @Summary Lists the active API keys of the customer
+// @Tags api keys
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Success 200 {array} outputs.APIKeyResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/api-keys [get]
+func (h *apiKeyHandler) ListAPIKeys(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	keys, err := h.listAPIKeysUC.ListAPIKeys(c, currentCustomer.ID, c.Param("customerId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	out := make([]outputs.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		out[i] = toAPIKeyResponse(key)
+	}
+
+	c.JSON(200, out)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revokes an API key, it stops working right away
+// @Tags api keys
+// @Security BearerAuth
+// @Param customerId path string true "Customer ID"
+// @Param keyId path string true "API key ID"
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/api-keys/{keyId} [delete]
+func (h *apiKeyHandler) RevokeAPIKey(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.revokeAPIKeyUC.RevokeAPIKey(c, currentCustomer.ID, c.Param("customerId"), c.Param("keyId")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+func toAPIKeyResponse(key *domain.APIKey) outputs.APIKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return outputs.APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		CreatedAt: key.CreatedAt,
+	}
+}
@@ -3,12 +3,15 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
 	"github.com/ydoro/wishlist/internal/presentation/inputs"
 	"github.com/ydoro/wishlist/internal/presentation/outputs"
 )
 
 type AuthHandler struct {
-	AuthUseCase domain.Authenticator
+	AuthUseCase    domain.Authenticator
+	RefreshUseCase domain.TokenRefresher
+	TokenRevoker   domain.TokenRevoker
 }
 
 // PasswordAuthentication godoc
@@ -20,6 +23,7 @@ type AuthHandler struct {
 // @Success 200 {object} outputs.AuthSuccessResponse
 // @Failure 401 {object} outputs.ErrorResponse
 // @Failure 400 {object} outputs.ErrorResponse
+// @Failure 429 {object} outputs.ErrorResponse
 // @Failure 500 {object} outputs.ErrorResponse
 // @Router /api/auth/login [post]
 func (h *AuthHandler) PasswordAuthentication(c *gin.Context) {
@@ -29,20 +33,119 @@ func (h *AuthHandler) PasswordAuthentication(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid input"})
 		return
 	}
+	credentials.IP = c.ClientIP()
+	credentials.UserAgent = c.Request.UserAgent()
 
-	token, err := h.AuthUseCase.Authenticate(c, credentials)
+	tokens, err := h.AuthUseCase.Authenticate(c, credentials)
 	if err != nil {
 		HandleError(c, err)
 		return
 	}
 
-	c.JSON(200, outputs.AuthSuccessResponse{Token: token})
+	c.JSON(200, toAuthSuccessResponse(tokens))
 }
-func NewAuthHandler(r *gin.RouterGroup, uc domain.Authenticator) {
+
+// RefreshToken godoc
+// @Summary Exchanges a refresh token for a new access token
+// @Description The given refresh token is rotated, reusing an already rotated token revokes the whole session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body inputs.RefreshTokenInput true "refresh token"
+// @Success 200 {object} outputs.AuthSuccessResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var input inputs.RefreshTokenInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	tokens, err := h.RefreshUseCase.Refresh(c, input.RefreshToken)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, toAuthSuccessResponse(tokens))
+}
+
+// Logout godoc
+// @Summary Revokes the current access token and its session
+// @Tags auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims := GetTokenClaimsFromContext(c)
+	if claims == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.TokenRevoker.RevokeToken(c, claims); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// LogoutAll godoc
+// @Summary Revokes every session of the current customer
+// @Tags auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/logout/all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	claims := GetTokenClaimsFromContext(c)
+	if claims == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.TokenRevoker.RevokeAllForCustomer(c, claims.Subject); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+func toAuthSuccessResponse(tokens *domain.AuthTokens) outputs.AuthSuccessResponse {
+	return outputs.AuthSuccessResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+		MFARequired:  tokens.MFAToken != "",
+		MFAToken:     tokens.MFAToken,
+	}
+}
+
+func NewAuthHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	uc domain.Authenticator,
+	refreshUC domain.TokenRefresher,
+	tokenRevoker domain.TokenRevoker,
+) {
 	handler := &AuthHandler{
-		AuthUseCase: uc,
+		AuthUseCase:    uc,
+		RefreshUseCase: refreshUC,
+		TokenRevoker:   tokenRevoker,
 	}
 
 	authRoutes := r.Group("/auth")
 	authRoutes.POST("/login", handler.PasswordAuthentication)
+	authRoutes.POST("/refresh", handler.RefreshToken)
+	authRoutes.POST("/logout", auth, handler.Logout)
+	authRoutes.POST("/logout/all", auth, handler.LogoutAll)
 }
@@ -14,6 +14,8 @@ type CunstomerHandler struct {
 	showcustomerUC   domain.ShowCustomerDataUC
 	updatecustomerUC domain.UpdateCustomerUC
 	deleteCustomerUC domain.DeleteCustomerUC
+	changePasswordUC domain.ChangeCustomerPasswordUC
+	restoreUC        domain.RestoreCustomerUC
 }
 
 func NewCustomerHandler(
@@ -23,12 +25,16 @@ func NewCustomerHandler(
 	showCustomeruc domain.ShowCustomerDataUC,
 	updateCustomerUC domain.UpdateCustomerUC,
 	deleteCustomerUC domain.DeleteCustomerUC,
+	changePasswordUC domain.ChangeCustomerPasswordUC,
+	restoreUC domain.RestoreCustomerUC,
 ) *gin.RouterGroup {
 	handler := &CunstomerHandler{
 		createCustomerUC: uc,
 		showcustomerUC:   showCustomeruc,
 		updatecustomerUC: updateCustomerUC,
 		deleteCustomerUC: deleteCustomerUC,
+		changePasswordUC: changePasswordUC,
+		restoreUC:        restoreUC,
 	}
 
 	customerRoutes := r.Group("/customers")
@@ -36,6 +42,8 @@ func NewCustomerHandler(
 	customerRoutes.GET("/:customerId", auth, handler.ShowCustomerData)
 	customerRoutes.PATCH("/:customerId", auth, handler.UpdateCustomer)
 	customerRoutes.DELETE("/:customerId", auth, handler.DeleteCustomer)
+	customerRoutes.POST("/:customerId/password", auth, handler.ChangePassword)
+	customerRoutes.POST("/:customerId/restore", auth, handler.RestoreCustomer)
 
 	return customerRoutes
 
@@ -164,6 +172,68 @@ func (h *CunstomerHandler) DeleteCustomer(c *gin.Context) {
 	return
 }
 
+// ChangePassword godoc
+// @Summary Changes the password of the given customer
+// @Description Every existing session of the customer is revoked after the change
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param passwords body inputs.ChangePasswordRequest true "current and new password"
+// @Security BearerAuth
+// @Param customerId path string true "Customer ID"
+// @Success 204
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/password [post]
+func (h *CunstomerHandler) ChangePassword(c *gin.Context) {
+	h.ensureParams(c)
+	currentCustomer := GetCustomerFromContext(c)
+
+	var data inputs.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	err := h.changePasswordUC.ChangePassword(c, currentCustomer.ID, c.Param("customerId"), domain.PasswordChange(data))
+
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// RestoreCustomer godoc
+// @Summary Restores a customer deleted during the grace period
+// @Description Meant for support and admins, customers restore their own account by logging in
+// @Tags customers
+// @Produce json
+// @Security BearerAuth
+// @Param customerId path string true "Customer ID"
+// @Success 200 {object} domain.OutgoingCustomer
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 403 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/restore [post]
+func (h *CunstomerHandler) RestoreCustomer(c *gin.Context) {
+	h.ensureParams(c)
+	currentCustomer := GetCustomerFromContext(c)
+
+	res, err := h.restoreUC.RestoreCustomer(c, currentCustomer.ID, c.Param("customerId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, res)
+}
+
 func (h CunstomerHandler) ensureParams(c *gin.Context) {
 	cid := c.Param("customerId")
 
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type dataExportHandler struct {
+	startDataExportUC    domain.StartDataExportUC
+	getDataExportUC      domain.GetDataExportUC
+	downloadDataExportUC domain.DownloadDataExportUC
+}
+
+func SetupDataExportHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	startDataExportUC domain.StartDataExportUC,
+	getDataExportUC domain.GetDataExportUC,
+	downloadDataExportUC domain.DownloadDataExportUC,
+) {
+	handler := &dataExportHandler{
+		startDataExportUC:    startDataExportUC,
+		getDataExportUC:      getDataExportUC,
+		downloadDataExportUC: downloadDataExportUC,
+	}
+
+	exportRoutes := r.Group("/:customerId/export")
+	exportRoutes.Use(auth)
+	exportRoutes.GET("", handler.StartDataExport)
+	exportRoutes.GET("/:jobId", handler.GetDataExport)
+	exportRoutes.GET("/:jobId/download", handler.DownloadDataExport)
+}
+
+// StartDataExport godoc
+// @Summary Starts an export of everything stored about the customer
+// @Description The export is built in the background, poll the status URL until it is ready. Asking again while an export is pending returns the same job
+// @Tags customers
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Success 202 {object} outputs.DataExportResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/export [get]
+func (h *dataExportHandler) StartDataExport(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	job, err := h.startDataExportUC.StartDataExport(c, currentCustomer.ID, c.Param("customerId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	out := dataExportResponse(job)
+	c.Header("Location", out.StatusURL)
+	c.JSON(202, out)
+}
+
+// GetDataExport godoc
+// @Summary Shows the status of a data export
+// @Tags customers
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param jobId path string true "Export job ID"
+// @Success 200 {object} outputs.DataExportResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/export/{jobId} [get]
+func (h *dataExportHandler) GetDataExport(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	job, err := h.getDataExportUC.GetDataExport(c, currentCustomer.ID, c.Param("customerId"), c.Param("jobId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, dataExportResponse(job))
+}
+
+// DownloadDataExport godoc
+// @Summary Downloads a ready data export as a JSON file
+// @Tags customers
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param jobId path string true "Export job ID"
+// @Success 200 {object} domain.CustomerDataExport
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/export/{jobId}/download [get]
+func (h *dataExportHandler) DownloadDataExport(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	jobID := c.Param("jobId")
+	artifact, err := h.downloadDataExportUC.DownloadDataExport(c, currentCustomer.ID, c.Param("customerId"), jobID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"data-export-%s.json\"", jobID))
+	c.Data(200, "application/json", artifact)
+}
+
+func dataExportResponse(job *domain.DataExportJob) outputs.DataExportResponse {
+	statusURL := fmt.Sprintf("/api/customers/%s/export/%s", job.CustomerID, job.ID)
+	out := outputs.DataExportResponse{
+		JobID:     job.ID,
+		Status:    string(job.Status),
+		CreatedAt: job.CreatedAt,
+		ExpiresAt: job.ExpiresAt,
+		StatusURL: statusURL,
+		Error:     job.Error,
+	}
+
+	if !job.CompletedAt.IsZero() {
+		out.CompletedAt = &job.CompletedAt
+	}
+
+	if job.Status == domain.DataExportReady {
+		out.DownloadURL = statusURL + "/download"
+	}
+
+	return out
+}
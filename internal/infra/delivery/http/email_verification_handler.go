@@ -0,0 +1,73 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type emailVerificationHandler struct {
+	verifyUC domain.VerifyEmailUC
+	resendUC domain.ResendEmailVerificationUC
+}
+
+func NewEmailVerificationHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	verifyUC domain.VerifyEmailUC,
+	resendUC domain.ResendEmailVerificationUC,
+) {
+	handler := &emailVerificationHandler{
+		verifyUC: verifyUC,
+		resendUC: resendUC,
+	}
+
+	verifyRoutes := r.Group("/auth/verify")
+	verifyRoutes.GET("", handler.VerifyEmail)
+	verifyRoutes.POST("/resend", auth, handler.ResendVerification)
+}
+
+// VerifyEmail godoc
+// @Summary Confirms the customer email address
+// @Description This is the link sent by email after signup or after an email change
+// @Tags auth
+// @Produce json
+// @Param token query string true "verification token"
+// @Success 204
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/verify [get]
+func (h *emailVerificationHandler) VerifyEmail(c *gin.Context) {
+	if err := h.verifyUC.VerifyEmail(c, c.Query("token")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// ResendVerification godoc
+// @Summary Sends a new verification link to the current customer
+// @Description Any previously sent link stops working
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 202
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/verify/resend [post]
+func (h *emailVerificationHandler) ResendVerification(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.resendUC.ResendVerification(c, currentCustomer.ID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(202)
+}
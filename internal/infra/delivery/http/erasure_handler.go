@@ -0,0 +1,86 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type erasureHandler struct {
+	eraseCustomerUC domain.EraseCustomerUC
+	verifyReceiptUC domain.VerifyErasureReceiptUC
+}
+
+func NewErasureHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	eraseCustomerUC domain.EraseCustomerUC,
+	verifyReceiptUC domain.VerifyErasureReceiptUC,
+) {
+	handler := &erasureHandler{
+		eraseCustomerUC: eraseCustomerUC,
+		verifyReceiptUC: verifyReceiptUC,
+	}
+
+	r.POST("/customers/:customerId/erase", auth, handler.EraseCustomer)
+	r.POST("/erasure-receipts/verify", handler.VerifyReceipt)
+}
+
+// EraseCustomer godoc
+// @Summary Permanently erases the personal data of a customer
+// @Description Name, email and credentials are scrubbed, wishlists are deleted or detached depending on the retention policy. Erasing again returns the same receipt
+// @Tags customers
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Success 200 {object} domain.ErasureReceipt
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/erase [post]
+func (h *erasureHandler) EraseCustomer(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	receipt, err := h.eraseCustomerUC.EraseCustomer(c, currentCustomer.ID, c.Param("customerId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, receipt)
+}
+
+// VerifyReceipt godoc
+// @Summary Tells whether an erasure receipt is genuine
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param receipt body inputs.ErasureReceiptInput true "receipt returned by the erase endpoint"
+// @Success 200 {object} outputs.ErasureVerificationResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/erasure-receipts/verify [post]
+func (h *erasureHandler) VerifyReceipt(c *gin.Context) {
+	var input inputs.ErasureReceiptInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	receipt := domain.ErasureReceipt(input)
+	valid, err := h.verifyReceiptUC.VerifyErasureReceipt(c, &receipt)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, outputs.ErasureVerificationResponse{Valid: valid})
+}
@@ -3,6 +3,8 @@ package http
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ydoro/wishlist/internal/domain"
@@ -13,7 +15,19 @@ import (
 func HandleError(c *gin.Context, err error) {
 	if err != nil {
 		if e.IsValidationError(err) {
-			c.JSON(400, outputs.ErrorResponse{
+			response := outputs.ErrorResponse{
+				Message: err.Error(),
+			}
+			if list, ok := err.(e.ValidationErrors); ok {
+				for _, fieldErr := range list {
+					response.Errors = append(response.Errors, outputs.FieldError{Field: fieldErr.Field, Error: fieldErr.Err})
+				}
+			}
+			c.JSON(400, response)
+			return
+		}
+		if e.IsAuthenticationError(err) || e.IsTokenExpiredError(err) {
+			c.JSON(401, outputs.ErrorResponse{
 				Message: err.Error(),
 			})
 			return
@@ -24,6 +38,26 @@ func HandleError(c *gin.Context, err error) {
 			})
 			return
 		}
+		if e.IsForbiddenError(err) {
+			c.JSON(403, outputs.ErrorResponse{
+				Message: err.Error(),
+			})
+			return
+		}
+		if e.IsConflictError(err) {
+			c.JSON(409, outputs.ErrorResponse{
+				Message: err.Error(),
+			})
+			return
+		}
+		if e.IsTooManyRequestsError(err) {
+			retryAfter := err.(*e.TooManyRequestsError).RetryAfter
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(429, outputs.ErrorResponse{
+				Message: err.Error(),
+			})
+			return
+		}
 		if e.IsNotFoundError(err) {
 			c.JSON(404, outputs.ErrorResponse{
 				Message: err.Error(),
@@ -54,3 +88,17 @@ func GetCustomerFromContext(c *gin.Context) *domain.OutgoingCustomer {
 
 	return &currentCustomer
 }
+
+func GetTokenClaimsFromContext(c *gin.Context) *domain.TokenClaims {
+	claims, ok := c.Get("tokenClaims")
+	if !ok {
+		return nil
+	}
+
+	tokenClaims, ok := claims.(*domain.TokenClaims)
+	if !ok {
+		return nil
+	}
+
+	return tokenClaims
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type jwksHandler struct {
+	keyProvider domain.PublicKeyProvider
+}
+
+func NewJWKSHandler(r *gin.Engine, keyProvider domain.PublicKeyProvider) {
+	handler := &jwksHandler{
+		keyProvider: keyProvider,
+	}
+
+	r.GET("/.well-known/jwks.json", handler.JWKS)
+}
+
+// JWKS godoc
+// @Summary Public keys used to sign access tokens
+// @Description Downstream services can verify access tokens with these keys, the kid header of a token tells which key signed it
+// @Tags auth
+// @Produce json
+// @Success 200 {object} domain.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *jwksHandler) JWKS(c *gin.Context) {
+	// short enough for clients to pick up a rotated key before the old one is dropped
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(200, h.keyProvider.PublicKeys())
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type magicLinkHandler struct {
+	requestUC     domain.RequestMagicLinkUC
+	authenticator domain.Authenticator
+}
+
+func NewMagicLinkHandler(
+	r *gin.RouterGroup,
+	requestUC domain.RequestMagicLinkUC,
+	authenticator domain.Authenticator,
+) {
+	handler := &magicLinkHandler{
+		requestUC:     requestUC,
+		authenticator: authenticator,
+	}
+
+	magicLinkRoutes := r.Group("/auth/magic-link")
+	magicLinkRoutes.POST("", handler.RequestMagicLink)
+	magicLinkRoutes.POST("/verify", handler.Verify)
+}
+
+// RequestMagicLink godoc
+// @Summary Emails a single use login link to the given email
+// @Description The response is the same whether the email is registered or not, the email is sent in the background. Requests are limited per email and per client IP
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param email body inputs.MagicLinkRequestInput true "customer email"
+// @Success 202
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 429 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/magic-link [post]
+func (h *magicLinkHandler) RequestMagicLink(c *gin.Context) {
+	var input inputs.MagicLinkRequestInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	if err := h.requestUC.RequestMagicLink(c, input.Email, c.ClientIP()); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(202)
+}
+
+// Verify godoc
+// @Summary Exchanges the token of a magic link for an access token
+// @Description It is a POST so mail scanners prefetching the link can't consume the token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body inputs.MagicLinkAuth true "token from the emailed link"
+// @Success 200 {object} outputs.AuthSuccessResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/magic-link/verify [post]
+func (h *magicLinkHandler) Verify(c *gin.Context) {
+	var credentials inputs.MagicLinkAuth
+
+	if err := c.ShouldBindJSON(&credentials); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+	credentials.IP = c.ClientIP()
+	credentials.UserAgent = c.Request.UserAgent()
+
+	tokens, err := h.authenticator.Authenticate(c, credentials)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, toAuthSuccessResponse(tokens))
+}
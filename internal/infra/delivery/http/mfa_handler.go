@@ -0,0 +1,166 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type mfaHandler struct {
+	verifyUC     domain.VerifyMFAUC
+	enrollmentUC domain.TOTPEnrollmentUC
+}
+
+func NewMFAHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	verifyUC domain.VerifyMFAUC,
+	enrollmentUC domain.TOTPEnrollmentUC,
+) {
+	handler := &mfaHandler{
+		verifyUC:     verifyUC,
+		enrollmentUC: enrollmentUC,
+	}
+
+	mfaRoutes := r.Group("/auth/mfa")
+	mfaRoutes.POST("/verify", handler.VerifyMFA)
+	mfaRoutes.POST("/totp", auth, handler.StartEnrollment)
+	mfaRoutes.POST("/totp/confirm", auth, handler.ConfirmEnrollment)
+	mfaRoutes.POST("/totp/disable", auth, handler.Disable)
+}
+
+// VerifyMFA godoc
+// @Summary Swaps the mfa token returned by the login and a second factor for the access and refresh tokens
+// @Description The code is either a TOTP code or one of the recovery codes
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param mfa body inputs.MFAVerifyInput true "mfa token and code"
+// @Success 200 {object} outputs.AuthSuccessResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/mfa/verify [post]
+func (h *mfaHandler) VerifyMFA(c *gin.Context) {
+	var input inputs.MFAVerifyInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	tokens, err := h.verifyUC.VerifyMFA(c, input.MFAToken, input.Code, domain.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, toAuthSuccessResponse(tokens))
+}
+
+// StartEnrollment godoc
+// @Summary Starts the TOTP enrollment of the current customer
+// @Description The provisioning URI can be shown as a QR code, TOTP is only enabled after the confirmation
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} outputs.MFAEnrollmentResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/mfa/totp [post]
+func (h *mfaHandler) StartEnrollment(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	enrollment, err := h.enrollmentUC.StartEnrollment(c, currentCustomer.ID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, outputs.MFAEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	})
+}
+
+// ConfirmEnrollment godoc
+// @Summary Enables TOTP with a first code from the authenticator app
+// @Description The recovery codes are only returned once
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param code body inputs.MFACodeInput true "TOTP code"
+// @Success 200 {object} outputs.RecoveryCodesResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/mfa/totp/confirm [post]
+func (h *mfaHandler) ConfirmEnrollment(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	var input inputs.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	codes, err := h.enrollmentUC.ConfirmEnrollment(c, currentCustomer.ID, input.Code)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, outputs.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// Disable godoc
+// @Summary Disables TOTP for the current customer
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Param code body inputs.MFACodeInput true "TOTP or recovery code"
+// @Success 204
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/mfa/totp/disable [post]
+func (h *mfaHandler) Disable(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	var input inputs.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	if err := h.enrollmentUC.Disable(c, currentCustomer.ID, input.Code); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
@@ -1,20 +1,34 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
 	"github.com/ydoro/wishlist/internal/presentation/outputs"
 )
 
 type AuthMiddleware struct {
-	Decrypter domain.Decrypter
+	Verifier          domain.TokenVerifier
+	RevocationChecker domain.RevokedTokenChecker
+	APIKeys           domain.APIKeyAuthenticator
+	SessionActivity   domain.SessionActivityRecorder
 }
 
-func NewAuthMiddleware(decrypter domain.Decrypter) *AuthMiddleware {
+func NewAuthMiddleware(
+	verifier domain.TokenVerifier,
+	revocationChecker domain.RevokedTokenChecker,
+	apiKeys domain.APIKeyAuthenticator,
+	sessionActivity domain.SessionActivityRecorder,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		Decrypter: decrypter,
+		Verifier:          verifier,
+		RevocationChecker: revocationChecker,
+		APIKeys:           apiKeys,
+		SessionActivity:   sessionActivity,
 	}
 }
 
@@ -22,20 +36,111 @@ func (h *AuthMiddleware) Handle(c *gin.Context) {
 	token := c.Request.Header.Get("Authorization")
 
 	if len(strings.Split(token, "Bearer")) < 2 {
-		c.JSON(401, outputs.ErrorResponse{
+		c.AbortWithStatusJSON(401, outputs.ErrorResponse{
 			Message: "Unauthorized",
 		})
 		return
 	}
 
-	data, err := h.Decrypter.Decrypt(strings.TrimSpace(strings.Split(token, "Bearer")[1]))
+	claims, err := h.Verifier.Verify(strings.TrimSpace(strings.Split(token, "Bearer")[1]))
 
 	if err != nil {
-		c.JSON(401, outputs.ErrorResponse{
+		// expired tokens get a distinct message so clients know they should use their refresh token
+		if e.IsTokenExpiredError(err) {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token", error_description="token expired"`)
+			c.AbortWithStatusJSON(401, outputs.ErrorResponse{
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(401, outputs.ErrorResponse{
 			Message: "Unauthorized",
 		})
 		return
 	}
 
-	c.Set("currentCustomer", data)
+	revoked, err := h.RevocationChecker.IsRevoked(c, claims)
+	if err != nil {
+		fmt.Printf("[auth_middleware] ERROR checking token revocation: %v\n", err)
+		c.AbortWithStatusJSON(500, outputs.ErrorResponse{
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	if revoked {
+		c.AbortWithStatusJSON(401, outputs.ErrorResponse{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	// a failure only leaves last_seen_at behind, it is no reason to reject the request
+	if claims.SessionID != "" {
+		if err := h.SessionActivity.RecordActivity(c, claims.SessionID); err != nil {
+			fmt.Printf("[auth_middleware] ERROR recording session activity: %v\n", err)
+		}
+	}
+
+	c.Set("currentCustomer", claims.Data)
+	c.Set("tokenClaims", claims)
+}
+
+// AllowAPIKey accepts `Authorization: ApiKey <key>` next to Bearer tokens, the key must grant the scope.
+// Routes only reachable through Handle never accept API keys, e.g. the ones managing credentials
+func (h *AuthMiddleware) AllowAPIKey(scope domain.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Request.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "ApiKey ") {
+			h.Handle(c)
+			return
+		}
+
+		key, customer, err := h.APIKeys.AuthenticateAPIKey(c, strings.TrimSpace(strings.TrimPrefix(header, "ApiKey ")))
+		if err != nil {
+			if !e.IsAuthenticationError(err) {
+				fmt.Printf("[auth_middleware] ERROR authenticating api key: %v\n", err)
+				c.AbortWithStatusJSON(500, outputs.ErrorResponse{
+					Message: "Internal server error",
+				})
+				return
+			}
+
+			c.AbortWithStatusJSON(401, outputs.ErrorResponse{
+				Message: "Unauthorized",
+			})
+			return
+		}
+
+		if !key.HasScope(scope) {
+			c.AbortWithStatusJSON(403, outputs.ErrorResponse{
+				Message: fmt.Sprintf("the api key is missing the %s scope", scope),
+			})
+			return
+		}
+
+		// the same payload the access tokens carry so handlers don't care how the customer authenticated
+		data, _ := json.Marshal(&domain.OutgoingCustomer{
+			ID:    customer.ID,
+			Name:  customer.Name,
+			Email: customer.Email,
+			Role:  customer.Role,
+		})
+
+		c.Set("currentCustomer", string(data))
+		c.Set("apiKey", key)
+	}
+}
+
+// Optional only runs the auth handler when the request carries credentials, public routes
+// still reject invalid ones
+func Optional(auth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Header.Get("Authorization") == "" {
+			return
+		}
+
+		auth(c)
+	}
 }
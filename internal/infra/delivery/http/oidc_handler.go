@@ -0,0 +1,83 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type oidcHandler struct {
+	startLoginUC  domain.StartOIDCLoginUC
+	authenticator domain.Authenticator
+}
+
+func NewOIDCHandler(
+	r *gin.RouterGroup,
+	startLoginUC domain.StartOIDCLoginUC,
+	authenticator domain.Authenticator,
+) {
+	handler := &oidcHandler{
+		startLoginUC:  startLoginUC,
+		authenticator: authenticator,
+	}
+
+	oidcRoutes := r.Group("/auth/oidc")
+	oidcRoutes.GET("/login", handler.Login)
+	oidcRoutes.GET("/callback", handler.Callback)
+}
+
+// Login godoc
+// @Summary Redirects to the identity provider login page
+// @Tags auth
+// @Success 302
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/oidc/login [get]
+func (h *oidcHandler) Login(c *gin.Context) {
+	authURL, err := h.startLoginUC.StartLogin(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Redirect(302, authURL)
+}
+
+// Callback godoc
+// @Summary Finishes the identity provider login
+// @Description Customers are matched by provider identity first, then by verified email, otherwise a customer without password is created. Customers with a second factor get an mfa_token to verify instead of the tokens
+// @Tags auth
+// @Produce json
+// @Param code query string true "authorization code"
+// @Param state query string true "state returned by the provider"
+// @Success 200 {object} outputs.AuthSuccessResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/oidc/callback [get]
+func (h *oidcHandler) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.JSON(401, outputs.ErrorResponse{
+			Message: "Login cancelled by the identity provider: " + providerErr,
+		})
+		return
+	}
+
+	var callback inputs.OIDCCallback
+	if err := c.ShouldBindQuery(&callback); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	callback.IP = c.ClientIP()
+	callback.UserAgent = c.Request.UserAgent()
+
+	tokens, err := h.authenticator.Authenticate(c, callback)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, toAuthSuccessResponse(tokens))
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type passwordResetHandler struct {
+	requestResetUC domain.RequestPasswordResetUC
+	resetUC        domain.ResetPasswordUC
+}
+
+func NewPasswordResetHandler(
+	r *gin.RouterGroup,
+	requestResetUC domain.RequestPasswordResetUC,
+	resetUC domain.ResetPasswordUC,
+) {
+	handler := &passwordResetHandler{
+		requestResetUC: requestResetUC,
+		resetUC:        resetUC,
+	}
+
+	passwordRoutes := r.Group("/auth/password")
+	passwordRoutes.POST("/forgot", handler.ForgotPassword)
+	passwordRoutes.POST("/reset", handler.ResetPassword)
+}
+
+// ForgotPassword godoc
+// @Summary Sends a password reset link to the given email
+// @Description The response is the same whether the email is registered or not, the email is sent in the background. Requests are limited per email and per client IP
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param email body inputs.ForgotPasswordInput true "customer email"
+// @Success 202
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 429 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/password/forgot [post]
+func (h *passwordResetHandler) ForgotPassword(c *gin.Context) {
+	var input inputs.ForgotPasswordInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	if err := h.requestResetUC.RequestPasswordReset(c, input.Email, c.ClientIP()); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(202)
+}
+
+// ResetPassword godoc
+// @Summary Sets a new password using a reset token
+// @Description Every existing session of the customer is revoked after the reset
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param reset body inputs.ResetPasswordInput true "reset token and new password"
+// @Success 204
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/auth/password/reset [post]
+func (h *passwordResetHandler) ResetPassword(c *gin.Context) {
+	var input inputs.ResetPasswordInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	if err := h.resetUC.ResetPassword(c, input.Token, input.NewPassword); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
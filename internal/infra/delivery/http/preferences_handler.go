@@ -0,0 +1,102 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type preferencesHandler struct {
+	getPreferencesUC    domain.GetPreferencesUC
+	updatePreferencesUC domain.UpdatePreferencesUC
+}
+
+func SetupPreferencesHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	getPreferencesUC domain.GetPreferencesUC,
+	updatePreferencesUC domain.UpdatePreferencesUC,
+) {
+	handler := &preferencesHandler{
+		getPreferencesUC:    getPreferencesUC,
+		updatePreferencesUC: updatePreferencesUC,
+	}
+
+	preferencesRoutes := r.Group("/:customerId/preferences")
+	preferencesRoutes.Use(auth)
+	preferencesRoutes.GET("", handler.GetPreferences)
+	preferencesRoutes.PUT("", handler.UpdatePreferences)
+}
+
+// GetPreferences godoc
+// @Summary Shows the preferences and notification settings of the customer
+// @Description Customers who never saved their preferences get the defaults
+// @Tags customers
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Success 200 {object} domain.Preferences
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/preferences [get]
+func (h *preferencesHandler) GetPreferences(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	preferences, err := h.getPreferencesUC.GetPreferences(c, currentCustomer.ID, c.Param("customerId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, preferences)
+}
+
+// UpdatePreferences godoc
+// @Summary Replaces the preferences and notification settings of the customer
+// @Tags customers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param preferences body inputs.PreferencesRequest true "every preference, omitted notifications are turned off"
+// @Success 200 {object} domain.Preferences
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/preferences [put]
+func (h *preferencesHandler) UpdatePreferences(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	var input inputs.PreferencesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	preferences, err := h.updatePreferencesUC.UpdatePreferences(c, currentCustomer.ID, c.Param("customerId"), domain.PreferencesUpdate{
+		Locale:             input.Locale,
+		Currency:           input.Currency,
+		WishlistVisibility: domain.WishlistVisibility(input.WishlistVisibility),
+		Notifications:      domain.NotificationSettings(input.Notifications),
+		RevealReservations: input.RevealReservations,
+	})
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, preferences)
+}
@@ -17,6 +17,7 @@ type productHandler struct {
 
 func NewProductHandler(
 	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
 	getProductUc domain.GetProductUseCase,
 	listProductUc domain.ListProductsUseCase,
 ) *gin.RouterGroup {
@@ -26,6 +27,7 @@ func NewProductHandler(
 	}
 
 	productRoutes := r.Group("/products")
+	productRoutes.Use(auth)
 	productRoutes.GET("/:productId", handler.GetProduct)
 	productRoutes.GET("/", handler.ListProducts)
 
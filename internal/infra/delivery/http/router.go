@@ -5,16 +5,32 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/infra/delivery/http/middleware"
 )
 
 func SetupRoutes(
 	r *gin.Engine,
 	customerCreation domain.CreateCustomerUC,
 	userAuthentication domain.Authenticator,
-	authMiddleware gin.HandlerFunc,
+	tokenRefresher domain.TokenRefresher,
+	tokenRevoker domain.TokenRevoker,
+	keyProvider domain.PublicKeyProvider,
+	auth *middleware.AuthMiddleware,
 	customerGetter domain.ShowCustomerDataUC,
 	customerUpdater domain.UpdateCustomerUC,
 	customerDeleter domain.DeleteCustomerUC,
+	passwordChanger domain.ChangeCustomerPasswordUC,
+	customerRestorer domain.RestoreCustomerUC,
+	passwordResetRequester domain.RequestPasswordResetUC,
+	passwordResetter domain.ResetPasswordUC,
+	magicLinkRequester domain.RequestMagicLinkUC,
+	magicLinkAuthentication domain.Authenticator,
+	emailVerifier domain.VerifyEmailUC,
+	emailVerificationResender domain.ResendEmailVerificationUC,
+	oidcLoginStarter domain.StartOIDCLoginUC,
+	oidcAuthentication domain.Authenticator,
+	mfaVerifier domain.VerifyMFAUC,
+	totpEnrollment domain.TOTPEnrollmentUC,
 	wishlistCreator domain.CreateWishlistUseCase,
 	wishlistDeleter domain.DeleteWishlistUseCase,
 	wishlistGetter domain.ShowWishlistUseCase,
@@ -22,24 +38,74 @@ func SetupRoutes(
 	productGetter domain.GetProductUseCase,
 	productLister domain.ListProductsUseCase,
 	wishlistLister domain.ListUserWishlists,
+	wishlistItemAdder domain.AddWishlistItemUseCase,
+	wishlistItemRemover domain.RemoveWishlistItemUseCase,
+	apiKeyCreator domain.CreateAPIKeyUC,
+	apiKeyLister domain.ListAPIKeysUC,
+	apiKeyRevoker domain.RevokeAPIKeyUC,
+	sessionLister domain.ListSessionsUC,
+	sessionRevoker domain.RevokeSessionUC,
+	dataExportStarter domain.StartDataExportUC,
+	dataExportGetter domain.GetDataExportUC,
+	dataExportDownloader domain.DownloadDataExportUC,
+	customerEraser domain.EraseCustomerUC,
+	erasureVerifier domain.VerifyErasureReceiptUC,
+	customerSearcher domain.SearchCustomersUC,
+	preferencesGetter domain.GetPreferencesUC,
+	preferencesUpdater domain.UpdatePreferencesUC,
+	wishlistShareCreator domain.CreateWishlistShareUC,
+	wishlistShareLister domain.ListWishlistSharesUC,
+	wishlistShareRevoker domain.RevokeWishlistShareUC,
+	sharedWishlistGetter domain.ShowSharedWishlistUC,
+	collaboratorInviter domain.InviteCollaboratorUC,
+	invitationAccepter domain.AcceptInvitationUC,
+	collaboratorLister domain.ListCollaboratorsUC,
+	collaboratorRemover domain.RemoveCollaboratorUC,
+	wishlistItemReserver domain.ReserveWishlistItemUC,
+	wishlistItemReleaser domain.ReleaseWishlistItemUC,
 
 ) *gin.Engine {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	NewJWKSHandler(r, keyProvider)
+
+	// API keys are only accepted by the routes granting a scope, everything else needs a customer session
+	authMiddleware := auth.Handle
+
 	api := r.Group("/api")
-	NewAuthHandler(api, userAuthentication)
-	NewProductHandler(api, productGetter, productLister)
+	NewAuthHandler(api, authMiddleware, userAuthentication, tokenRefresher, tokenRevoker)
+	NewPasswordResetHandler(api, passwordResetRequester, passwordResetter)
+	NewMagicLinkHandler(api, magicLinkRequester, magicLinkAuthentication)
+	NewEmailVerificationHandler(api, authMiddleware, emailVerifier, emailVerificationResender)
+	NewMFAHandler(api, authMiddleware, mfaVerifier, totpEnrollment)
+	// social login is optional, it is only enabled when an issuer is configured
+	if oidcLoginStarter != nil && oidcAuthentication != nil {
+		NewOIDCHandler(api, oidcLoginStarter, oidcAuthentication)
+	}
+	NewProductHandler(api, middleware.Optional(auth.AllowAPIKey(domain.APIKeyScopeProducts)), productGetter, productLister)
 
-	customerRoutes := NewCustomerHandler(api, customerCreation, authMiddleware, customerGetter, customerUpdater, customerDeleter)
+	customerRoutes := NewCustomerHandler(api, customerCreation, authMiddleware, customerGetter, customerUpdater, customerDeleter, passwordChanger, customerRestorer)
+	NewErasureHandler(api, authMiddleware, customerEraser, erasureVerifier)
+	NewAdminCustomerHandler(api, authMiddleware, customerSearcher)
 	SetupWishlistHandler(
 		customerRoutes,
-		authMiddleware,
+		auth.AllowAPIKey(domain.APIKeyScopeWishlistsRead),
+		auth.AllowAPIKey(domain.APIKeyScopeWishlistsWrite),
 		wishlistCreator,
 		wishlistDeleter,
 		wishlistGetter,
 		wishlistUpdater,
 		wishlistLister,
+		wishlistItemAdder,
+		wishlistItemRemover,
 	)
+	NewWishlistShareHandler(api, authMiddleware, wishlistShareCreator, wishlistShareLister, wishlistShareRevoker, sharedWishlistGetter)
+	NewWishlistReservationHandler(api, authMiddleware, wishlistItemReserver, wishlistItemReleaser)
+	SetupWishlistCollaboratorHandler(customerRoutes, authMiddleware, collaboratorInviter, invitationAccepter, collaboratorLister, collaboratorRemover)
+	SetupAPIKeyHandler(customerRoutes, authMiddleware, apiKeyCreator, apiKeyLister, apiKeyRevoker)
+	SetupSessionHandler(customerRoutes, authMiddleware, sessionLister, sessionRevoker)
+	SetupDataExportHandler(customerRoutes, authMiddleware, dataExportStarter, dataExportGetter, dataExportDownloader)
+	SetupPreferencesHandler(customerRoutes, authMiddleware, preferencesGetter, preferencesUpdater)
 
 	return r
 }
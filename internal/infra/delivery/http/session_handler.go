@@ -0,0 +1,100 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type sessionHandler struct {
+	listSessionsUC  domain.ListSessionsUC
+	revokeSessionUC domain.RevokeSessionUC
+}
+
+func SetupSessionHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	listSessionsUC domain.ListSessionsUC,
+	revokeSessionUC domain.RevokeSessionUC,
+) {
+	handler := &sessionHandler{
+		listSessionsUC:  listSessionsUC,
+		revokeSessionUC: revokeSessionUC,
+	}
+
+	sessionRoutes := r.Group("/:customerId/sessions")
+	sessionRoutes.Use(auth)
+	sessionRoutes.GET("/", handler.ListSessions)
+	sessionRoutes.DELETE("/:sessionId", handler.RevokeSession)
+}
+
+// ListSessions godoc
+// @Summary Lists the devices the customer is logged in on
+// @Tags sessions
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Success 200 {array} outputs.SessionResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/sessions [get]
+func (h *sessionHandler) ListSessions(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	sessions, err := h.listSessionsUC.ListSessions(c, currentCustomer.ID, c.Param("customerId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	currentSessionID := ""
+	if claims := GetTokenClaimsFromContext(c); claims != nil {
+		currentSessionID = claims.SessionID
+	}
+
+	out := make([]outputs.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		out[i] = outputs.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		}
+	}
+
+	c.JSON(200, out)
+}
+
+// RevokeSession godoc
+// @Summary Logs a device out
+// @Description The refresh token of the session stops working and its access tokens are rejected right away
+// @Tags sessions
+// @Security BearerAuth
+// @Param customerId path string true "Customer ID"
+// @Param sessionId path string true "Session ID"
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/sessions/{sessionId} [delete]
+func (h *sessionHandler) RevokeSession(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.revokeSessionUC.RevokeSession(c, currentCustomer.ID, c.Param("customerId"), c.Param("sessionId")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
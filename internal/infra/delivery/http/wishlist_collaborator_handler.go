@@ -0,0 +1,162 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type wishlistCollaboratorHandler struct {
+	inviteCollaboratorUC domain.InviteCollaboratorUC
+	acceptInvitationUC   domain.AcceptInvitationUC
+	listCollaboratorsUC  domain.ListCollaboratorsUC
+	removeCollaboratorUC domain.RemoveCollaboratorUC
+}
+
+func SetupWishlistCollaboratorHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	inviteCollaboratorUC domain.InviteCollaboratorUC,
+	acceptInvitationUC domain.AcceptInvitationUC,
+	listCollaboratorsUC domain.ListCollaboratorsUC,
+	removeCollaboratorUC domain.RemoveCollaboratorUC,
+) {
+	handler := &wishlistCollaboratorHandler{
+		inviteCollaboratorUC: inviteCollaboratorUC,
+		acceptInvitationUC:   acceptInvitationUC,
+		listCollaboratorsUC:  listCollaboratorsUC,
+		removeCollaboratorUC: removeCollaboratorUC,
+	}
+
+	collaboratorRoutes := r.Group("/:customerId/wishlists/:wishListId/collaborators")
+	collaboratorRoutes.Use(auth)
+	collaboratorRoutes.POST("", handler.InviteCollaborator)
+	collaboratorRoutes.GET("", handler.ListCollaborators)
+	collaboratorRoutes.POST("/accept", handler.AcceptInvitation)
+	collaboratorRoutes.DELETE("/:collaboratorId", handler.RemoveCollaborator)
+}
+
+// InviteCollaborator godoc
+// @Summary Invites a customer to view or edit a wishlist
+// @Description Only the owner invites collaborators, inviting someone again changes their role. The invitation is sent by email in the background, so the owner's email must be verified. The response is the same whether the email is registered or not, unknown emails are ignored
+// @Tags wishlists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param customerId path string true "Customer ID of the owner"
+// @Param wishListId path string true "Wishlist ID"
+// @Param invitation body inputs.InviteCollaboratorInput true "email of the customer and role"
+// @Success 202
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 403 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/collaborators [post]
+func (h *wishlistCollaboratorHandler) InviteCollaborator(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	var input inputs.InviteCollaboratorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	if err := h.inviteCollaboratorUC.InviteCollaborator(c, currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"), input.Email, domain.CollaboratorRole(input.Role)); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(202)
+}
+
+// ListCollaborators godoc
+// @Summary Lists the collaborators of a wishlist, pending invitations included
+// @Tags wishlists
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID of the owner"
+// @Param wishListId path string true "Wishlist ID"
+// @Success 200 {array} domain.WishlistCollaborator
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/collaborators [get]
+func (h *wishlistCollaboratorHandler) ListCollaborators(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	collaborators, err := h.listCollaboratorsUC.ListCollaborators(c, currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, collaborators)
+}
+
+// AcceptInvitation godoc
+// @Summary Accepts the invitation of the signed in customer to a wishlist
+// @Description Called by the invited customer, the path identifies the wishlist of the owner
+// @Tags wishlists
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID of the owner"
+// @Param wishListId path string true "Wishlist ID"
+// @Success 200 {object} domain.WishlistCollaborator
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/collaborators/accept [post]
+func (h *wishlistCollaboratorHandler) AcceptInvitation(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	collaborator, err := h.acceptInvitationUC.AcceptInvitation(c, currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, collaborator)
+}
+
+// RemoveCollaborator godoc
+// @Summary Removes a collaborator or cancels a pending invitation
+// @Tags wishlists
+// @Security BearerAuth
+// @Param customerId path string true "Customer ID of the owner"
+// @Param wishListId path string true "Wishlist ID"
+// @Param collaboratorId path string true "Customer ID of the collaborator"
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/collaborators/{collaboratorId} [delete]
+func (h *wishlistCollaboratorHandler) RemoveCollaborator(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.removeCollaboratorUC.RemoveCollaborator(c, currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"), c.Param("collaboratorId")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
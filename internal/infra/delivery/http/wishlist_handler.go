@@ -13,16 +13,21 @@ type wishlistHandler struct {
 	getWishlistUseCase    domain.ShowWishlistUseCase
 	updateWishlistUsecase domain.UpdateWishListUseCase
 	listWishlistUsecase   domain.ListUserWishlists
+	addItemUseCase        domain.AddWishlistItemUseCase
+	removeItemUseCase     domain.RemoveWishlistItemUseCase
 }
 
 func SetupWishlistHandler(
 	r *gin.RouterGroup,
-	auth gin.HandlerFunc,
+	readAuth gin.HandlerFunc,
+	writeAuth gin.HandlerFunc,
 	createWishlistUseCase domain.CreateWishlistUseCase,
 	deleteWishlistUseCase domain.DeleteWishlistUseCase,
 	getWishlistUseCase domain.ShowWishlistUseCase,
 	updateWishlistUsecase domain.UpdateWishListUseCase,
 	listWishlistUsecase domain.ListUserWishlists,
+	addItemUseCase domain.AddWishlistItemUseCase,
+	removeItemUseCase domain.RemoveWishlistItemUseCase,
 ) {
 	handler := &wishlistHandler{
 		createWishlistUseCase: createWishlistUseCase,
@@ -30,16 +35,19 @@ func SetupWishlistHandler(
 		getWishlistUseCase:    getWishlistUseCase,
 		updateWishlistUsecase: updateWishlistUsecase,
 		listWishlistUsecase:   listWishlistUsecase,
+		addItemUseCase:        addItemUseCase,
+		removeItemUseCase:     removeItemUseCase,
 	}
 
 	wishlistRoutes := r.Group("/:customerId/wishlists")
-	wishlistRoutes.Use(auth)
-	wishlistRoutes.POST("/", handler.CreateWishList)
-	wishlistRoutes.GET("/", handler.ListWishList)
-	wishlistRoutes.PUT("/:wishListId", handler.UpdateWishlist)
-	wishlistRoutes.PATCH("/:wishListId", handler.UpdateWishlist)
-	wishlistRoutes.DELETE("/:wishListId", handler.DeleteWishlist)
-	wishlistRoutes.GET("/:wishListId", handler.GetWishlist)
+	wishlistRoutes.POST("/", writeAuth, handler.CreateWishList)
+	wishlistRoutes.GET("/", readAuth, handler.ListWishList)
+	wishlistRoutes.PUT("/:wishListId", writeAuth, handler.UpdateWishlist)
+	wishlistRoutes.PATCH("/:wishListId", writeAuth, handler.UpdateWishlist)
+	wishlistRoutes.DELETE("/:wishListId", writeAuth, handler.DeleteWishlist)
+	wishlistRoutes.GET("/:wishListId", readAuth, handler.GetWishlist)
+	wishlistRoutes.POST("/:wishListId/items", writeAuth, handler.AddItem)
+	wishlistRoutes.DELETE("/:wishListId/items/:productId", writeAuth, handler.RemoveItem)
 
 }
 
@@ -87,7 +95,7 @@ func (h wishlistHandler) CreateWishList(c *gin.Context) {
 
 // UpdateWishlist godoc
 // @Summary update wishlist
-// @Description update wishlist, updates both title and items if given, if you want to add a single item you need to pass the whole wishlist
+// @Description update wishlist, updates both title and items if given, items replace the whole list keeping the metadata of products already in it, use the items endpoints to add or remove a single product
 // @Tags wishlists
 // @Accept json
 // @Produce json
@@ -119,7 +127,13 @@ func (h wishlistHandler) UpdateWishlist(c *gin.Context) {
 		Title:      input.Title,
 		ID:         c.Param("wishListId"),
 		CustomerId: c.Param("customerId"),
-		Items:      input.Items,
+	}
+
+	if input.Items != nil {
+		wl.Items = make([]domain.WishlistItem, 0, len(input.Items))
+		for _, productId := range input.Items {
+			wl.Items = append(wl.Items, domain.WishlistItem{ProductID: productId})
+		}
 	}
 
 	err := h.updateWishlistUsecase.UpdateWishlist(c.Request.Context(), currentCustomer.ID, wl)
@@ -220,6 +234,78 @@ func (h wishlistHandler) ListWishList(c *gin.Context) {
 
 }
 
+// AddItem godoc
+// @Summary Adds a single product to a wishlist
+// @Description Only the added product is validated, adding a product already in the wishlist does nothing. The quantity defaults to 1
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param wishListId path string true "Wishlist ID"
+// @Param item body inputs.AddWishlistItemInput true "Product to add"
+// @Success 204
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/items [post]
+// @securityDefinitions.apikey BearerAuth
+// @in Header
+// @name Authorization
+func (h wishlistHandler) AddItem(c *gin.Context) {
+	h.ensureParams(c)
+
+	var input inputs.AddWishlistItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	currentCustomer := GetCustomerFromContext(c)
+	err := h.addItemUseCase.AddWishlistItem(c.Request.Context(), currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"), domain.WishlistItem{
+		ProductID: input.ProductID,
+		Quantity:  input.Quantity,
+		Priority:  input.Priority,
+		Note:      input.Note,
+	})
+
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(204, gin.H{})
+	return
+}
+
+// RemoveItem godoc
+// @Summary Removes a single product from a wishlist
+// @Tags wishlists
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param wishListId path string true "Wishlist ID"
+// @Param productId path string true "Product ID"
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/items/{productId} [delete]
+// @securityDefinitions.apikey BearerAuth
+// @in Header
+// @name Authorization
+func (h wishlistHandler) RemoveItem(c *gin.Context) {
+	h.ensureParams(c)
+
+	currentCustomer := GetCustomerFromContext(c)
+	err := h.removeItemUseCase.RemoveWishlistItem(c.Request.Context(), currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"), c.Param("productId"))
+
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(204, gin.H{})
+	return
+}
+
 func (h wishlistHandler) ensureParams(c *gin.Context) {
 	cid := c.Param("customerId")
 	wid := c.Param("wishListId")
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+type wishlistReservationHandler struct {
+	reserveItemUC domain.ReserveWishlistItemUC
+	releaseItemUC domain.ReleaseWishlistItemUC
+}
+
+// NewWishlistReservationHandler registers the reservation routes under the shared wishlist route,
+// reserving needs a customer session so the reservation can be released later
+func NewWishlistReservationHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	reserveItemUC domain.ReserveWishlistItemUC,
+	releaseItemUC domain.ReleaseWishlistItemUC,
+) {
+	handler := &wishlistReservationHandler{
+		reserveItemUC: reserveItemUC,
+		releaseItemUC: releaseItemUC,
+	}
+
+	reservationRoutes := r.Group("/shared/wishlists/:token/items/:productId/reservation")
+	reservationRoutes.Use(auth)
+	reservationRoutes.POST("", handler.ReserveItem)
+	reservationRoutes.DELETE("", handler.ReleaseItem)
+}
+
+// ReserveItem godoc
+// @Summary Reserves an item of a shared wishlist
+// @Description Tells the other viewers the item is being bought, anonymous reservations hide who made them. The reservation expires after RESERVATION_TTL days, the owner can't reserve their own items
+// @Tags wishlists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param token path string true "Share token"
+// @Param productId path string true "Product ID"
+// @Param reservation body inputs.ReserveWishlistItemInput false "optional anonymity"
+// @Success 201 {object} domain.WishlistReservation
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 403 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 409 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/shared/wishlists/{token}/items/{productId}/reservation [post]
+func (h *wishlistReservationHandler) ReserveItem(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	// the body is optional, an empty one makes a reservation that shows who made it
+	var input inputs.ReserveWishlistItemInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	reservation, err := h.reserveItemUC.ReserveWishlistItem(c, currentCustomer.ID, c.Param("token"), c.Param("productId"), input.Anonymous)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, reservation)
+}
+
+// ReleaseItem godoc
+// @Summary Releases a reservation so someone else can buy the item
+// @Description Only who reserved the item can release it
+// @Tags wishlists
+// @Security BearerAuth
+// @Param token path string true "Share token"
+// @Param productId path string true "Product ID"
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/shared/wishlists/{token}/items/{productId}/reservation [delete]
+func (h *wishlistReservationHandler) ReleaseItem(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.releaseItemUC.ReleaseWishlistItem(c, currentCustomer.ID, c.Param("token"), c.Param("productId")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/infra/delivery/http/middleware"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/presentation/outputs"
+)
+
+const sharedWishlistPath = "/api/shared/wishlists/"
+
+type wishlistShareHandler struct {
+	createShareUC      domain.CreateWishlistShareUC
+	listSharesUC       domain.ListWishlistSharesUC
+	revokeShareUC      domain.RevokeWishlistShareUC
+	showSharedWishlist domain.ShowSharedWishlistUC
+}
+
+// NewWishlistShareHandler registers the share management routes and the public shared wishlist route,
+// auth must not accept API keys since a share exposes the wishlist to anyone
+func NewWishlistShareHandler(
+	r *gin.RouterGroup,
+	auth gin.HandlerFunc,
+	createShareUC domain.CreateWishlistShareUC,
+	listSharesUC domain.ListWishlistSharesUC,
+	revokeShareUC domain.RevokeWishlistShareUC,
+	showSharedWishlist domain.ShowSharedWishlistUC,
+) {
+	handler := &wishlistShareHandler{
+		createShareUC:      createShareUC,
+		listSharesUC:       listSharesUC,
+		revokeShareUC:      revokeShareUC,
+		showSharedWishlist: showSharedWishlist,
+	}
+
+	shareRoutes := r.Group("/customers/:customerId/wishlists/:wishListId/shares")
+	shareRoutes.Use(auth)
+	shareRoutes.POST("", handler.CreateShare)
+	shareRoutes.GET("", handler.ListShares)
+	shareRoutes.DELETE("/:shareId", handler.RevokeShare)
+
+	// signing in is optional, only signed in viewers see reservations
+	r.GET("/shared/wishlists/:token", middleware.Optional(auth), handler.ShowSharedWishlist)
+}
+
+// CreateShare godoc
+// @Summary Creates a share link for a wishlist
+// @Description The token is only returned once, anyone holding it can read the wishlist at /api/shared/wishlists/{token} until it expires or is revoked. The wishlist_visibility preference must be shared, the link stops working while it is private
+// @Tags wishlists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param wishListId path string true "Wishlist ID"
+// @Param share body inputs.CreateWishlistShareInput false "optional expiry"
+// @Success 201 {object} outputs.CreatedWishlistShareResponse
+// @Failure 400 {object} outputs.ErrorResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 403 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/shares [post]
+func (h *wishlistShareHandler) CreateShare(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	// the body is optional, an empty one creates a share that never expires
+	var input inputs.CreateWishlistShareInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(400, outputs.ErrorResponse{
+			Message: "Invalid input"})
+		return
+	}
+
+	share, err := h.createShareUC.CreateWishlistShare(c, currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"), input.ExpiresAt)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, outputs.CreatedWishlistShareResponse{
+		WishlistShareResponse: toWishlistShareResponse(&share.WishlistShare),
+		Token:                 share.Token,
+		Path:                  sharedWishlistPath + share.Token,
+	})
+}
+
+// ListShares godoc
+// @Summary Lists the active share links of a wishlist
+// @Description Expired shares are listed until they are revoked
+// @Tags wishlists
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param wishListId path string true "Wishlist ID"
+// @Success 200 {array} outputs.WishlistShareResponse
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/shares [get]
+func (h *wishlistShareHandler) ListShares(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	shares, err := h.listSharesUC.ListWishlistShares(c, currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	out := make([]outputs.WishlistShareResponse, len(shares))
+	for i, share := range shares {
+		out[i] = toWishlistShareResponse(share)
+	}
+
+	c.JSON(200, out)
+}
+
+// RevokeShare godoc
+// @Summary Revokes a share link, it stops working right away
+// @Tags wishlists
+// @Security BearerAuth
+// @Param customerId path string true "Customer ID"
+// @Param wishListId path string true "Wishlist ID"
+// @Param shareId path string true "Share ID"
+// @Success 204
+// @Failure 401 {object} outputs.ErrorResponse
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/customers/{customerId}/wishlists/{wishListId}/shares/{shareId} [delete]
+func (h *wishlistShareHandler) RevokeShare(c *gin.Context) {
+	currentCustomer := GetCustomerFromContext(c)
+	if currentCustomer == nil {
+		HandleError(c, e.NewUnauthorizedError())
+		return
+	}
+
+	if err := h.revokeShareUC.RevokeWishlistShare(c, currentCustomer.ID, c.Param("customerId"), c.Param("wishListId"), c.Param("shareId")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// ShowSharedWishlist godoc
+// @Summary Shows a shared wishlist without signing in
+// @Description Read-only view of the wishlist, the owner email is hidden. Signed in viewers also see the reservation status of each item and which reservations are theirs. The owner only sees reservations after enabling reveal_reservations in their preferences
+// @Tags wishlists
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} domain.FullfilledWishlist
+// @Failure 404 {object} outputs.ErrorResponse
+// @Failure 500 {object} outputs.ErrorResponse
+// @Router /api/shared/wishlists/{token} [get]
+func (h *wishlistShareHandler) ShowSharedWishlist(c *gin.Context) {
+	var currentCustomerID string
+	if currentCustomer := GetCustomerFromContext(c); currentCustomer != nil {
+		currentCustomerID = currentCustomer.ID
+	}
+
+	wishlist, err := h.showSharedWishlist.ShowSharedWishlist(c, currentCustomerID, c.Param("token"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, wishlist)
+}
+
+func toWishlistShareResponse(share *domain.WishlistShare) outputs.WishlistShareResponse {
+	return outputs.WishlistShareResponse{
+		ID:         share.ID,
+		WishlistID: share.WishlistID,
+		ExpiresAt:  share.ExpiresAt,
+		CreatedAt:  share.CreatedAt,
+	}
+}
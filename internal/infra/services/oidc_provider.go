@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type OIDCProviderConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCProvider implements the authorization code flow with PKCE against any provider
+// exposing the OpenID Connect discovery document
+type OIDCProvider struct {
+	config OIDCProviderConfig
+	client domain.HttpClient
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]any
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcTokenResponse struct {
+	IDToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+type oidcIDTokenClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+type oidcJWK struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func NewOIDCProvider(config OIDCProviderConfig, client domain.HttpClient) *OIDCProvider {
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &OIDCProvider{
+		config: config,
+		client: client,
+	}
+}
+
+func (p *OIDCProvider) Issuer() string {
+	return p.config.IssuerURL
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*domain.OIDCClaims, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"client_secret": {p.config.ClientSecret},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token oidcTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint error: %d %s", resp.StatusCode, token.Error)
+	}
+
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return p.verifyIDToken(ctx, token.IDToken, nonce)
+}
+
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, idToken string, nonce string) (*domain.OIDCClaims, error) {
+	parser := jwt.Parser{ValidMethods: []string{"RS256", "ES256"}}
+	token, err := parser.ParseWithClaims(idToken, &oidcIDTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*oidcIDTokenClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid id_token")
+	}
+
+	if claims.Issuer != p.config.IssuerURL {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.New("id_token was issued for another client")
+	}
+
+	if claims.ExpiresAt == nil {
+		return nil, errors.New("id_token has no expiration")
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("id_token has no subject")
+	}
+
+	return &domain.OIDCClaims{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, p.config.IssuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+
+	if discovery.Issuer != p.config.IssuerURL {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", discovery.Issuer, p.config.IssuerURL)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// getKey returns the provider key with the given kid, the key set is fetched again
+// when the kid is unknown since the provider may have rotated its keys
+func (p *OIDCProvider) getKey(ctx context.Context, kid string) (any, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	if err := p.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.publicKey()
+		if err != nil {
+			// keys we can't use are skipped, the provider may publish encryption keys too
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	p.keys = keys
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code error: %d %s", resp.StatusCode, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (k oidcJWK) publicKey() (any, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
@@ -0,0 +1,255 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/infra/services"
+)
+
+// fakeIssuer is a minimal OpenID Connect provider, codes are registered by the tests
+// as if the customer went through the provider login page
+type fakeIssuer struct {
+	server *httptest.Server
+	key    *ecdsa.PrivateKey
+	kid    string
+	codes  map[string]fakeGrant
+}
+
+type fakeGrant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	issuer := &fakeIssuer{key: key, kid: "fake-key", codes: map[string]fakeGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "EC",
+				"kid": issuer.kid,
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+				"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		grant, ok := issuer.codes[r.PostForm.Get("code")]
+		verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || grant.challenge != base64.RawURLEncoding.EncodeToString(verifier[:]) || r.PostForm.Get("client_id") != "wishlist" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		delete(issuer.codes, r.PostForm.Get("code"))
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "provider_access_token",
+			"id_token":     issuer.sign(t, grant.claims),
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (f *fakeIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = f.kid
+	signed, err := token.SignedString(f.key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func (f *fakeIssuer) claims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            f.server.URL,
+		"sub":            "provider_user_123",
+		"aud":            "wishlist",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "john@example.com",
+		"email_verified": true,
+		"name":           "John Doe",
+	}
+}
+
+func newTestOIDCProvider(issuer *fakeIssuer) *services.OIDCProvider {
+	return services.NewOIDCProvider(services.OIDCProviderConfig{
+		IssuerURL:    issuer.server.URL,
+		ClientID:     "wishlist",
+		ClientSecret: "client_secret",
+		RedirectURL:  "http://localhost:8080/api/auth/oidc/callback",
+	}, issuer.server.Client())
+}
+
+func TestOIDCProvider_AuthCodeURL(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := newTestOIDCProvider(issuer)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state_123", "nonce_123", "verifier_123")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, issuer.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+
+	query := parsed.Query()
+	challenge := sha256.Sum256([]byte("verifier_123"))
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "wishlist", query.Get("client_id"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "state_123", query.Get("state"))
+	assert.Equal(t, "nonce_123", query.Get("nonce"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), query.Get("code_challenge"))
+	assert.Empty(t, query.Get("code_verifier"))
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	tests := []struct {
+		name         string
+		codeVerifier string
+		nonce        string
+		editClaims   func(issuer *fakeIssuer, claims jwt.MapClaims)
+		wantErr      bool
+	}{
+		{
+			name:         "valid code",
+			codeVerifier: "verifier_123",
+			nonce:        "nonce_123",
+		},
+		{
+			name:         "wrong code verifier",
+			codeVerifier: "someone_else",
+			nonce:        "nonce_123",
+			wantErr:      true,
+		},
+		{
+			name:         "nonce mismatch",
+			codeVerifier: "verifier_123",
+			nonce:        "another_nonce",
+			wantErr:      true,
+		},
+		{
+			name:         "token issued for another client",
+			codeVerifier: "verifier_123",
+			nonce:        "nonce_123",
+			editClaims: func(issuer *fakeIssuer, claims jwt.MapClaims) {
+				claims["aud"] = "another_client"
+			},
+			wantErr: true,
+		},
+		{
+			name:         "token from another issuer",
+			codeVerifier: "verifier_123",
+			nonce:        "nonce_123",
+			editClaims: func(issuer *fakeIssuer, claims jwt.MapClaims) {
+				claims["iss"] = "https://evil.example.com"
+			},
+			wantErr: true,
+		},
+		{
+			name:         "expired token",
+			codeVerifier: "verifier_123",
+			nonce:        "nonce_123",
+			editClaims: func(issuer *fakeIssuer, claims jwt.MapClaims) {
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			},
+			wantErr: true,
+		},
+		{
+			name:         "token signed by an unknown key",
+			codeVerifier: "verifier_123",
+			nonce:        "nonce_123",
+			editClaims: func(issuer *fakeIssuer, claims jwt.MapClaims) {
+				other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				issuer.key = other
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			provider := newTestOIDCProvider(issuer)
+
+			claims := issuer.claims("nonce_123")
+			if tt.editClaims != nil {
+				tt.editClaims(issuer, claims)
+			}
+			challenge := sha256.Sum256([]byte("verifier_123"))
+			issuer.codes["code_123"] = fakeGrant{
+				challenge: base64.RawURLEncoding.EncodeToString(challenge[:]),
+				claims:    claims,
+			}
+
+			result, err := provider.Exchange(context.Background(), "code_123", tt.codeVerifier, tt.nonce)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, issuer.server.URL, result.Issuer)
+			assert.Equal(t, "provider_user_123", result.Subject)
+			assert.Equal(t, "john@example.com", result.Email)
+			assert.True(t, result.EmailVerified)
+			assert.Equal(t, "John Doe", result.Name)
+		})
+	}
+}
+
+func TestOIDCProvider_ExchangeCodeOnlyOnce(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := newTestOIDCProvider(issuer)
+
+	challenge := sha256.Sum256([]byte("verifier_123"))
+	issuer.codes["code_123"] = fakeGrant{
+		challenge: base64.RawURLEncoding.EncodeToString(challenge[:]),
+		claims:    issuer.claims("nonce_123"),
+	}
+
+	_, err := provider.Exchange(context.Background(), "code_123", "verifier_123", "nonce_123")
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), "code_123", "verifier_123", "nonce_123")
+	assert.Error(t, err)
+}
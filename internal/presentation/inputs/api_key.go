@@ -0,0 +1,7 @@
+package inputs
+
+type CreateAPIKeyInput struct {
+	Name string `json:"name" binding:"required"`
+	// Scopes accepts wishlists:read, wishlists:write and products
+	Scopes []string `json:"scopes" binding:"required"`
+}
@@ -3,4 +3,49 @@ package inputs
 type PwdAuth struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// IP and UserAgent are filled by the handler from the request, they are never read from the body
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// OIDCCallback holds the query parameters the provider redirects back with
+type OIDCCallback struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+	// IP and UserAgent are filled by the handler from the request
+	IP        string `form:"-"`
+	UserAgent string `form:"-"`
+}
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type MagicLinkRequestInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type MagicLinkAuth struct {
+	Token string `json:"token" binding:"required"`
+	// IP and UserAgent are filled by the handler from the request, they are never read from the body
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+type MFAVerifyInput struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type MFACodeInput struct {
+	Code string `json:"code" binding:"required"`
 }
@@ -1,7 +1,26 @@
 package inputs
 
+import "time"
+
 type CreateCustomerRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password"`
 }
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// CustomerSearchQuery is read from the query string, dates are RFC 3339
+type CustomerSearchQuery struct {
+	Query         string    `form:"q"`
+	CreatedAfter  time.Time `form:"created_after"`
+	CreatedBefore time.Time `form:"created_before"`
+	Status        string    `form:"status"`
+	Sort          string    `form:"sort"`
+	Order         string    `form:"order"`
+	Cursor        string    `form:"cursor"`
+	Limit         int       `form:"limit"`
+}
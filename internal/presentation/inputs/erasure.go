@@ -0,0 +1,18 @@
+package inputs
+
+import (
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+// ErasureReceiptInput is a receipt returned by the erase endpoint, sent back as is to be verified
+type ErasureReceiptInput struct {
+	ID                string                   `json:"id" binding:"required"`
+	CustomerID        string                   `json:"customer_id" binding:"required"`
+	RequestedBy       string                   `json:"requested_by" binding:"required"`
+	ErasedAt          time.Time                `json:"erased_at" binding:"required"`
+	WishlistRetention domain.WishlistRetention `json:"wishlist_retention" binding:"required"`
+	Wishlists         int                      `json:"wishlists"`
+	Signature         string                   `json:"signature" binding:"required"`
+}
@@ -0,0 +1,15 @@
+package inputs
+
+type NotificationSettingsRequest struct {
+	PriceDrops   bool `json:"price_drops"`
+	BackInStock  bool `json:"back_in_stock"`
+	WeeklyDigest bool `json:"weekly_digest"`
+}
+
+type PreferencesRequest struct {
+	Locale             string                      `json:"locale" binding:"required"`
+	Currency           string                      `json:"currency" binding:"required"`
+	WishlistVisibility string                      `json:"wishlist_visibility" binding:"required"`
+	Notifications      NotificationSettingsRequest `json:"notifications"`
+	RevealReservations bool                        `json:"reveal_reservations"`
+}
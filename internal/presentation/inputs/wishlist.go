@@ -8,3 +8,10 @@ type UpdateWishlistInput struct {
 	Title string   `json:"title"`
 	Items []string `json:"items"`
 }
+
+type AddWishlistItemInput struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity"`
+	Priority  int    `json:"priority"`
+	Note      string `json:"note"`
+}
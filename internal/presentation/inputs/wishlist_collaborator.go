@@ -0,0 +1,7 @@
+package inputs
+
+type InviteCollaboratorInput struct {
+	Email string `json:"email" binding:"required,email"`
+	// Role accepts viewer and editor
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,6 @@
+package inputs
+
+type ReserveWishlistItemInput struct {
+	// Anonymous hides who reserved the item from the owner and the other viewers
+	Anonymous bool `json:"anonymous"`
+}
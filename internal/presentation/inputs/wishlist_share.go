@@ -0,0 +1,8 @@
+package inputs
+
+import "time"
+
+type CreateWishlistShareInput struct {
+	// ExpiresAt is optional, shares without it work until they are revoked
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "time"
+
+type APIKeyResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is the only response holding the plain key
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
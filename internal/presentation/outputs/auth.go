@@ -1,5 +1,22 @@
 package outputs
 
+import "time"
+
+// AuthSuccessResponse holds either the tokens or, when MFARequired is set, the mfa token
+// to send to /api/auth/mfa/verify along with a second factor
 type AuthSuccessResponse struct {
-	Token string `json:"token"`
+	Token        string    `json:"token,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	MFARequired  bool      `json:"mfa_required,omitempty"`
+	MFAToken     string    `json:"mfa_token,omitempty"`
+}
+
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
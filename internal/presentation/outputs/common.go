@@ -2,4 +2,11 @@ package outputs
 
 type ErrorResponse struct {
 	Message string `json:"message"`
+	// Errors lists every failed rule when a request breaks more than one
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
 }
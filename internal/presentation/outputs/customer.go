@@ -1,5 +1,24 @@
 package outputs
 
+import "time"
+
 type CreateCustomerResponse struct {
 	ID string `json:"id"`
 }
+
+type AdminCustomerResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
+
+type CustomerPageResponse struct {
+	Customers []AdminCustomerResponse `json:"customers"`
+	// NextCursor is sent back as the cursor parameter to get the next page, it is empty on the last one
+	NextCursor string `json:"next_cursor,omitempty"`
+}
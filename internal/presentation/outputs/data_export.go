@@ -0,0 +1,15 @@
+package outputs
+
+import "time"
+
+type DataExportResponse struct {
+	JobID       string     `json:"job_id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	StatusURL   string     `json:"status_url"`
+	// DownloadURL is only set once the export is ready
+	DownloadURL string `json:"download_url,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
@@ -0,0 +1,5 @@
+package outputs
+
+type ErasureVerificationResponse struct {
+	Valid bool `json:"valid"`
+}
@@ -0,0 +1,13 @@
+package outputs
+
+import "time"
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// Current flags the session of the token used for the request
+	Current bool `json:"current"`
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "time"
+
+type WishlistShareResponse struct {
+	ID         string     `json:"id"`
+	WishlistID string     `json:"wishlist_id"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedWishlistShareResponse is the only response holding the plain token
+type CreatedWishlistShareResponse struct {
+	WishlistShareResponse
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type AccessPolicyUseCase struct {
+	customerGetter domain.GetCustomerByIDRepository
+	idGen          domain.IDGenerator
+	auditStorer    domain.AuditEventCreationRepository
+}
+
+func NewAccessPolicyUseCase(
+	customerGetter domain.GetCustomerByIDRepository,
+	idGen domain.IDGenerator,
+	auditStorer domain.AuditEventCreationRepository,
+) *AccessPolicyUseCase {
+	return &AccessPolicyUseCase{
+		customerGetter: customerGetter,
+		idGen:          idGen,
+		auditStorer:    auditStorer,
+	}
+}
+
+// Authorize lets owners act on their own resources, anyone else needs a role granting the permission.
+// The role is read from the database instead of the token so a demotion applies right away,
+// and every access granted through a role is audited before it happens
+func (u *AccessPolicyUseCase) Authorize(ctx context.Context, actorID string, permission domain.Permission, ownerID string) error {
+	if actorID == "" {
+		return e.NewUnauthorizedError()
+	}
+
+	if actorID == ownerID {
+		return nil
+	}
+
+	actor, err := u.customerGetter.GetByID(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	if actor == nil || !actor.Role.Can(permission) {
+		return e.NewUnauthorizedError()
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return errors.Join(err, errors.New("failed to generate audit event ID"))
+	}
+
+	return u.auditStorer.Create(ctx, &domain.AuditEvent{
+		ID:         id,
+		ActorID:    actor.ID,
+		ActorRole:  actor.Role,
+		Permission: permission,
+		OwnerID:    ownerID,
+		CreatedAt:  time.Now(),
+	})
+}
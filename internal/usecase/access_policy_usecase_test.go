@@ -0,0 +1,146 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccessPolicyUseCase_Authorize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockAuditStorer := mocks.NewMockAuditEventCreationRepository(ctrl)
+
+	support := &domain.Customer{ID: "support_123", Role: domain.RoleSupport}
+	admin := &domain.Customer{ID: "admin_123", Role: domain.RoleAdmin}
+
+	tests := []struct {
+		name          string
+		actorID       string
+		permission    domain.Permission
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:       "owner",
+			actorID:    "customer_123",
+			permission: domain.PermissionCustomerPassword,
+			setupMocks: func() {},
+		},
+		{
+			name:          "anonymous actor",
+			actorID:       "",
+			permission:    domain.PermissionCustomerRead,
+			setupMocks:    func() {},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "another customer",
+			actorID:    "other_123",
+			permission: domain.PermissionWishlistRead,
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "other_123").Return(&domain.Customer{ID: "other_123", Role: domain.RoleCustomer}, nil)
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "unknown actor",
+			actorID:    "deleted_123",
+			permission: domain.PermissionWishlistRead,
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "deleted_123").Return(nil, nil)
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "support reads a wishlist and is audited",
+			actorID:    "support_123",
+			permission: domain.PermissionWishlistRead,
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "support_123").Return(support, nil)
+				mockIDGen.EXPECT().Generate().Return("event_123", nil)
+				mockAuditStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *domain.AuditEvent) error {
+						assert.Equal(t, "event_123", event.ID)
+						assert.Equal(t, "support_123", event.ActorID)
+						assert.Equal(t, domain.RoleSupport, event.ActorRole)
+						assert.Equal(t, domain.PermissionWishlistRead, event.Permission)
+						assert.Equal(t, "customer_123", event.OwnerID)
+						assert.False(t, event.CreatedAt.IsZero())
+						return nil
+					})
+			},
+		},
+		{
+			name:       "support can't write",
+			actorID:    "support_123",
+			permission: domain.PermissionWishlistWrite,
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "support_123").Return(support, nil)
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "admin can't change passwords",
+			actorID:    "admin_123",
+			permission: domain.PermissionCustomerPassword,
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "admin_123").Return(admin, nil)
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "audit failure denies the access",
+			actorID:    "admin_123",
+			permission: domain.PermissionCustomerRead,
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "admin_123").Return(admin, nil)
+				mockIDGen.EXPECT().Generate().Return("event_123", nil)
+				mockAuditStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewAccessPolicyUseCase(mockCustomerGetter, mockIDGen, mockAuditStorer)
+			err := uc.Authorize(context.Background(), tt.actorID, tt.permission, "customer_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// ownerOnlyPolicy behaves like the access policy for actors without a privileged role
+func ownerOnlyPolicy(ctrl *gomock.Controller) *mocks.MockPolicy {
+	policy := mocks.NewMockPolicy(ctrl)
+	policy.EXPECT().
+		Authorize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, actorID string, permission domain.Permission, ownerID string) error {
+			if actorID != ownerID {
+				return e.NewUnauthorizedError()
+			}
+			return nil
+		}).
+		AnyTimes()
+
+	return policy
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+const (
+	// apiKeyPrefix makes leaked keys easy to spot by secret scanners
+	apiKeyPrefix = "wl_"
+	// apiKeyVisibleChars is how much of the key is kept in clear to tell keys apart
+	apiKeyVisibleChars = 10
+	apiKeyMaxNameLen   = 100
+)
+
+type APIKeyUseCase struct {
+	customerGetter domain.GetCustomerByIDRepository
+	idGen          domain.IDGenerator
+	keyGen         domain.TokenGenerator
+	keyHasher      domain.Hasher
+	keyStorer      domain.APIKeyCreationRepository
+	keyLister      domain.ListAPIKeysRepository
+	keyGetter      domain.APIKeyByHashRepository
+	keyRevoker     domain.RevokeAPIKeyRepository
+	accessPolicy   domain.Policy
+}
+
+func NewAPIKeyUseCase(
+	customerGetter domain.GetCustomerByIDRepository,
+	idGen domain.IDGenerator,
+	keyGen domain.TokenGenerator,
+	keyHasher domain.Hasher,
+	keyStorer domain.APIKeyCreationRepository,
+	keyLister domain.ListAPIKeysRepository,
+	keyGetter domain.APIKeyByHashRepository,
+	keyRevoker domain.RevokeAPIKeyRepository,
+	accessPolicy domain.Policy,
+) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		customerGetter: customerGetter,
+		idGen:          idGen,
+		keyGen:         keyGen,
+		keyHasher:      keyHasher,
+		keyStorer:      keyStorer,
+		keyLister:      keyLister,
+		keyGetter:      keyGetter,
+		keyRevoker:     keyRevoker,
+		accessPolicy:   accessPolicy,
+	}
+}
+
+func (u *APIKeyUseCase) CreateAPIKey(ctx context.Context, currentCustomerID string, customerID string, data domain.IncommingAPIKey) (*domain.CreatedAPIKey, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerAPIKeys, customerID); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return nil, e.NewRequiredFieldError("name")
+	}
+
+	if len(name) > apiKeyMaxNameLen {
+		return nil, &e.ValidationError{Field: "name", Err: "is too long"}
+	}
+
+	scopes, err := uniqueScopes(data.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := u.keyGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate API key"))
+	}
+	plainKey := apiKeyPrefix + secret
+
+	keyHash, err := u.keyHasher.Hash(plainKey)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to hash API key"))
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate API key ID"))
+	}
+
+	key := domain.APIKey{
+		ID:         id,
+		CustomerID: customerID,
+		Name:       name,
+		Prefix:     plainKey[:apiKeyVisibleChars],
+		KeyHash:    keyHash,
+		Scopes:     scopes,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := u.keyStorer.Create(ctx, &key); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAPIKey{APIKey: key, Key: plainKey}, nil
+}
+
+func (u *APIKeyUseCase) ListAPIKeys(ctx context.Context, currentCustomerID string, customerID string) ([]*domain.APIKey, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerAPIKeys, customerID); err != nil {
+		return nil, err
+	}
+
+	return u.keyLister.ListByCustomerID(ctx, customerID)
+}
+
+func (u *APIKeyUseCase) RevokeAPIKey(ctx context.Context, currentCustomerID string, customerID string, keyID string) error {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerAPIKeys, customerID); err != nil {
+		return err
+	}
+
+	revoked, err := u.keyRevoker.Revoke(ctx, customerID, keyID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return e.NewNotFoundError("api key")
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey resolves the key sent by an integration, keys of deleted customers stop working with them
+func (u *APIKeyUseCase) AuthenticateAPIKey(ctx context.Context, plainKey string) (*domain.APIKey, *domain.Customer, error) {
+	if !strings.HasPrefix(plainKey, apiKeyPrefix) {
+		return nil, nil, e.NewAuthenticationError(domain.AuthMethodAPIKey)
+	}
+
+	keyHash, err := u.keyHasher.Hash(plainKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := u.keyGetter.GetActiveByHash(ctx, keyHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if key == nil {
+		return nil, nil, e.NewAuthenticationError(domain.AuthMethodAPIKey)
+	}
+
+	customer, err := u.customerGetter.GetByID(ctx, key.CustomerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if customer == nil {
+		return nil, nil, e.NewAuthenticationError(domain.AuthMethodAPIKey)
+	}
+
+	return key, customer, nil
+}
+
+func uniqueScopes(requested []domain.APIKeyScope) ([]domain.APIKeyScope, error) {
+	if len(requested) == 0 {
+		return nil, e.NewRequiredFieldError("scopes")
+	}
+
+	scopes := make([]domain.APIKeyScope, 0, len(requested))
+	seen := make(map[domain.APIKeyScope]bool, len(requested))
+	for _, scope := range requested {
+		if !scope.Valid() {
+			return nil, &e.ValidationError{Field: "scopes", Err: "contains an unknown scope " + string(scope)}
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
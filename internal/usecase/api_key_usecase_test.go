@@ -0,0 +1,280 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyUseCase_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockKeyGen := mocks.NewMockTokenGenerator(ctrl)
+	mockKeyHasher := mocks.NewMockHasher(ctrl)
+	mockKeyStorer := mocks.NewMockAPIKeyCreationRepository(ctrl)
+	mockKeyLister := mocks.NewMockListAPIKeysRepository(ctrl)
+	mockKeyGetter := mocks.NewMockAPIKeyByHashRepository(ctrl)
+	mockKeyRevoker := mocks.NewMockRevokeAPIKeyRepository(ctrl)
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		data              domain.IncommingAPIKey
+		setupMocks        func()
+		expectedError     error
+	}{
+		{
+			name:              "creates a key and only stores its hash",
+			currentCustomerID: "customer_123",
+			data: domain.IncommingAPIKey{
+				Name:   " backend ",
+				Scopes: []domain.APIKeyScope{domain.APIKeyScopeWishlistsRead, domain.APIKeyScopeProducts, domain.APIKeyScopeWishlistsRead},
+			},
+			setupMocks: func() {
+				mockKeyGen.EXPECT().Generate().Return("secret_value", nil)
+				mockKeyHasher.EXPECT().Hash("wl_secret_value").Return("key_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("key_123", nil)
+				mockKeyStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, key *domain.APIKey) error {
+						assert.Equal(t, "key_123", key.ID)
+						assert.Equal(t, "customer_123", key.CustomerID)
+						assert.Equal(t, "backend", key.Name)
+						assert.Equal(t, "wl_secret_", key.Prefix)
+						assert.Equal(t, "key_hash", key.KeyHash)
+						assert.Equal(t, []domain.APIKeyScope{domain.APIKeyScopeWishlistsRead, domain.APIKeyScopeProducts}, key.Scopes)
+						return nil
+					})
+			},
+		},
+		{
+			name:              "another customer",
+			currentCustomerID: "other_123",
+			data:              domain.IncommingAPIKey{Name: "backend", Scopes: []domain.APIKeyScope{domain.APIKeyScopeProducts}},
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+		{
+			name:              "missing name",
+			currentCustomerID: "customer_123",
+			data:              domain.IncommingAPIKey{Name: " ", Scopes: []domain.APIKeyScope{domain.APIKeyScopeProducts}},
+			setupMocks:        func() {},
+			expectedError:     e.NewRequiredFieldError("name"),
+		},
+		{
+			name:              "missing scopes",
+			currentCustomerID: "customer_123",
+			data:              domain.IncommingAPIKey{Name: "backend"},
+			setupMocks:        func() {},
+			expectedError:     e.NewRequiredFieldError("scopes"),
+		},
+		{
+			name:              "unknown scope",
+			currentCustomerID: "customer_123",
+			data:              domain.IncommingAPIKey{Name: "backend", Scopes: []domain.APIKeyScope{"customers:write"}},
+			setupMocks:        func() {},
+			expectedError:     &e.ValidationError{Field: "scopes", Err: "contains an unknown scope customers:write"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewAPIKeyUseCase(
+				mockCustomerGetter,
+				mockIDGen,
+				mockKeyGen,
+				mockKeyHasher,
+				mockKeyStorer,
+				mockKeyLister,
+				mockKeyGetter,
+				mockKeyRevoker,
+				ownerOnlyPolicy(ctrl),
+			)
+
+			key, err := uc.CreateAPIKey(context.Background(), tt.currentCustomerID, "customer_123", tt.data)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, key)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "wl_secret_value", key.Key)
+			assert.Equal(t, "key_123", key.ID)
+		})
+	}
+}
+
+func TestAPIKeyUseCase_RevokeAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockKeyGen := mocks.NewMockTokenGenerator(ctrl)
+	mockKeyHasher := mocks.NewMockHasher(ctrl)
+	mockKeyStorer := mocks.NewMockAPIKeyCreationRepository(ctrl)
+	mockKeyLister := mocks.NewMockListAPIKeysRepository(ctrl)
+	mockKeyGetter := mocks.NewMockAPIKeyByHashRepository(ctrl)
+	mockKeyRevoker := mocks.NewMockRevokeAPIKeyRepository(ctrl)
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "revoked",
+			setupMocks: func() {
+				mockKeyRevoker.EXPECT().Revoke(gomock.Any(), "customer_123", "key_123", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name: "unknown or already revoked key",
+			setupMocks: func() {
+				mockKeyRevoker.EXPECT().Revoke(gomock.Any(), "customer_123", "key_123", gomock.Any()).Return(false, nil)
+			},
+			expectedError: e.NewNotFoundError("api key"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewAPIKeyUseCase(
+				mockCustomerGetter,
+				mockIDGen,
+				mockKeyGen,
+				mockKeyHasher,
+				mockKeyStorer,
+				mockKeyLister,
+				mockKeyGetter,
+				mockKeyRevoker,
+				ownerOnlyPolicy(ctrl),
+			)
+
+			err := uc.RevokeAPIKey(context.Background(), "customer_123", "customer_123", "key_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyUseCase_AuthenticateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockKeyGen := mocks.NewMockTokenGenerator(ctrl)
+	mockKeyHasher := mocks.NewMockHasher(ctrl)
+	mockKeyStorer := mocks.NewMockAPIKeyCreationRepository(ctrl)
+	mockKeyLister := mocks.NewMockListAPIKeysRepository(ctrl)
+	mockKeyGetter := mocks.NewMockAPIKeyByHashRepository(ctrl)
+	mockKeyRevoker := mocks.NewMockRevokeAPIKeyRepository(ctrl)
+
+	customer := &domain.Customer{ID: "customer_123", Email: "john@example.com"}
+	key := &domain.APIKey{ID: "key_123", CustomerID: "customer_123", Scopes: []domain.APIKeyScope{domain.APIKeyScopeWishlistsWrite}}
+
+	tests := []struct {
+		name          string
+		plainKey      string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:     "valid key",
+			plainKey: "wl_secret_value",
+			setupMocks: func() {
+				mockKeyHasher.EXPECT().Hash("wl_secret_value").Return("key_hash", nil)
+				mockKeyGetter.EXPECT().GetActiveByHash(gomock.Any(), "key_hash").Return(key, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+			},
+		},
+		{
+			name:          "not an api key",
+			plainKey:      "eyJhbGciOiJIUzI1NiJ9",
+			setupMocks:    func() {},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodAPIKey),
+		},
+		{
+			name:     "unknown or revoked key",
+			plainKey: "wl_secret_value",
+			setupMocks: func() {
+				mockKeyHasher.EXPECT().Hash("wl_secret_value").Return("key_hash", nil)
+				mockKeyGetter.EXPECT().GetActiveByHash(gomock.Any(), "key_hash").Return(nil, nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodAPIKey),
+		},
+		{
+			name:     "deleted customer",
+			plainKey: "wl_secret_value",
+			setupMocks: func() {
+				mockKeyHasher.EXPECT().Hash("wl_secret_value").Return("key_hash", nil)
+				mockKeyGetter.EXPECT().GetActiveByHash(gomock.Any(), "key_hash").Return(key, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodAPIKey),
+		},
+		{
+			name:     "database error",
+			plainKey: "wl_secret_value",
+			setupMocks: func() {
+				mockKeyHasher.EXPECT().Hash("wl_secret_value").Return("key_hash", nil)
+				mockKeyGetter.EXPECT().GetActiveByHash(gomock.Any(), "key_hash").Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewAPIKeyUseCase(
+				mockCustomerGetter,
+				mockIDGen,
+				mockKeyGen,
+				mockKeyHasher,
+				mockKeyStorer,
+				mockKeyLister,
+				mockKeyGetter,
+				mockKeyRevoker,
+				ownerOnlyPolicy(ctrl),
+			)
+
+			gotKey, gotCustomer, err := uc.AuthenticateAPIKey(context.Background(), tt.plainKey)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, gotKey)
+				assert.Nil(t, gotCustomer)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, key, gotKey)
+			assert.Equal(t, customer, gotCustomer)
+			assert.True(t, gotKey.HasScope(domain.APIKeyScopeWishlistsRead))
+			assert.False(t, gotKey.HasScope(domain.APIKeyScopeProducts))
+		})
+	}
+}
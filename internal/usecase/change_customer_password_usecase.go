@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type ChangeCustomerPasswordUseCase struct {
+	Getter          domain.GetCustomerByIDRepository
+	PasswordUpdater domain.UpdateCustomerPasswordRepository
+	HashComparer    domain.HashComparer
+	Hasher          domain.Hasher
+	Policy          domain.PasswordPolicy
+	Revoker         domain.TokenRevoker
+	AccessPolicy    domain.Policy
+}
+
+func NewChangeCustomerPasswordUseCase(
+	getter domain.GetCustomerByIDRepository,
+	passwordUpdater domain.UpdateCustomerPasswordRepository,
+	hashComparer domain.HashComparer,
+	hasher domain.Hasher,
+	policy domain.PasswordPolicy,
+	revoker domain.TokenRevoker,
+	accessPolicy domain.Policy,
+) *ChangeCustomerPasswordUseCase {
+	return &ChangeCustomerPasswordUseCase{
+		Getter:          getter,
+		PasswordUpdater: passwordUpdater,
+		HashComparer:    hashComparer,
+		Hasher:          hasher,
+		Policy:          policy,
+		Revoker:         revoker,
+		AccessPolicy:    accessPolicy,
+	}
+}
+
+func (u *ChangeCustomerPasswordUseCase) ChangePassword(ctx context.Context, currentCustomerID string, customerID string, data domain.PasswordChange) error {
+	if err := u.AccessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerPassword, customerID); err != nil {
+		return err
+	}
+
+	if data.CurrentPassword == "" {
+		return e.NewRequiredFieldError("current_password")
+	}
+
+	if data.NewPassword == "" {
+		return e.NewRequiredFieldError("new_password")
+	}
+
+	customer, err := u.Getter.GetByID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		return e.NewNotFoundError("customer")
+	}
+
+	if err := u.HashComparer.Compare(customer.Password, data.CurrentPassword); err != nil {
+		return &e.ValidationError{Field: "current_password", Err: "does not match"}
+	}
+
+	if data.NewPassword == data.CurrentPassword {
+		return &e.ValidationError{Field: "new_password", Err: "must be different from the current password"}
+	}
+
+	if err := u.Policy.Validate(data.NewPassword, customer); err != nil {
+		return err
+	}
+
+	hash, err := u.Hasher.Hash(data.NewPassword)
+	if err != nil {
+		return errors.Join(err, errors.New("failed to hash password"))
+	}
+
+	err = u.PasswordUpdater.UpdatePassword(ctx, customerID, hash, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// sessions opened with the old password must not survive the change
+	return u.Revoker.RevokeAllForCustomer(ctx, customerID)
+}
@@ -0,0 +1,163 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangeCustomerPasswordUseCase_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockPasswordUpdater := mocks.NewMockUpdateCustomerPasswordRepository(ctrl)
+	mockComparer := mocks.NewMockHashComparer(ctrl)
+	mockHasher := mocks.NewMockHasher(ctrl)
+	mockPolicy := mocks.NewMockPasswordPolicy(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+
+	customer := &domain.Customer{
+		ID:       "customer_123",
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "old_hash",
+	}
+	validChange := domain.PasswordChange{
+		CurrentPassword: "old-password",
+		NewPassword:     "new-password",
+	}
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		customerID        string
+		data              domain.PasswordChange
+		setupMocks        func()
+		expectedError     error
+	}{
+		{
+			name:              "successful password change",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              validChange,
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockComparer.EXPECT().Compare("old_hash", "old-password").Return(nil)
+				mockPolicy.EXPECT().Validate("new-password", customer).Return(nil)
+				mockHasher.EXPECT().Hash("new-password").Return("new_hash", nil)
+				mockPasswordUpdater.EXPECT().UpdatePassword(gomock.Any(), "customer_123", "new_hash", gomock.Any()).Return(nil)
+				mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil)
+			},
+		},
+		{
+			name:              "unauthorized",
+			currentCustomerID: "other_customer",
+			customerID:        "customer_123",
+			data:              validChange,
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+		{
+			name:              "missing current password",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              domain.PasswordChange{NewPassword: "new-password"},
+			setupMocks:        func() {},
+			expectedError:     e.NewRequiredFieldError("current_password"),
+		},
+		{
+			name:              "missing new password",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              domain.PasswordChange{CurrentPassword: "old-password"},
+			setupMocks:        func() {},
+			expectedError:     e.NewRequiredFieldError("new_password"),
+		},
+		{
+			name:              "customer not found",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              validChange,
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("customer"),
+		},
+		{
+			name:              "wrong current password",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              validChange,
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockComparer.EXPECT().Compare("old_hash", "old-password").Return(errors.New("mismatch"))
+			},
+			expectedError: &e.ValidationError{Field: "current_password", Err: "does not match"},
+		},
+		{
+			name:              "same password",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              domain.PasswordChange{CurrentPassword: "old-password", NewPassword: "old-password"},
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockComparer.EXPECT().Compare("old_hash", "old-password").Return(nil)
+			},
+			expectedError: &e.ValidationError{Field: "new_password", Err: "must be different from the current password"},
+		},
+		{
+			name:              "policy violation",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              validChange,
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockComparer.EXPECT().Compare("old_hash", "old-password").Return(nil)
+				mockPolicy.EXPECT().
+					Validate("new-password", customer).
+					Return(&e.ValidationError{Field: "password", Err: "must have at least 20 characters"})
+			},
+			expectedError: &e.ValidationError{Field: "password", Err: "must have at least 20 characters"},
+		},
+		{
+			name:              "repository error",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			data:              validChange,
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockComparer.EXPECT().Compare("old_hash", "old-password").Return(nil)
+				mockPolicy.EXPECT().Validate("new-password", customer).Return(nil)
+				mockHasher.EXPECT().Hash("new-password").Return("new_hash", nil)
+				mockPasswordUpdater.EXPECT().
+					UpdatePassword(gomock.Any(), "customer_123", "new_hash", gomock.Any()).
+					Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewChangeCustomerPasswordUseCase(mockGetter, mockPasswordUpdater, mockComparer, mockHasher, mockPolicy, mockRevoker, ownerOnlyPolicy(ctrl))
+			err := uc.ChangePassword(context.Background(), tt.currentCustomerID, tt.customerID, tt.data)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
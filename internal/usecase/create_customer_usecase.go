@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ydoro/wishlist/internal/domain"
@@ -14,20 +15,30 @@ type createCustomerUseCase struct {
 	idGen          domain.IDGenerator
 	pwdHasher      domain.Hasher
 	customerGetter domain.GetCustomerByEmailRepository
+	verification   domain.EmailVerificationSender
+	passwordPolicy domain.PasswordPolicy
+	restorer       domain.AccountRestorer
 }
 
-func NewCreateCustomerUseCase(customerRepository domain.CustomerCreationRepository, idGen domain.IDGenerator, hasher domain.Hasher, customerGetter domain.GetCustomerByEmailRepository) *createCustomerUseCase {
+func NewCreateCustomerUseCase(customerRepository domain.CustomerCreationRepository, idGen domain.IDGenerator, hasher domain.Hasher, customerGetter domain.GetCustomerByEmailRepository, verification domain.EmailVerificationSender, passwordPolicy domain.PasswordPolicy, restorer domain.AccountRestorer) *createCustomerUseCase {
 	return &createCustomerUseCase{
 		repo:           customerRepository,
 		idGen:          idGen,
 		pwdHasher:      hasher,
 		customerGetter: customerGetter,
+		verification:   verification,
+		passwordPolicy: passwordPolicy,
+		restorer:       restorer,
 	}
 }
 
 func (uc *createCustomerUseCase) CreateCustomerWithEmail(ctx context.Context, data domain.IncommingCustomer) (string, error) {
-	if data.Password == "" {
-		return "", e.NewRequiredFieldError("password")
+	// customers without a password log in with a magic link
+	if data.Password != "" {
+		// the customer does not exist yet, name and email are enough to reject passwords built from them
+		if err := uc.passwordPolicy.Validate(data.Password, &domain.Customer{Name: data.Name, Email: data.Email}); err != nil {
+			return "", err
+		}
 	}
 
 	c, err := uc.customerGetter.GetByEmail(ctx, data.Email)
@@ -36,10 +47,22 @@ func (uc *createCustomerUseCase) CreateCustomerWithEmail(ctx context.Context, da
 		return "", &e.ValidationError{Field: "email", Err: "email already in use"}
 	}
 
-	// here we can add some validation for the password strength and etc
-	pwd, err := uc.pwdHasher.Hash(data.Password)
+	// the email stays taken until the deleted account is purged
+	deleted, err := uc.restorer.GetRestorable(ctx, data.Email)
 	if err != nil {
-		return "", errors.Join(err, errors.New("failed to hash password"))
+		return "", err
+	}
+
+	if deleted != nil {
+		return "", &e.ValidationError{Field: "email", Err: "belongs to a deleted account, log in to restore it"}
+	}
+
+	var pwd string
+	if data.Password != "" {
+		pwd, err = uc.pwdHasher.Hash(data.Password)
+		if err != nil {
+			return "", errors.Join(err, errors.New("failed to hash password"))
+		}
 	}
 
 	id, err := uc.idGen.Generate()
@@ -47,12 +70,23 @@ func (uc *createCustomerUseCase) CreateCustomerWithEmail(ctx context.Context, da
 		return "", errors.Join(err, errors.New("failed to generate customer ID"))
 	}
 
-	return id, uc.repo.Create(ctx, &domain.Customer{
+	customer := &domain.Customer{
 		Name:      data.Name,
 		Email:     data.Email,
 		Password:  pwd,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		ID:        id,
-	})
+		Role:      domain.RoleCustomer,
+	}
+	if err := uc.repo.Create(ctx, customer); err != nil {
+		return id, err
+	}
+
+	if err := uc.verification.SendVerification(ctx, customer); err != nil {
+		// the account already exists at this point, the customer can ask for a new link later
+		fmt.Printf("[create_customer_usecase] ERROR sending verification email: %v\n", err)
+	}
+
+	return id, nil
 }
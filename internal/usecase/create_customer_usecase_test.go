@@ -22,6 +22,9 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 	mockIDGen := mocks.NewMockIDGenerator(ctrl)
 	mockHasher := mocks.NewMockHasher(ctrl)
 	mockCustomerGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockVerification := mocks.NewMockEmailVerificationSender(ctrl)
+	mockPolicy := mocks.NewMockPasswordPolicy(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
 
 	tests := []struct {
 		name          string
@@ -38,11 +41,15 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 				Password: "password123",
 			},
 			setupMocks: func() {
+				mockPolicy.EXPECT().Validate("password123", gomock.Any()).Return(nil)
+
 				mockCustomerGetter.EXPECT().
 					GetByEmail(gomock.Any(), "john@example.com").
 					Return(nil, nil).
 					Times(1)
 
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(nil, nil)
+
 				mockHasher.EXPECT().
 					Hash("password123").
 					Return("hashed_password", nil)
@@ -63,20 +70,104 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 				mockRepo.EXPECT().
 					Create(gomock.Any(), matchesCustomer(expectedCustomer)).
 					Return(nil)
+
+				mockVerification.EXPECT().
+					SendVerification(gomock.Any(), matchesCustomer(expectedCustomer)).
+					Return(nil)
 			},
 			expectedID:    "generated_id",
 			expectedError: nil,
 		},
 		{
-			name: "empty password",
+			name: "verification email failure does not fail the signup",
+			input: domain.IncommingCustomer{
+				Name:     "John Doe",
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			setupMocks: func() {
+				mockPolicy.EXPECT().Validate("password123", gomock.Any()).Return(nil)
+
+				mockCustomerGetter.EXPECT().
+					GetByEmail(gomock.Any(), "john@example.com").
+					Return(nil, nil)
+
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(nil, nil)
+
+				mockHasher.EXPECT().
+					Hash("password123").
+					Return("hashed_password", nil)
+
+				mockIDGen.EXPECT().
+					Generate().
+					Return("generated_id", nil)
+
+				mockRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(nil)
+
+				mockVerification.EXPECT().
+					SendVerification(gomock.Any(), gomock.Any()).
+					Return(errors.New("smtp error"))
+			},
+			expectedID:    "generated_id",
+			expectedError: nil,
+		},
+		{
+			name: "customer without password",
 			input: domain.IncommingCustomer{
 				Name:     "John Doe",
 				Email:    "john@example.com",
 				Password: "",
 			},
-			setupMocks:    func() {},
-			expectedID:    "",
-			expectedError: e.NewRequiredFieldError("password"),
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().
+					GetByEmail(gomock.Any(), "john@example.com").
+					Return(nil, nil)
+
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(nil, nil)
+
+				mockIDGen.EXPECT().
+					Generate().
+					Return("generated_id", nil)
+
+				expectedCustomer := &domain.Customer{
+					ID:    "generated_id",
+					Name:  "John Doe",
+					Email: "john@example.com",
+				}
+
+				mockRepo.EXPECT().
+					Create(gomock.Any(), matchesCustomer(expectedCustomer)).
+					Return(nil)
+
+				mockVerification.EXPECT().
+					SendVerification(gomock.Any(), matchesCustomer(expectedCustomer)).
+					Return(nil)
+			},
+			expectedID:    "generated_id",
+			expectedError: nil,
+		},
+		{
+			name: "password rejected by the policy",
+			input: domain.IncommingCustomer{
+				Name:     "John Doe",
+				Email:    "john@example.com",
+				Password: "john",
+			},
+			setupMocks: func() {
+				mockPolicy.EXPECT().
+					Validate("john", &domain.Customer{Name: "John Doe", Email: "john@example.com"}).
+					Return(e.ValidationErrors{
+						{Field: "password", Err: "must have at least 8 characters"},
+						{Field: "password", Err: "must not contain your name or email"},
+					})
+			},
+			expectedID: "",
+			expectedError: e.ValidationErrors{
+				{Field: "password", Err: "must have at least 8 characters"},
+				{Field: "password", Err: "must not contain your name or email"},
+			},
 		},
 		{
 			name: "password hashing fails",
@@ -86,11 +177,15 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 				Password: "password123",
 			},
 			setupMocks: func() {
+				mockPolicy.EXPECT().Validate("password123", gomock.Any()).Return(nil)
+
 				mockCustomerGetter.EXPECT().
 					GetByEmail(gomock.Any(), "john@example.com").
 					Return(nil, nil).
 					Times(1)
 
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(nil, nil)
+
 				mockHasher.EXPECT().
 					Hash("password123").
 					Return("", errors.New("hashing error"))
@@ -106,11 +201,15 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 				Password: "password123",
 			},
 			setupMocks: func() {
+				mockPolicy.EXPECT().Validate("password123", gomock.Any()).Return(nil)
+
 				mockCustomerGetter.EXPECT().
 					GetByEmail(gomock.Any(), "john@example.com").
 					Return(nil, nil).
 					Times(1)
 
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(nil, nil)
+
 				mockHasher.EXPECT().
 					Hash("password123").
 					Return("hashed_password", nil)
@@ -130,11 +229,15 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 				Password: "password123",
 			},
 			setupMocks: func() {
+				mockPolicy.EXPECT().Validate("password123", gomock.Any()).Return(nil)
+
 				mockCustomerGetter.EXPECT().
 					GetByEmail(gomock.Any(), "john@example.com").
 					Return(nil, nil).
 					Times(1)
 
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(nil, nil)
+
 				mockHasher.EXPECT().
 					Hash("password123").
 					Return("hashed_password", nil)
@@ -150,6 +253,27 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 			expectedID:    "generated_id",
 			expectedError: errors.New("repository error"),
 		},
+		{
+			name: "email of an account deleted during the grace period",
+			input: domain.IncommingCustomer{
+				Name:     "John Doe",
+				Email:    "deleted@example.com",
+				Password: "password123",
+			},
+			setupMocks: func() {
+				mockPolicy.EXPECT().Validate("password123", gomock.Any()).Return(nil)
+
+				mockCustomerGetter.EXPECT().
+					GetByEmail(gomock.Any(), "deleted@example.com").
+					Return(nil, nil)
+
+				mockRestorer.EXPECT().
+					GetRestorable(gomock.Any(), "deleted@example.com").
+					Return(&domain.Customer{ID: "deleted_id", Email: "deleted@example.com", DeletedAt: time.Now()}, nil)
+			},
+			expectedID:    "",
+			expectedError: &e.ValidationError{Field: "email", Err: "belongs to a deleted account, log in to restore it"},
+		},
 		{
 			name: "email already in use",
 			input: domain.IncommingCustomer{
@@ -158,6 +282,8 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 				Password: "password123",
 			},
 			setupMocks: func() {
+				mockPolicy.EXPECT().Validate("password123", gomock.Any()).Return(nil)
+
 				mockCustomerGetter.EXPECT().
 					GetByEmail(gomock.Any(), "existing@example.com").
 					Return(&domain.Customer{
@@ -175,7 +301,7 @@ func TestCreateCustomerUseCase_CreateCustomerWithEmail(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
 
-			uc := usecase.NewCreateCustomerUseCase(mockRepo, mockIDGen, mockHasher, mockCustomerGetter)
+			uc := usecase.NewCreateCustomerUseCase(mockRepo, mockIDGen, mockHasher, mockCustomerGetter, mockVerification, mockPolicy, mockRestorer)
 			id, err := uc.CreateCustomerWithEmail(context.Background(), tt.input)
 
 			if tt.expectedError != nil {
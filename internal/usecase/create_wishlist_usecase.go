@@ -12,6 +12,7 @@ type CreateWishlistUseCase struct {
 	Getter         domain.WishlistByTitleRepository
 	Creator        domain.WishlistCreationRepository
 	IdMaker        domain.IDGenerator
+	AccessPolicy   domain.Policy
 }
 
 func NewCreateWishlistUseCase(
@@ -19,17 +20,19 @@ func NewCreateWishlistUseCase(
 	creator domain.WishlistCreationRepository,
 	customerGetter domain.GetCustomerByIDRepository,
 	idMaker domain.IDGenerator,
+	accessPolicy domain.Policy,
 ) *CreateWishlistUseCase {
 	return &CreateWishlistUseCase{
 		Getter:         getter,
 		Creator:        creator,
 		CustomerGetter: customerGetter,
 		IdMaker:        idMaker,
+		AccessPolicy:   accessPolicy,
 	}
 }
 func (u *CreateWishlistUseCase) CreateWishlist(ctx context.Context, currentCustomerId string, customerId string, title string) (string, error) {
-	if currentCustomerId != customerId {
-		return "", e.NewUnauthorizedError()
+	if err := u.AccessPolicy.Authorize(ctx, currentCustomerId, domain.PermissionWishlistWrite, customerId); err != nil {
+		return "", err
 	}
 
 	customer, err := u.CustomerGetter.GetByID(ctx, customerId)
@@ -63,7 +66,7 @@ func (u *CreateWishlistUseCase) CreateWishlist(ctx context.Context, currentCusto
 		ID:         newId,
 		CustomerId: customerId,
 		Title:      title,
-		Items:      []string{},
+		Items:      []domain.WishlistItem{},
 	}
 
 	err = u.Creator.Create(ctx, newWishlist)
@@ -48,7 +48,7 @@ func TestCreateWishlistUseCase_CreateWishlist(t *testing.T) {
 					ID:         "wishlist_123",
 					CustomerId: "customer_123",
 					Title:      "Birthday Wishlist",
-					Items:      []string{},
+					Items:      []domain.WishlistItem{},
 				}
 
 				wishlistCreator.EXPECT().
@@ -216,6 +216,7 @@ func TestCreateWishlistUseCase_CreateWishlist(t *testing.T) {
 				wishlistCreator,
 				customerGetter,
 				idGen,
+				ownerOnlyPolicy(ctrl),
 			)
 
 			id, err := uc.CreateWishlist(context.Background(), tt.currentCustomerID, tt.customerID, tt.title)
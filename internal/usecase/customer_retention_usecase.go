@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+// CustomerRetentionUseCase handles what happens to a customer after DeleteCustomer: during
+// gracePeriod the account only looks deleted and can be restored, after it the account is purged
+type CustomerRetentionUseCase struct {
+	gracePeriod   time.Duration
+	deletedGetter domain.GetDeletedCustomerRepository
+	restorer      domain.RestoreCustomerRepository
+	purger        domain.PurgeCustomersRepository
+	accessPolicy  domain.Policy
+}
+
+func NewCustomerRetentionUseCase(
+	gracePeriod time.Duration,
+	deletedGetter domain.GetDeletedCustomerRepository,
+	restorer domain.RestoreCustomerRepository,
+	purger domain.PurgeCustomersRepository,
+	accessPolicy domain.Policy,
+) *CustomerRetentionUseCase {
+	return &CustomerRetentionUseCase{
+		gracePeriod:   gracePeriod,
+		deletedGetter: deletedGetter,
+		restorer:      restorer,
+		purger:        purger,
+		accessPolicy:  accessPolicy,
+	}
+}
+
+func (u *CustomerRetentionUseCase) GetRestorable(ctx context.Context, email string) (*domain.Customer, error) {
+	return u.deletedGetter.GetDeletedByEmail(ctx, email, u.graceStart())
+}
+
+func (u *CustomerRetentionUseCase) GetRestorableByID(ctx context.Context, id string) (*domain.Customer, error) {
+	return u.deletedGetter.GetDeletedByID(ctx, id, u.graceStart())
+}
+
+func (u *CustomerRetentionUseCase) Restore(ctx context.Context, customer *domain.Customer) error {
+	now := time.Now()
+	if err := u.restorer.Restore(ctx, customer.ID, now); err != nil {
+		return err
+	}
+
+	customer.DeletedAt = time.Time{}
+	customer.UpdatedAt = now
+	return nil
+}
+
+// RestoreCustomer lets support restore an account on behalf of the customer, whose tokens
+// were all revoked by the deletion
+func (u *CustomerRetentionUseCase) RestoreCustomer(ctx context.Context, currentCustomerID string, customerID string) (*domain.OutgoingCustomer, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerWrite, customerID); err != nil {
+		return nil, err
+	}
+
+	customer, err := u.deletedGetter.GetDeletedByID(ctx, customerID, u.graceStart())
+	if err != nil {
+		return nil, err
+	}
+
+	if customer == nil {
+		return nil, e.NewNotFoundError("deleted customer")
+	}
+
+	if err := u.Restore(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	return &domain.OutgoingCustomer{
+		ID:        customer.ID,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		CreatedAt: customer.CreatedAt,
+	}, nil
+}
+
+func (u *CustomerRetentionUseCase) PurgeDeletedCustomers(ctx context.Context) (int64, error) {
+	return u.purger.PurgeDeletedBefore(ctx, u.graceStart())
+}
+
+// graceStart is the oldest deletion time that can still be restored
+func (u *CustomerRetentionUseCase) graceStart() time.Time {
+	return time.Now().Add(-u.gracePeriod)
+}
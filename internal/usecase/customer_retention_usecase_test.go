@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+const retentionGracePeriod = 30 * 24 * time.Hour
+
+func TestCustomerRetentionUseCase_GetRestorable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := mocks.NewMockGetDeletedCustomerRepository(ctrl)
+	deleted := &domain.Customer{ID: "customer_123", DeletedAt: time.Now().Add(-24 * time.Hour)}
+
+	mockGetter.EXPECT().
+		GetDeletedByEmail(gomock.Any(), "john@example.com", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, email string, deletedSince time.Time) (*domain.Customer, error) {
+			assert.WithinDuration(t, time.Now().Add(-retentionGracePeriod), deletedSince, time.Second)
+			return deleted, nil
+		})
+
+	uc := usecase.NewCustomerRetentionUseCase(retentionGracePeriod, mockGetter, nil, nil, nil)
+	customer, err := uc.GetRestorable(context.Background(), "john@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, deleted, customer)
+}
+
+func TestCustomerRetentionUseCase_GetRestorableByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := mocks.NewMockGetDeletedCustomerRepository(ctrl)
+	deleted := &domain.Customer{ID: "customer_123", DeletedAt: time.Now().Add(-24 * time.Hour)}
+
+	mockGetter.EXPECT().
+		GetDeletedByID(gomock.Any(), "customer_123", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id string, deletedSince time.Time) (*domain.Customer, error) {
+			assert.WithinDuration(t, time.Now().Add(-retentionGracePeriod), deletedSince, time.Second)
+			return deleted, nil
+		})
+
+	uc := usecase.NewCustomerRetentionUseCase(retentionGracePeriod, mockGetter, nil, nil, nil)
+	customer, err := uc.GetRestorableByID(context.Background(), "customer_123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, deleted, customer)
+}
+
+func TestCustomerRetentionUseCase_RestoreCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := mocks.NewMockGetDeletedCustomerRepository(ctrl)
+	mockRestorer := mocks.NewMockRestoreCustomerRepository(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expected      *domain.OutgoingCustomer
+		expectedError error
+	}{
+		{
+			name: "restores a customer inside the grace period",
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerWrite, "customer_123").Return(nil)
+				mockGetter.EXPECT().
+					GetDeletedByID(gomock.Any(), "customer_123", gomock.Any()).
+					Return(&domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com", DeletedAt: time.Now()}, nil)
+				mockRestorer.EXPECT().Restore(gomock.Any(), "customer_123", gomock.Any()).Return(nil)
+			},
+			expected: &domain.OutgoingCustomer{ID: "customer_123", Name: "John", Email: "john@example.com"},
+		},
+		{
+			name: "nothing to restore",
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerWrite, "customer_123").Return(nil)
+				mockGetter.EXPECT().GetDeletedByID(gomock.Any(), "customer_123", gomock.Any()).Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("deleted customer"),
+		},
+		{
+			name: "not allowed",
+			setupMocks: func() {
+				mockPolicy.EXPECT().
+					Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerWrite, "customer_123").
+					Return(e.NewUnauthorizedError())
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name: "restore error",
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mockGetter.EXPECT().
+					GetDeletedByID(gomock.Any(), "customer_123", gomock.Any()).
+					Return(&domain.Customer{ID: "customer_123"}, nil)
+				mockRestorer.EXPECT().Restore(gomock.Any(), "customer_123", gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewCustomerRetentionUseCase(retentionGracePeriod, mockGetter, mockRestorer, nil, mockPolicy)
+			result, err := uc.RestoreCustomer(context.Background(), "admin_123", "customer_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCustomerRetentionUseCase_PurgeDeletedCustomers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPurger := mocks.NewMockPurgeCustomersRepository(ctrl)
+
+	mockPurger.EXPECT().
+		PurgeDeletedBefore(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().Add(-retentionGracePeriod), before, time.Second)
+			return 3, nil
+		})
+
+	uc := usecase.NewCustomerRetentionUseCase(retentionGracePeriod, nil, nil, mockPurger, nil)
+	purged, err := uc.PurgeDeletedCustomers(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
@@ -0,0 +1,372 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+const (
+	dataExportJobCacheKey      = "data_export::%s"
+	dataExportArtifactCacheKey = "data_export_artifact::%s"
+	// dataExportCurrentCacheKey points to the pending job of a customer so a second request reuses it
+	dataExportCurrentCacheKey = "data_export_current::%s"
+
+	dataExportFailedMessage = "the export could not be generated, please request a new one"
+)
+
+// DataExportUseCase builds a customer data export in the background, jobs and artifacts
+// live in the cache and expire exportTTL after the job was started. A job still pending
+// after taskTimeout lost its runner and is reported as failed
+type DataExportUseCase struct {
+	exportTTL      time.Duration
+	taskTimeout    time.Duration
+	cache          domain.Cache
+	runner         domain.BackgroundRunner
+	idGen          domain.IDGenerator
+	customerGetter domain.GetCustomerByIDRepository
+	wishlistLister domain.WishlistByCustomerIdRepository
+	productGetter  domain.GetProductRepository
+	sessionLister  domain.ListAllSessionsRepository
+	auditLister    domain.ListAuditEventsRepository
+	preferences    domain.PreferencesReader
+	identityLister domain.ListOIDCIdentitiesRepository
+	mfaGetter      domain.GetCustomerMFARepository
+	apiKeyLister   domain.ListAPIKeysRepository
+	shareLister    domain.ListWishlistSharesRepository
+	// collaboratorLister lists who the customer invited, collaborationLister where they were invited
+	collaboratorLister  domain.ListCollaboratorsRepository
+	collaborationLister domain.ListCollaborationsRepository
+	reservationLister   domain.ListCustomerReservationsRepository
+	accessPolicy        domain.Policy
+}
+
+func NewDataExportUseCase(
+	exportTTL time.Duration,
+	taskTimeout time.Duration,
+	cache domain.Cache,
+	runner domain.BackgroundRunner,
+	idGen domain.IDGenerator,
+	customerGetter domain.GetCustomerByIDRepository,
+	wishlistLister domain.WishlistByCustomerIdRepository,
+	productGetter domain.GetProductRepository,
+	sessionLister domain.ListAllSessionsRepository,
+	auditLister domain.ListAuditEventsRepository,
+	preferences domain.PreferencesReader,
+	identityLister domain.ListOIDCIdentitiesRepository,
+	mfaGetter domain.GetCustomerMFARepository,
+	apiKeyLister domain.ListAPIKeysRepository,
+	shareLister domain.ListWishlistSharesRepository,
+	collaboratorLister domain.ListCollaboratorsRepository,
+	collaborationLister domain.ListCollaborationsRepository,
+	reservationLister domain.ListCustomerReservationsRepository,
+	accessPolicy domain.Policy,
+) *DataExportUseCase {
+	return &DataExportUseCase{
+		exportTTL:           exportTTL,
+		taskTimeout:         taskTimeout,
+		cache:               cache,
+		runner:              runner,
+		idGen:               idGen,
+		customerGetter:      customerGetter,
+		wishlistLister:      wishlistLister,
+		productGetter:       productGetter,
+		sessionLister:       sessionLister,
+		auditLister:         auditLister,
+		preferences:         preferences,
+		identityLister:      identityLister,
+		mfaGetter:           mfaGetter,
+		apiKeyLister:        apiKeyLister,
+		shareLister:         shareLister,
+		collaboratorLister:  collaboratorLister,
+		collaborationLister: collaborationLister,
+		reservationLister:   reservationLister,
+		accessPolicy:        accessPolicy,
+	}
+}
+
+func (u *DataExportUseCase) StartDataExport(ctx context.Context, currentCustomerID string, customerID string) (*domain.DataExportJob, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerRead, customerID); err != nil {
+		return nil, err
+	}
+
+	customer, err := u.customerGetter.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer == nil {
+		return nil, e.NewNotFoundError("customer")
+	}
+
+	currentJobID, err := u.cache.Get(ctx, fmt.Sprintf(dataExportCurrentCacheKey, customerID))
+	if err != nil {
+		return nil, err
+	}
+
+	if currentJobID != "" {
+		current, err := u.getJob(ctx, currentJobID)
+		if err != nil {
+			return nil, err
+		}
+		if current != nil && current.Status == domain.DataExportPending {
+			return current, nil
+		}
+
+		// only the current job keeps an artifact so EvictCustomer knows what to remove
+		if err := u.deleteJob(ctx, currentJobID); err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate data export ID"))
+	}
+
+	now := time.Now()
+	job := &domain.DataExportJob{
+		ID:         id,
+		CustomerID: customerID,
+		Status:     domain.DataExportPending,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(u.exportTTL),
+	}
+
+	if err := u.saveJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if err := u.cache.Set(ctx, fmt.Sprintf(dataExportCurrentCacheKey, customerID), job.ID, u.exportTTL); err != nil {
+		return nil, err
+	}
+
+	u.runner.Go(func(ctx context.Context) {
+		u.RunDataExport(ctx, job)
+	})
+
+	return job, nil
+}
+
+// RunDataExport builds the artifact of a pending job and stores the outcome, errors are
+// kept on the job since nobody is waiting for this call
+func (u *DataExportUseCase) RunDataExport(ctx context.Context, job *domain.DataExportJob) {
+	artifact, err := u.buildExport(ctx, job.CustomerID)
+	if err == nil {
+		err = u.cache.Set(ctx, fmt.Sprintf(dataExportArtifactCacheKey, job.ID), string(artifact), time.Until(job.ExpiresAt))
+	}
+
+	job.CompletedAt = time.Now()
+	job.Status = domain.DataExportReady
+	if err != nil {
+		fmt.Printf("[data_export_usecase] ERROR building data export %s: %v\n", job.ID, err)
+		job.Status = domain.DataExportFailed
+		job.Error = dataExportFailedMessage
+	}
+
+	// ctx is done once the task timed out, which is when the failure most needs to be stored
+	if err := u.saveJob(context.WithoutCancel(ctx), job); err != nil {
+		fmt.Printf("[data_export_usecase] ERROR storing data export %s: %v\n", job.ID, err)
+	}
+}
+
+func (u *DataExportUseCase) GetDataExport(ctx context.Context, currentCustomerID string, customerID string, jobID string) (*domain.DataExportJob, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerRead, customerID); err != nil {
+		return nil, err
+	}
+
+	job, err := u.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil || job.CustomerID != customerID {
+		return nil, e.NewNotFoundError("data export")
+	}
+
+	return job, nil
+}
+
+func (u *DataExportUseCase) DownloadDataExport(ctx context.Context, currentCustomerID string, customerID string, jobID string) ([]byte, error) {
+	job, err := u.GetDataExport(ctx, currentCustomerID, customerID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != domain.DataExportReady {
+		return nil, &e.ValidationError{Field: "export", Err: fmt.Sprintf("is %s, it can only be downloaded once ready", job.Status)}
+	}
+
+	artifact, err := u.cache.Get(ctx, fmt.Sprintf(dataExportArtifactCacheKey, job.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	if artifact == "" {
+		return nil, e.NewNotFoundError("data export")
+	}
+
+	return []byte(artifact), nil
+}
+
+// EvictCustomer removes the current export of the customer along with its artifact
+func (u *DataExportUseCase) EvictCustomer(ctx context.Context, customer *domain.Customer) error {
+	currentKey := fmt.Sprintf(dataExportCurrentCacheKey, customer.ID)
+	currentJobID, err := u.cache.Get(ctx, currentKey)
+	if err != nil {
+		return err
+	}
+
+	if currentJobID != "" {
+		if err := u.deleteJob(ctx, currentJobID); err != nil {
+			return err
+		}
+	}
+
+	return u.cache.Delete(ctx, currentKey)
+}
+
+func (u *DataExportUseCase) buildExport(ctx context.Context, customerID string) ([]byte, error) {
+	customer, err := u.customerGetter.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer == nil {
+		return nil, e.NewNotFoundError("customer")
+	}
+
+	export := domain.CustomerDataExport{
+		GeneratedAt: time.Now(),
+		Profile: domain.ExportedProfile{
+			ID:         customer.ID,
+			Name:       customer.Name,
+			Email:      customer.Email,
+			Role:       customer.Role,
+			CreatedAt:  customer.CreatedAt,
+			UpdatedAt:  customer.UpdatedAt,
+			VerifiedAt: customer.VerifiedAt,
+		},
+		Wishlists: []domain.ExportedWishlist{},
+	}
+
+	export.Preferences, err = u.preferences.GetCustomerPreferences(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.Identities, err = u.identityLister.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.MFA, err = u.mfaGetter.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.APIKeys, err = u.apiKeyLister.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlists, err := u.wishlistLister.GetByCustomerId(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, wishlist := range wishlists {
+		exported := domain.ExportedWishlist{
+			ID:       wishlist.ID,
+			Title:    wishlist.Title,
+			Items:    wishlist.Items,
+			Products: []domain.Product{},
+		}
+
+		for _, productID := range wishlist.ProductIDs() {
+			product, err := u.productGetter.GetByID(ctx, productID)
+			if err != nil {
+				return nil, err
+			}
+			if product != nil {
+				exported.Products = append(exported.Products, *product)
+			}
+		}
+
+		exported.Shares, err = u.shareLister.ListByWishlistID(ctx, wishlist.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		exported.Collaborators, err = u.collaboratorLister.ListByWishlistID(ctx, wishlist.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		export.Wishlists = append(export.Wishlists, exported)
+	}
+
+	export.Collaborations, err = u.collaborationLister.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.Reservations, err = u.reservationLister.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.Sessions, err = u.sessionLister.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.AuditEvents, err = u.auditLister.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(export, "", "  ")
+}
+
+func (u *DataExportUseCase) getJob(ctx context.Context, jobID string) (*domain.DataExportJob, error) {
+	value, err := u.cache.Get(ctx, fmt.Sprintf(dataExportJobCacheKey, jobID))
+	if err != nil {
+		return nil, err
+	}
+
+	if value == "" {
+		return nil, nil
+	}
+
+	var job domain.DataExportJob
+	if err := json.Unmarshal([]byte(value), &job); err != nil {
+		return nil, err
+	}
+
+	// the runner was stopped before it could store the outcome, e.g. by a restart
+	if job.Status == domain.DataExportPending && time.Since(job.CreatedAt) > u.taskTimeout {
+		job.Status = domain.DataExportFailed
+		job.Error = dataExportFailedMessage
+	}
+
+	return &job, nil
+}
+
+func (u *DataExportUseCase) saveJob(ctx context.Context, job *domain.DataExportJob) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return u.cache.Set(ctx, fmt.Sprintf(dataExportJobCacheKey, job.ID), string(value), time.Until(job.ExpiresAt))
+}
+
+func (u *DataExportUseCase) deleteJob(ctx context.Context, jobID string) error {
+	return u.cache.Delete(ctx, fmt.Sprintf(dataExportJobCacheKey, jobID), fmt.Sprintf(dataExportArtifactCacheKey, jobID))
+}
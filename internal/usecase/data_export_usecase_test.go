@@ -0,0 +1,410 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+// inMemoryCache backs a cache mock with stored
+func inMemoryCache(ctrl *gomock.Controller, stored map[string]string) *mocks.MockCache {
+	cache := mocks.NewMockCache(ctrl)
+	cache.EXPECT().
+		Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, value string, expiration time.Duration) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			stored[key] = value
+			return nil
+		}).
+		AnyTimes()
+	cache.EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string) (string, error) {
+			return stored[key], nil
+		}).
+		AnyTimes()
+	cache.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, keys ...string) error {
+			for _, key := range keys {
+				delete(stored, key)
+			}
+			return nil
+		}).
+		AnyTimes()
+
+	return cache
+}
+
+// inlineRunner runs background tasks before Go returns
+func inlineRunner(ctrl *gomock.Controller) *mocks.MockBackgroundRunner {
+	runner := mocks.NewMockBackgroundRunner(ctrl)
+	runner.EXPECT().
+		Go(gomock.Any()).
+		Do(func(task func(ctx context.Context)) {
+			task(context.Background())
+		}).
+		AnyTimes()
+
+	return runner
+}
+
+func TestDataExportUseCase_StartDataExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stored := map[string]string{}
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistLister := mocks.NewMockWishlistByCustomerIdRepository(ctrl)
+	mockProductGetter := mocks.NewMockGetProductRepository(ctrl)
+	mockSessionLister := mocks.NewMockListAllSessionsRepository(ctrl)
+	mockAuditLister := mocks.NewMockListAuditEventsRepository(ctrl)
+	mockPreferences := mocks.NewMockPreferencesReader(ctrl)
+	mockIdentityLister := mocks.NewMockListOIDCIdentitiesRepository(ctrl)
+	mockMFAGetter := mocks.NewMockGetCustomerMFARepository(ctrl)
+	mockAPIKeyLister := mocks.NewMockListAPIKeysRepository(ctrl)
+	mockShareLister := mocks.NewMockListWishlistSharesRepository(ctrl)
+	mockCollaboratorLister := mocks.NewMockListCollaboratorsRepository(ctrl)
+	mockCollaborationLister := mocks.NewMockListCollaborationsRepository(ctrl)
+	mockReservationLister := mocks.NewMockListCustomerReservationsRepository(ctrl)
+
+	uc := usecase.NewDataExportUseCase(
+		24*time.Hour,
+		5*time.Minute,
+		inMemoryCache(ctrl, stored),
+		inlineRunner(ctrl),
+		mockIDGen,
+		mockCustomerGetter,
+		mockWishlistLister,
+		mockProductGetter,
+		mockSessionLister,
+		mockAuditLister,
+		mockPreferences,
+		mockIdentityLister,
+		mockMFAGetter,
+		mockAPIKeyLister,
+		mockShareLister,
+		mockCollaboratorLister,
+		mockCollaborationLister,
+		mockReservationLister,
+		ownerOnlyPolicy(ctrl),
+	)
+
+	customer := &domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com", Password: "hashed"}
+	released := time.Now().Add(-time.Hour)
+
+	t.Run("builds the export in the background", func(t *testing.T) {
+		clear(stored)
+
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil).Times(2)
+		mockIDGen.EXPECT().Generate().Return("job_123", nil)
+		mockPreferences.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(domain.DefaultPreferences("customer_123"), nil)
+		mockIdentityLister.EXPECT().
+			ListByCustomerID(gomock.Any(), "customer_123").
+			Return([]*domain.OIDCIdentity{{ID: "identity_123", Issuer: "https://accounts.example.com", Email: "john@provider.com"}}, nil)
+		mockMFAGetter.EXPECT().
+			GetByCustomerID(gomock.Any(), "customer_123").
+			Return(&domain.CustomerMFA{CustomerID: "customer_123", Secret: "encrypted_secret", EnabledAt: time.Now()}, nil)
+		mockAPIKeyLister.EXPECT().
+			ListByCustomerID(gomock.Any(), "customer_123").
+			Return([]*domain.APIKey{{ID: "key_123", Name: "Zapier", KeyHash: "key_hash"}}, nil)
+		mockWishlistLister.EXPECT().
+			GetByCustomerId(gomock.Any(), "customer_123").
+			Return([]*domain.Wishlist{{ID: "wishlist_123", Title: "Birthday", Items: []domain.WishlistItem{{ProductID: "product_1"}, {ProductID: "product_2"}}}}, nil)
+		mockProductGetter.EXPECT().GetByID(gomock.Any(), "product_1").Return(&domain.Product{ID: "product_1", Name: "Chair"}, nil)
+		mockProductGetter.EXPECT().GetByID(gomock.Any(), "product_2").Return(nil, nil)
+		mockShareLister.EXPECT().
+			ListByWishlistID(gomock.Any(), "wishlist_123").
+			Return([]*domain.WishlistShare{{ID: "share_123", WishlistID: "wishlist_123", TokenHash: "token_hash"}}, nil)
+		mockCollaboratorLister.EXPECT().
+			ListByWishlistID(gomock.Any(), "wishlist_123").
+			Return([]*domain.WishlistCollaborator{{WishlistID: "wishlist_123", CustomerID: "friend_123", Role: domain.CollaboratorRoleEditor}}, nil)
+		mockCollaborationLister.EXPECT().
+			ListByCustomerID(gomock.Any(), "customer_123").
+			Return([]*domain.WishlistCollaborator{{WishlistID: "wishlist_456", CustomerID: "customer_123", Role: domain.CollaboratorRoleViewer}}, nil)
+		mockReservationLister.EXPECT().
+			ListByCustomerID(gomock.Any(), "customer_123").
+			Return([]*domain.WishlistReservation{{ID: "reservation_123", WishlistID: "wishlist_789", ProductID: "product_3", ReleasedAt: &released}}, nil)
+		mockSessionLister.EXPECT().ListByCustomerID(gomock.Any(), "customer_123").Return([]*domain.Session{{ID: "session_123"}}, nil)
+		mockAuditLister.EXPECT().ListByCustomerID(gomock.Any(), "customer_123").Return([]*domain.AuditEvent{{ID: "event_123"}}, nil)
+
+		job, err := uc.StartDataExport(context.Background(), "customer_123", "customer_123")
+		require.NoError(t, err)
+		assert.Equal(t, "job_123", job.ID)
+
+		job, err = uc.GetDataExport(context.Background(), "customer_123", "customer_123", "job_123")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DataExportReady, job.Status)
+
+		artifact, err := uc.DownloadDataExport(context.Background(), "customer_123", "customer_123", "job_123")
+		require.NoError(t, err)
+		assert.NotContains(t, string(artifact), "hashed")
+		assert.NotContains(t, string(artifact), "encrypted_secret")
+		assert.NotContains(t, string(artifact), "key_hash")
+		assert.NotContains(t, string(artifact), "token_hash")
+
+		var export domain.CustomerDataExport
+		require.NoError(t, json.Unmarshal(artifact, &export))
+		assert.Equal(t, "john@example.com", export.Profile.Email)
+		assert.Equal(t, "USD", export.Preferences.Currency)
+		assert.Equal(t, []string{"product_1", "product_2"}, (&domain.Wishlist{Items: export.Wishlists[0].Items}).ProductIDs())
+		assert.Equal(t, []domain.Product{{ID: "product_1", Name: "Chair"}}, export.Wishlists[0].Products)
+		assert.Equal(t, "share_123", export.Wishlists[0].Shares[0].ID)
+		assert.Equal(t, "friend_123", export.Wishlists[0].Collaborators[0].CustomerID)
+		assert.Equal(t, "john@provider.com", export.Identities[0].Email)
+		assert.True(t, export.MFA.Enabled())
+		assert.Equal(t, "Zapier", export.APIKeys[0].Name)
+		assert.Equal(t, "wishlist_456", export.Collaborations[0].WishlistID)
+		assert.NotNil(t, export.Reservations[0].ReleasedAt)
+		assert.Len(t, export.Sessions, 1)
+		assert.Len(t, export.AuditEvents, 1)
+	})
+
+	t.Run("reuses a pending job", func(t *testing.T) {
+		clear(stored)
+
+		pending, _ := json.Marshal(domain.DataExportJob{ID: "job_123", CustomerID: "customer_123", Status: domain.DataExportPending, CreatedAt: time.Now()})
+		stored["data_export_current::customer_123"] = "job_123"
+		stored["data_export::job_123"] = string(pending)
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+
+		job, err := uc.StartDataExport(context.Background(), "customer_123", "customer_123")
+		require.NoError(t, err)
+		assert.Equal(t, "job_123", job.ID)
+	})
+
+	t.Run("replaces a job pending past the task timeout", func(t *testing.T) {
+		clear(stored)
+
+		stale, _ := json.Marshal(domain.DataExportJob{ID: "job_123", CustomerID: "customer_123", Status: domain.DataExportPending, CreatedAt: time.Now().Add(-time.Hour)})
+		stored["data_export_current::customer_123"] = "job_123"
+		stored["data_export::job_123"] = string(stale)
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+		// the new export itself is not under test here
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, errors.New("database error"))
+		mockIDGen.EXPECT().Generate().Return("job_456", nil)
+
+		job, err := uc.StartDataExport(context.Background(), "customer_123", "customer_123")
+		require.NoError(t, err)
+		assert.Equal(t, "job_456", job.ID)
+		assert.NotContains(t, stored, "data_export::job_123")
+	})
+
+	t.Run("replaces a ready job", func(t *testing.T) {
+		clear(stored)
+
+		ready, _ := json.Marshal(domain.DataExportJob{ID: "job_123", CustomerID: "customer_123", Status: domain.DataExportReady})
+		stored["data_export_current::customer_123"] = "job_123"
+		stored["data_export::job_123"] = string(ready)
+		stored["data_export_artifact::job_123"] = "{}"
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+		// the new export itself is not under test here
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, errors.New("database error"))
+		mockIDGen.EXPECT().Generate().Return("job_456", nil)
+
+		job, err := uc.StartDataExport(context.Background(), "customer_123", "customer_123")
+		require.NoError(t, err)
+		assert.Equal(t, "job_456", job.ID)
+		assert.NotContains(t, stored, "data_export_artifact::job_123")
+	})
+
+	t.Run("marks the job as failed", func(t *testing.T) {
+		clear(stored)
+
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil).Times(2)
+		mockIDGen.EXPECT().Generate().Return("job_123", nil)
+		mockPreferences.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(nil, errors.New("database error"))
+
+		_, err := uc.StartDataExport(context.Background(), "customer_123", "customer_123")
+		require.NoError(t, err)
+
+		job, err := uc.GetDataExport(context.Background(), "customer_123", "customer_123", "job_123")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DataExportFailed, job.Status)
+
+		_, err = uc.DownloadDataExport(context.Background(), "customer_123", "customer_123", "job_123")
+		assert.True(t, e.IsValidationError(err))
+	})
+
+	t.Run("stores the failure of a timed out export", func(t *testing.T) {
+		clear(stored)
+
+		timedOutRunner := mocks.NewMockBackgroundRunner(ctrl)
+		timedOutRunner.EXPECT().
+			Go(gomock.Any()).
+			Do(func(task func(ctx context.Context)) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				task(ctx)
+			})
+		uc := usecase.NewDataExportUseCase(
+			24*time.Hour,
+			5*time.Minute,
+			inMemoryCache(ctrl, stored),
+			timedOutRunner,
+			mockIDGen,
+			mockCustomerGetter,
+			mockWishlistLister,
+			mockProductGetter,
+			mockSessionLister,
+			mockAuditLister,
+			mockPreferences,
+			mockIdentityLister,
+			mockMFAGetter,
+			mockAPIKeyLister,
+			mockShareLister,
+			mockCollaboratorLister,
+			mockCollaborationLister,
+			mockReservationLister,
+			ownerOnlyPolicy(ctrl),
+		)
+
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, context.Canceled)
+		mockIDGen.EXPECT().Generate().Return("job_123", nil)
+
+		_, err := uc.StartDataExport(context.Background(), "customer_123", "customer_123")
+		require.NoError(t, err)
+
+		job, err := uc.GetDataExport(context.Background(), "customer_123", "customer_123", "job_123")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DataExportFailed, job.Status)
+	})
+
+	t.Run("another customer", func(t *testing.T) {
+		clear(stored)
+
+		_, err := uc.StartDataExport(context.Background(), "other_123", "customer_123")
+		assert.EqualError(t, err, e.NewUnauthorizedError().Error())
+	})
+
+	t.Run("customer not found", func(t *testing.T) {
+		clear(stored)
+
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+
+		_, err := uc.StartDataExport(context.Background(), "customer_123", "customer_123")
+		assert.EqualError(t, err, e.NewNotFoundError("customer").Error())
+	})
+}
+
+func TestDataExportUseCase_GetDataExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stored := map[string]string{}
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistLister := mocks.NewMockWishlistByCustomerIdRepository(ctrl)
+	mockProductGetter := mocks.NewMockGetProductRepository(ctrl)
+	mockSessionLister := mocks.NewMockListAllSessionsRepository(ctrl)
+	mockAuditLister := mocks.NewMockListAuditEventsRepository(ctrl)
+	mockPreferences := mocks.NewMockPreferencesReader(ctrl)
+
+	uc := usecase.NewDataExportUseCase(
+		24*time.Hour,
+		5*time.Minute,
+		inMemoryCache(ctrl, stored),
+		inlineRunner(ctrl),
+		mockIDGen,
+		mockCustomerGetter,
+		mockWishlistLister,
+		mockProductGetter,
+		mockSessionLister,
+		mockAuditLister,
+		mockPreferences,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		ownerOnlyPolicy(ctrl),
+	)
+	job, _ := json.Marshal(domain.DataExportJob{ID: "job_123", CustomerID: "other_123", Status: domain.DataExportReady})
+	stored["data_export::job_123"] = string(job)
+
+	tests := []struct {
+		name          string
+		jobID         string
+		expectedError error
+	}{
+		{
+			name:          "job of another customer",
+			jobID:         "job_123",
+			expectedError: e.NewNotFoundError("data export"),
+		},
+		{
+			name:          "expired job",
+			jobID:         "job_456",
+			expectedError: e.NewNotFoundError("data export"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := uc.GetDataExport(context.Background(), "customer_123", "customer_123", tt.jobID)
+
+			assert.EqualError(t, err, tt.expectedError.Error())
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestDataExportUseCase_EvictCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stored := map[string]string{}
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistLister := mocks.NewMockWishlistByCustomerIdRepository(ctrl)
+	mockProductGetter := mocks.NewMockGetProductRepository(ctrl)
+	mockSessionLister := mocks.NewMockListAllSessionsRepository(ctrl)
+	mockAuditLister := mocks.NewMockListAuditEventsRepository(ctrl)
+	mockPreferences := mocks.NewMockPreferencesReader(ctrl)
+
+	uc := usecase.NewDataExportUseCase(
+		24*time.Hour,
+		5*time.Minute,
+		inMemoryCache(ctrl, stored),
+		inlineRunner(ctrl),
+		mockIDGen,
+		mockCustomerGetter,
+		mockWishlistLister,
+		mockProductGetter,
+		mockSessionLister,
+		mockAuditLister,
+		mockPreferences,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		ownerOnlyPolicy(ctrl),
+	)
+	stored["data_export_current::customer_123"] = "job_123"
+	stored["data_export::job_123"] = "{}"
+	stored["data_export_artifact::job_123"] = "{}"
+
+	err := uc.EvictCustomer(context.Background(), &domain.Customer{ID: "customer_123"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, stored)
+}
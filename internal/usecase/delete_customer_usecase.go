@@ -9,23 +9,29 @@ import (
 )
 
 type DeleteCustomerUseCase struct {
-	Updater domain.UpdateCustomerRepository
-	Getter  domain.GetCustomerByIDRepository
+	Updater      domain.UpdateCustomerRepository
+	Getter       domain.GetCustomerByIDRepository
+	Revoker      domain.TokenRevoker
+	AccessPolicy domain.Policy
 }
 
 func NewDeleteCustomerUseCase(
 	updater domain.UpdateCustomerRepository,
 	getter domain.GetCustomerByIDRepository,
+	revoker domain.TokenRevoker,
+	accessPolicy domain.Policy,
 ) *DeleteCustomerUseCase {
 	return &DeleteCustomerUseCase{
-		Updater: updater,
-		Getter:  getter,
+		Updater:      updater,
+		Getter:       getter,
+		Revoker:      revoker,
+		AccessPolicy: accessPolicy,
 	}
 }
 
 func (u *DeleteCustomerUseCase) DeleteCustomer(ctx context.Context, currentCustomerID string, customerID string) error {
-	if currentCustomerID != customerID {
-		return e.NewUnauthorizedError()
+	if err := u.AccessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerWrite, customerID); err != nil {
+		return err
 	}
 
 	customer, err := u.Getter.GetByID(ctx, customerID)
@@ -43,5 +49,5 @@ func (u *DeleteCustomerUseCase) DeleteCustomer(ctx context.Context, currentCusto
 		return err
 	}
 
-	return nil
+	return u.Revoker.RevokeAllForCustomer(ctx, customerID)
 }
@@ -20,6 +20,7 @@ func TestDeleteCustomerUseCase_DeleteCustomer(t *testing.T) {
 
 	mockUpdater := mocks.NewMockUpdateCustomerRepository(ctrl)
 	mockGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
 
 	tests := []struct {
 		name              string
@@ -51,6 +52,10 @@ func TestDeleteCustomerUseCase_DeleteCustomer(t *testing.T) {
 						assert.NotZero(t, c.DeletedAt)
 						return nil
 					})
+
+				mockRevoker.EXPECT().
+					RevokeAllForCustomer(gomock.Any(), "customer_123").
+					Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -106,13 +111,32 @@ func TestDeleteCustomerUseCase_DeleteCustomer(t *testing.T) {
 			},
 			expectedError: errors.New("update error"),
 		},
+		{
+			name:              "token revocation error",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			setupMocks: func() {
+				mockGetter.EXPECT().
+					GetByID(gomock.Any(), "customer_123").
+					Return(&domain.Customer{ID: "customer_123"}, nil)
+
+				mockUpdater.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					Return(nil)
+
+				mockRevoker.EXPECT().
+					RevokeAllForCustomer(gomock.Any(), "customer_123").
+					Return(errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
 
-			uc := usecase.NewDeleteCustomerUseCase(mockUpdater, mockGetter)
+			uc := usecase.NewDeleteCustomerUseCase(mockUpdater, mockGetter, mockRevoker, ownerOnlyPolicy(ctrl))
 			err := uc.DeleteCustomer(context.Background(), tt.currentCustomerID, tt.customerID)
 
 			if tt.expectedError != nil {
@@ -11,23 +11,28 @@ type DeleteWishlistUseCase struct {
 	CustomerGetter  domain.GetCustomerByIDRepository
 	WishlistGetter  domain.WishlistByIdRepository
 	WishlistDeleter domain.DeleteWishlistRepository
+	AccessPolicy    domain.Policy
 }
 
 func NewDeleteWishlistUseCase(
 	customerGetter domain.GetCustomerByIDRepository,
 	wishlistGetter domain.WishlistByIdRepository,
 	wishlistDeleter domain.DeleteWishlistRepository,
+	accessPolicy domain.Policy,
 ) *DeleteWishlistUseCase {
 	return &DeleteWishlistUseCase{
 		CustomerGetter:  customerGetter,
 		WishlistGetter:  wishlistGetter,
 		WishlistDeleter: wishlistDeleter,
+		AccessPolicy:    accessPolicy,
 	}
 }
 
+// DeleteWishlist goes through the access policy and not the wishlist policy, only the owner
+// (or staff allowed to write any wishlist) may delete it, collaborators can't
 func (u *DeleteWishlistUseCase) DeleteWishlist(ctx context.Context, currentCustomerId string, customerId string, wishlistId string) error {
-	if currentCustomerId != customerId {
-		return e.NewUnauthorizedError()
+	if err := u.AccessPolicy.Authorize(ctx, currentCustomerId, domain.PermissionWishlistWrite, customerId); err != nil {
+		return err
 	}
 
 	customer, err := u.CustomerGetter.GetByID(ctx, customerId)
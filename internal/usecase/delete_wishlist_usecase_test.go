@@ -41,7 +41,7 @@ func TestDeleteWishlistUseCase_DeleteWishlist(t *testing.T) {
 						ID:         "wishlist_123",
 						CustomerId: "customer_123",
 						Title:      "Birthday Wishlist",
-						Items:      []string{},
+						Items:      []domain.WishlistItem{},
 					}, nil).
 					Times(1)
 
@@ -145,7 +145,7 @@ func TestDeleteWishlistUseCase_DeleteWishlist(t *testing.T) {
 						ID:         "wishlist_123",
 						CustomerId: "different_customer",
 						Title:      "Birthday Wishlist",
-						Items:      []string{},
+						Items:      []domain.WishlistItem{},
 					}, nil)
 			},
 			expectedError: e.NewUnauthorizedError(),
@@ -168,7 +168,7 @@ func TestDeleteWishlistUseCase_DeleteWishlist(t *testing.T) {
 						ID:         "wishlist_123",
 						CustomerId: "customer_123",
 						Title:      "Birthday Wishlist",
-						Items:      []string{},
+						Items:      []domain.WishlistItem{},
 					}, nil)
 
 				wishlistDeleter.EXPECT().
@@ -196,6 +196,7 @@ func TestDeleteWishlistUseCase_DeleteWishlist(t *testing.T) {
 				customerGetter,
 				wishlistGetter,
 				wishlistDeleter,
+				ownerOnlyPolicy(ctrl),
 			)
 
 			err := uc.DeleteWishlist(context.Background(), tt.currentCustomerId, tt.customerId, tt.wishlistId)
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type EmailVerificationConfig struct {
+	// Required turns verification off when false, meant for local development only
+	Required  bool
+	TokenTTL  time.Duration
+	VerifyURL string
+}
+
+type EmailVerificationUseCase struct {
+	config           EmailVerificationConfig
+	customerGetter   domain.GetCustomerByIDRepository
+	idGen            domain.IDGenerator
+	tokenGen         domain.TokenGenerator
+	tokenHasher      domain.Hasher
+	tokenStorer      domain.OneTimeTokenCreationRepository
+	tokenConsumer    domain.ConsumeOneTimeTokenRepository
+	tokenInvalidator domain.InvalidateOneTimeTokensRepository
+	verifiedMarker   domain.MarkCustomerVerifiedRepository
+	mailer           domain.Mailer
+}
+
+func NewEmailVerificationUseCase(
+	config EmailVerificationConfig,
+	customerGetter domain.GetCustomerByIDRepository,
+	idGen domain.IDGenerator,
+	tokenGen domain.TokenGenerator,
+	tokenHasher domain.Hasher,
+	tokenStorer domain.OneTimeTokenCreationRepository,
+	tokenConsumer domain.ConsumeOneTimeTokenRepository,
+	tokenInvalidator domain.InvalidateOneTimeTokensRepository,
+	verifiedMarker domain.MarkCustomerVerifiedRepository,
+	mailer domain.Mailer,
+) *EmailVerificationUseCase {
+	return &EmailVerificationUseCase{
+		config:           config,
+		customerGetter:   customerGetter,
+		idGen:            idGen,
+		tokenGen:         tokenGen,
+		tokenHasher:      tokenHasher,
+		tokenStorer:      tokenStorer,
+		tokenConsumer:    tokenConsumer,
+		tokenInvalidator: tokenInvalidator,
+		verifiedMarker:   verifiedMarker,
+		mailer:           mailer,
+	}
+}
+
+// SendVerification invalidates any pending link and emails a new one to the customer
+func (u *EmailVerificationUseCase) SendVerification(ctx context.Context, customer *domain.Customer) error {
+	if !u.config.Required || !customer.VerifiedAt.IsZero() {
+		return nil
+	}
+
+	token, err := u.tokenGen.Generate()
+	if err != nil {
+		return errors.Join(err, errors.New("failed to generate verification token"))
+	}
+
+	hash, err := u.tokenHasher.Hash(token)
+	if err != nil {
+		return errors.Join(err, errors.New("failed to hash verification token"))
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return errors.Join(err, errors.New("failed to generate verification token ID"))
+	}
+
+	now := time.Now()
+	if err := u.tokenInvalidator.InvalidateForCustomer(ctx, customer.ID, domain.OneTimeTokenEmailVerification, now); err != nil {
+		return err
+	}
+
+	err = u.tokenStorer.Create(ctx, &domain.OneTimeToken{
+		ID:         id,
+		CustomerID: customer.ID,
+		Purpose:    domain.OneTimeTokenEmailVerification,
+		TokenHash:  hash,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(u.config.TokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, domain.MailMessage{
+		To:      customer.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to confirm your email address, it expires in %s:\n\n%s?token=%s\n\nIf you did not create an account you can ignore this email.",
+			customer.Name,
+			u.config.TokenTTL,
+			u.config.VerifyURL,
+			token,
+		),
+	})
+}
+
+func (u *EmailVerificationUseCase) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return e.NewRequiredFieldError("token")
+	}
+
+	tokenHash, err := u.tokenHasher.Hash(token)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	consumed, err := u.tokenConsumer.Consume(ctx, domain.OneTimeTokenEmailVerification, tokenHash, now)
+	if err != nil {
+		return err
+	}
+
+	if consumed == nil {
+		return &e.ValidationError{Field: "token", Err: "is invalid or expired"}
+	}
+
+	return u.verifiedMarker.MarkVerified(ctx, consumed.CustomerID, now)
+}
+
+func (u *EmailVerificationUseCase) ResendVerification(ctx context.Context, currentCustomerID string) error {
+	customer, err := u.customerGetter.GetByID(ctx, currentCustomerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		return e.NewNotFoundError("customer")
+	}
+
+	if !customer.VerifiedAt.IsZero() {
+		return &e.ValidationError{Field: "email", Err: "is already verified"}
+	}
+
+	return u.SendVerification(ctx, customer)
+}
+
+func (u *EmailVerificationUseCase) EnsureVerified(customer *domain.Customer) error {
+	if !u.config.Required || !customer.VerifiedAt.IsZero() {
+		return nil
+	}
+
+	return e.NewForbiddenError("email address is not verified")
+}
+
+// ensureVerifiedCustomer loads the customer and applies the guard, actions reaching other people go through it
+func ensureVerifiedCustomer(ctx context.Context, customerGetter domain.GetCustomerByIDRepository, guard domain.VerifiedEmailGuard, customerID string) error {
+	customer, err := customerGetter.GetByID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		return e.NewNotFoundError("customer")
+	}
+
+	return guard.EnsureVerified(customer)
+}
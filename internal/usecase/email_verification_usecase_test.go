@@ -0,0 +1,216 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+type emailVerificationMocks struct {
+	customerGetter   *mocks.MockGetCustomerByIDRepository
+	idGen            *mocks.MockIDGenerator
+	tokenGen         *mocks.MockTokenGenerator
+	tokenHasher      *mocks.MockHasher
+	tokenStorer      *mocks.MockOneTimeTokenCreationRepository
+	tokenConsumer    *mocks.MockConsumeOneTimeTokenRepository
+	tokenInvalidator *mocks.MockInvalidateOneTimeTokensRepository
+	verifiedMarker   *mocks.MockMarkCustomerVerifiedRepository
+	mailer           *mocks.MockMailer
+}
+
+func newEmailVerificationUseCase(ctrl *gomock.Controller, required bool) (*usecase.EmailVerificationUseCase, emailVerificationMocks) {
+	m := emailVerificationMocks{
+		customerGetter:   mocks.NewMockGetCustomerByIDRepository(ctrl),
+		idGen:            mocks.NewMockIDGenerator(ctrl),
+		tokenGen:         mocks.NewMockTokenGenerator(ctrl),
+		tokenHasher:      mocks.NewMockHasher(ctrl),
+		tokenStorer:      mocks.NewMockOneTimeTokenCreationRepository(ctrl),
+		tokenConsumer:    mocks.NewMockConsumeOneTimeTokenRepository(ctrl),
+		tokenInvalidator: mocks.NewMockInvalidateOneTimeTokensRepository(ctrl),
+		verifiedMarker:   mocks.NewMockMarkCustomerVerifiedRepository(ctrl),
+		mailer:           mocks.NewMockMailer(ctrl),
+	}
+
+	uc := usecase.NewEmailVerificationUseCase(
+		usecase.EmailVerificationConfig{
+			Required:  required,
+			TokenTTL:  48 * time.Hour,
+			VerifyURL: "https://wishlist.local/api/auth/verify",
+		},
+		m.customerGetter,
+		m.idGen,
+		m.tokenGen,
+		m.tokenHasher,
+		m.tokenStorer,
+		m.tokenConsumer,
+		m.tokenInvalidator,
+		m.verifiedMarker,
+		m.mailer,
+	)
+
+	return uc, m
+}
+
+func TestEmailVerificationUseCase_SendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customer := &domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com"}
+
+	t.Run("stores a new token and emails the link", func(t *testing.T) {
+		uc, m := newEmailVerificationUseCase(ctrl, true)
+
+		gomock.InOrder(
+			m.tokenGen.EXPECT().Generate().Return("verify_token", nil),
+			m.tokenHasher.EXPECT().Hash("verify_token").Return("verify_hash", nil),
+			m.idGen.EXPECT().Generate().Return("token_123", nil),
+			m.tokenInvalidator.EXPECT().
+				InvalidateForCustomer(gomock.Any(), "customer_123", domain.OneTimeTokenEmailVerification, gomock.Any()).
+				Return(nil),
+			m.tokenStorer.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, token *domain.OneTimeToken) error {
+					assert.Equal(t, "token_123", token.ID)
+					assert.Equal(t, domain.OneTimeTokenEmailVerification, token.Purpose)
+					assert.Equal(t, "verify_hash", token.TokenHash)
+					assert.WithinDuration(t, token.CreatedAt.Add(48*time.Hour), token.ExpiresAt, time.Second)
+					return nil
+				}),
+			m.mailer.EXPECT().
+				Send(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, msg domain.MailMessage) error {
+					assert.Equal(t, "john@example.com", msg.To)
+					assert.Contains(t, msg.Body, "https://wishlist.local/api/auth/verify?token=verify_token")
+					return nil
+				}),
+		)
+
+		assert.NoError(t, uc.SendVerification(context.Background(), customer))
+	})
+
+	t.Run("does nothing when verification is disabled", func(t *testing.T) {
+		uc, _ := newEmailVerificationUseCase(ctrl, false)
+
+		assert.NoError(t, uc.SendVerification(context.Background(), customer))
+	})
+
+	t.Run("does nothing when already verified", func(t *testing.T) {
+		uc, _ := newEmailVerificationUseCase(ctrl, true)
+
+		verified := *customer
+		verified.VerifiedAt = time.Now()
+		assert.NoError(t, uc.SendVerification(context.Background(), &verified))
+	})
+
+	t.Run("returns the mailer error", func(t *testing.T) {
+		uc, m := newEmailVerificationUseCase(ctrl, true)
+
+		m.tokenGen.EXPECT().Generate().Return("verify_token", nil)
+		m.tokenHasher.EXPECT().Hash("verify_token").Return("verify_hash", nil)
+		m.idGen.EXPECT().Generate().Return("token_123", nil)
+		m.tokenInvalidator.EXPECT().InvalidateForCustomer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		m.tokenStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+		assert.EqualError(t, uc.SendVerification(context.Background(), customer), "smtp down")
+	})
+}
+
+func TestEmailVerificationUseCase_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name          string
+		token         string
+		setupMocks    func(m emailVerificationMocks)
+		expectedError error
+	}{
+		{
+			name:  "marks the customer as verified",
+			token: "verify_token",
+			setupMocks: func(m emailVerificationMocks) {
+				m.tokenHasher.EXPECT().Hash("verify_token").Return("verify_hash", nil)
+				m.tokenConsumer.EXPECT().
+					Consume(gomock.Any(), domain.OneTimeTokenEmailVerification, "verify_hash", gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_123"}, nil)
+				m.verifiedMarker.EXPECT().MarkVerified(gomock.Any(), "customer_123", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:          "missing token",
+			token:         "",
+			setupMocks:    func(m emailVerificationMocks) {},
+			expectedError: e.NewRequiredFieldError("token"),
+		},
+		{
+			name:  "used or expired token",
+			token: "verify_token",
+			setupMocks: func(m emailVerificationMocks) {
+				m.tokenHasher.EXPECT().Hash("verify_token").Return("verify_hash", nil)
+				m.tokenConsumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedError: &e.ValidationError{Field: "token", Err: "is invalid or expired"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newEmailVerificationUseCase(ctrl, true)
+			tt.setupMocks(m)
+
+			err := uc.VerifyEmail(context.Background(), tt.token)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmailVerificationUseCase_ResendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("already verified customer", func(t *testing.T) {
+		uc, m := newEmailVerificationUseCase(ctrl, true)
+
+		m.customerGetter.EXPECT().
+			GetByID(gomock.Any(), "customer_123").
+			Return(&domain.Customer{ID: "customer_123", VerifiedAt: time.Now()}, nil)
+
+		err := uc.ResendVerification(context.Background(), "customer_123")
+		assert.EqualError(t, err, (&e.ValidationError{Field: "email", Err: "is already verified"}).Error())
+	})
+
+	t.Run("customer not found", func(t *testing.T) {
+		uc, m := newEmailVerificationUseCase(ctrl, true)
+
+		m.customerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+
+		err := uc.ResendVerification(context.Background(), "customer_123")
+		assert.True(t, e.IsNotFoundError(err))
+	})
+}
+
+func TestEmailVerificationUseCase_EnsureVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	required, _ := newEmailVerificationUseCase(ctrl, true)
+	disabled, _ := newEmailVerificationUseCase(ctrl, false)
+
+	assert.NoError(t, required.EnsureVerified(&domain.Customer{VerifiedAt: time.Now()}))
+	assert.True(t, e.IsForbiddenError(required.EnsureVerified(&domain.Customer{})))
+	assert.NoError(t, disabled.EnsureVerified(&domain.Customer{}))
+}
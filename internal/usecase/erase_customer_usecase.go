@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+// EraseCustomerUseCase implements the right to be forgotten: the customer row is kept as an
+// anonymous tombstone, everything else that identifies the customer is removed
+type EraseCustomerUseCase struct {
+	retention      domain.WishlistRetention
+	idGen          domain.IDGenerator
+	signer         domain.Signer
+	customerGetter domain.GetCustomerByIDRepository
+	deletedGetter  domain.GetDeletedCustomerRepository
+	wishlistLister domain.WishlistByCustomerIdRepository
+	receiptGetter  domain.GetErasureReceiptRepository
+	eraser         domain.EraseCustomerRepository
+	revoker        domain.TokenRevoker
+	evicters       []domain.CustomerCacheEvicter
+	accessPolicy   domain.Policy
+}
+
+func NewEraseCustomerUseCase(
+	retention domain.WishlistRetention,
+	idGen domain.IDGenerator,
+	signer domain.Signer,
+	customerGetter domain.GetCustomerByIDRepository,
+	deletedGetter domain.GetDeletedCustomerRepository,
+	wishlistLister domain.WishlistByCustomerIdRepository,
+	receiptGetter domain.GetErasureReceiptRepository,
+	eraser domain.EraseCustomerRepository,
+	revoker domain.TokenRevoker,
+	evicters []domain.CustomerCacheEvicter,
+	accessPolicy domain.Policy,
+) *EraseCustomerUseCase {
+	return &EraseCustomerUseCase{
+		retention:      retention,
+		idGen:          idGen,
+		signer:         signer,
+		customerGetter: customerGetter,
+		deletedGetter:  deletedGetter,
+		wishlistLister: wishlistLister,
+		receiptGetter:  receiptGetter,
+		eraser:         eraser,
+		revoker:        revoker,
+		evicters:       evicters,
+		accessPolicy:   accessPolicy,
+	}
+}
+
+// EraseCustomer works on active and soft deleted customers. Tokens and caches are cleared before
+// the database so a failed erasure can simply be retried, once the receipt exists it is returned as is
+func (u *EraseCustomerUseCase) EraseCustomer(ctx context.Context, currentCustomerID string, customerID string) (*domain.ErasureReceipt, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerWrite, customerID); err != nil {
+		return nil, err
+	}
+
+	receipt, err := u.receiptGetter.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt != nil {
+		return receipt, nil
+	}
+
+	customer, err := u.getCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer == nil {
+		return nil, e.NewNotFoundError("customer")
+	}
+
+	wishlists, err := u.wishlistLister.GetByCustomerId(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.revoker.RevokeAllForCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	for _, evicter := range u.evicters {
+		if err := evicter.EvictCustomer(ctx, customer); err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate erasure receipt ID"))
+	}
+
+	receipt = &domain.ErasureReceipt{
+		ID:                id,
+		CustomerID:        customerID,
+		RequestedBy:       currentCustomerID,
+		ErasedAt:          time.Now().UTC().Truncate(time.Second),
+		WishlistRetention: u.retention,
+		Wishlists:         len(wishlists),
+	}
+	receipt.Signature = u.signer.Sign(receipt.Payload())
+
+	if err := u.eraser.Erase(ctx, receipt); err != nil {
+		if !e.IsConflictError(err) {
+			return nil, err
+		}
+
+		// a concurrent erasure won, its receipt is the one that counts
+		return u.erasedReceipt(ctx, customerID)
+	}
+
+	return receipt, nil
+}
+
+// VerifyErasureReceipt checks the signature and that the receipt matches the stored one, so a
+// receipt is only valid for an erasure that was actually committed
+func (u *EraseCustomerUseCase) VerifyErasureReceipt(ctx context.Context, receipt *domain.ErasureReceipt) (bool, error) {
+	if !u.signer.Verify(receipt.Payload(), receipt.Signature) {
+		return false, nil
+	}
+
+	stored, err := u.receiptGetter.GetByCustomerID(ctx, receipt.CustomerID)
+	if err != nil {
+		return false, err
+	}
+
+	return stored != nil && stored.ID == receipt.ID, nil
+}
+
+func (u *EraseCustomerUseCase) erasedReceipt(ctx context.Context, customerID string) (*domain.ErasureReceipt, error) {
+	receipt, err := u.receiptGetter.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the customer was purged in the meantime without being erased
+	if receipt == nil {
+		return nil, e.NewNotFoundError("customer")
+	}
+
+	return receipt, nil
+}
+
+func (u *EraseCustomerUseCase) getCustomer(ctx context.Context, customerID string) (*domain.Customer, error) {
+	customer, err := u.customerGetter.GetByID(ctx, customerID)
+	if err != nil || customer != nil {
+		return customer, err
+	}
+
+	// a zero deletedSince also finds customers whose grace period is over but who were not purged yet
+	return u.deletedGetter.GetDeletedByID(ctx, customerID, time.Time{})
+}
@@ -0,0 +1,254 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEraseCustomerUseCase_EraseCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockSigner := mocks.NewMockSigner(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockDeletedGetter := mocks.NewMockGetDeletedCustomerRepository(ctrl)
+	mockWishlistLister := mocks.NewMockWishlistByCustomerIdRepository(ctrl)
+	mockReceiptGetter := mocks.NewMockGetErasureReceiptRepository(ctrl)
+	mockEraser := mocks.NewMockEraseCustomerRepository(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+	mockEvicter := mocks.NewMockCustomerCacheEvicter(ctrl)
+
+	customer := &domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com"}
+	stored := &domain.ErasureReceipt{ID: "receipt_123", CustomerID: "customer_123", Signature: "signature"}
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		setupMocks        func()
+		expected          *domain.ErasureReceipt
+		expectedError     error
+	}{
+		{
+			name:              "erases the customer",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockWishlistLister.EXPECT().GetByCustomerId(gomock.Any(), "customer_123").Return([]*domain.Wishlist{{ID: "wishlist_1"}, {ID: "wishlist_2"}}, nil)
+				mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil)
+				mockEvicter.EXPECT().EvictCustomer(gomock.Any(), customer).Return(nil)
+				mockIDGen.EXPECT().Generate().Return("receipt_123", nil)
+				mockSigner.EXPECT().Sign(gomock.Any()).Return("signature")
+				mockEraser.EXPECT().
+					Erase(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, receipt *domain.ErasureReceipt) error {
+						assert.Equal(t, domain.WishlistRetentionDetach, receipt.WishlistRetention)
+						assert.Equal(t, 2, receipt.Wishlists)
+						assert.Equal(t, "customer_123", receipt.RequestedBy)
+						assert.WithinDuration(t, time.Now(), receipt.ErasedAt, 2*time.Second)
+						return nil
+					})
+			},
+			expected: &domain.ErasureReceipt{
+				ID:                "receipt_123",
+				CustomerID:        "customer_123",
+				RequestedBy:       "customer_123",
+				WishlistRetention: domain.WishlistRetentionDetach,
+				Wishlists:         2,
+				Signature:         "signature",
+			},
+		},
+		{
+			name:              "already erased",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(stored, nil)
+			},
+			expected: stored,
+		},
+		{
+			name:              "soft deleted customer",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "customer_123", Email: "john@example.com", DeletedAt: time.Now()}
+
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockDeletedGetter.EXPECT().GetDeletedByID(gomock.Any(), "customer_123", time.Time{}).Return(deleted, nil)
+				mockWishlistLister.EXPECT().GetByCustomerId(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil)
+				mockEvicter.EXPECT().EvictCustomer(gomock.Any(), deleted).Return(nil)
+				mockIDGen.EXPECT().Generate().Return("receipt_123", nil)
+				mockSigner.EXPECT().Sign(gomock.Any()).Return("signature")
+				mockEraser.EXPECT().Erase(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: &domain.ErasureReceipt{
+				ID:                "receipt_123",
+				CustomerID:        "customer_123",
+				RequestedBy:       "customer_123",
+				WishlistRetention: domain.WishlistRetentionDetach,
+				Signature:         "signature",
+			},
+		},
+		{
+			name:              "customer not found",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockDeletedGetter.EXPECT().GetDeletedByID(gomock.Any(), "customer_123", time.Time{}).Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("customer"),
+		},
+		{
+			name:              "concurrent erasure returns the stored receipt",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockWishlistLister.EXPECT().GetByCustomerId(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil)
+				mockEvicter.EXPECT().EvictCustomer(gomock.Any(), customer).Return(nil)
+				mockIDGen.EXPECT().Generate().Return("receipt_456", nil)
+				mockSigner.EXPECT().Sign(gomock.Any()).Return("other_signature")
+				mockEraser.EXPECT().Erase(gomock.Any(), gomock.Any()).Return(e.NewConflictError("customer is already erased"))
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(stored, nil)
+			},
+			expected: stored,
+		},
+		{
+			name:              "another customer",
+			currentCustomerID: "other_123",
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+		{
+			name:              "cache eviction fails before anything is erased",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockWishlistLister.EXPECT().GetByCustomerId(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil)
+				mockEvicter.EXPECT().EvictCustomer(gomock.Any(), customer).Return(errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewEraseCustomerUseCase(
+				domain.WishlistRetentionDetach,
+				mockIDGen,
+				mockSigner,
+				mockCustomerGetter,
+				mockDeletedGetter,
+				mockWishlistLister,
+				mockReceiptGetter,
+				mockEraser,
+				mockRevoker,
+				[]domain.CustomerCacheEvicter{mockEvicter},
+				ownerOnlyPolicy(ctrl),
+			)
+
+			result, err := uc.EraseCustomer(context.Background(), tt.currentCustomerID, "customer_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			if result != nil && tt.expected != nil {
+				tt.expected.ErasedAt = result.ErasedAt
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEraseCustomerUseCase_VerifyErasureReceipt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockSigner := mocks.NewMockSigner(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockDeletedGetter := mocks.NewMockGetDeletedCustomerRepository(ctrl)
+	mockWishlistLister := mocks.NewMockWishlistByCustomerIdRepository(ctrl)
+	mockReceiptGetter := mocks.NewMockGetErasureReceiptRepository(ctrl)
+	mockEraser := mocks.NewMockEraseCustomerRepository(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+	mockEvicter := mocks.NewMockCustomerCacheEvicter(ctrl)
+
+	receipt := &domain.ErasureReceipt{ID: "receipt_123", CustomerID: "customer_123", Signature: "signature"}
+
+	tests := []struct {
+		name       string
+		setupMocks func()
+		expected   bool
+	}{
+		{
+			name: "genuine receipt",
+			setupMocks: func() {
+				mockSigner.EXPECT().Verify(receipt.Payload(), "signature").Return(true)
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(receipt, nil)
+			},
+			expected: true,
+		},
+		{
+			name: "tampered receipt",
+			setupMocks: func() {
+				mockSigner.EXPECT().Verify(receipt.Payload(), "signature").Return(false)
+			},
+			expected: false,
+		},
+		{
+			name: "receipt that was never stored",
+			setupMocks: func() {
+				mockSigner.EXPECT().Verify(receipt.Payload(), "signature").Return(true)
+				mockReceiptGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewEraseCustomerUseCase(
+				domain.WishlistRetentionDetach,
+				mockIDGen,
+				mockSigner,
+				mockCustomerGetter,
+				mockDeletedGetter,
+				mockWishlistLister,
+				mockReceiptGetter,
+				mockEraser,
+				mockRevoker,
+				[]domain.CustomerCacheEvicter{mockEvicter},
+				ownerOnlyPolicy(ctrl),
+			)
+
+			valid, err := uc.VerifyErasureReceipt(context.Background(), receipt)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, valid)
+		})
+	}
+}
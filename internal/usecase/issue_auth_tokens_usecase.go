@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+// sessionUserAgentMaxLen is the size of the sessions.user_agent column, clients control the header
+const sessionUserAgentMaxLen = 512
+
+type IssueAuthTokensUseCase struct {
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+	signer        domain.TokenSigner
+	idGen         domain.IDGenerator
+	tokenGen      domain.TokenGenerator
+	tokenHasher   domain.Hasher
+	refreshStorer domain.RefreshTokenCreationRepository
+	sessionStorer domain.SessionCreationRepository
+	sessionTouch  domain.TouchSessionRepository
+}
+
+func NewIssueAuthTokensUseCase(
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+	signer domain.TokenSigner,
+	idGen domain.IDGenerator,
+	tokenGen domain.TokenGenerator,
+	tokenHasher domain.Hasher,
+	refreshStorer domain.RefreshTokenCreationRepository,
+	sessionStorer domain.SessionCreationRepository,
+	sessionTouch domain.TouchSessionRepository,
+) *IssueAuthTokensUseCase {
+	return &IssueAuthTokensUseCase{
+		accessTTL:     accessTTL,
+		refreshTTL:    refreshTTL,
+		signer:        signer,
+		idGen:         idGen,
+		tokenGen:      tokenGen,
+		tokenHasher:   tokenHasher,
+		refreshStorer: refreshStorer,
+		sessionStorer: sessionStorer,
+		sessionTouch:  sessionTouch,
+	}
+}
+
+func (u *IssueAuthTokensUseCase) Issue(ctx context.Context, customer *domain.Customer, familyID string, client domain.ClientInfo) (*domain.AuthTokens, error) {
+	var err error
+	now := time.Now()
+	newSession := familyID == ""
+
+	if newSession {
+		familyID, err = u.idGen.Generate()
+		if err != nil {
+			return nil, errors.Join(err, errors.New("failed to generate token family ID"))
+		}
+	}
+
+	tokenID, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate token ID"))
+	}
+
+	outgoing := &domain.OutgoingCustomer{
+		ID:    customer.ID,
+		Name:  customer.Name,
+		Email: customer.Email,
+		Role:  customer.Role,
+	}
+	data, _ := json.Marshal(outgoing)
+
+	expiresAt := now.Add(u.accessTTL)
+	accessToken, err := u.signer.Sign(domain.TokenClaims{
+		ID:        tokenID,
+		Subject:   customer.ID,
+		SessionID: familyID,
+		Role:      customer.Role,
+		Data:      string(data),
+		IssuedAt:  now,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := u.tokenGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate refresh token"))
+	}
+
+	refreshHash, err := u.tokenHasher.Hash(refreshToken)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to hash refresh token"))
+	}
+
+	refreshID, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate refresh token ID"))
+	}
+
+	err = u.refreshStorer.Create(ctx, &domain.RefreshToken{
+		ID:         refreshID,
+		CustomerID: customer.ID,
+		FamilyID:   familyID,
+		TokenHash:  refreshHash,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(u.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if newSession {
+		err = u.sessionStorer.Create(ctx, &domain.Session{
+			ID:         familyID,
+			CustomerID: customer.ID,
+			TokenID:    tokenID,
+			UserAgent:  truncateRunes(client.UserAgent, sessionUserAgentMaxLen),
+			IP:         client.IP,
+			CreatedAt:  now,
+			LastSeenAt: now,
+		})
+	} else {
+		err = u.sessionTouch.Touch(ctx, familyID, tokenID, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// truncateRunes cuts value to max characters without splitting a multi-byte one
+func truncateRunes(value string, max int) string {
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+
+	return string([]rune(value)[:max])
+}
@@ -0,0 +1,187 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIssueAuthTokensUseCase_Issue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSigner := mocks.NewMockTokenSigner(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockHasher := mocks.NewMockHasher(ctrl)
+	mockStorer := mocks.NewMockRefreshTokenCreationRepository(ctrl)
+	mockSessionStorer := mocks.NewMockSessionCreationRepository(ctrl)
+	mockSessionTouch := mocks.NewMockTouchSessionRepository(ctrl)
+	client := domain.ClientInfo{IP: "10.0.0.1", UserAgent: "curl/8.0"}
+
+	customer := &domain.Customer{
+		ID:    "customer_123",
+		Name:  "Test User",
+		Email: "test@example.com",
+		Role:  domain.RoleSupport,
+	}
+	expectedData, _ := json.Marshal(&domain.OutgoingCustomer{
+		ID:    customer.ID,
+		Name:  customer.Name,
+		Email: customer.Email,
+		Role:  domain.RoleSupport,
+	})
+
+	tests := []struct {
+		name          string
+		familyID      string
+		client        domain.ClientInfo
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:     "new token family",
+			familyID: "",
+			client:   client,
+			setupMocks: func() {
+				gomock.InOrder(
+					mockIDGen.EXPECT().Generate().Return("family_123", nil),
+					mockIDGen.EXPECT().Generate().Return("jti_123", nil),
+				)
+				mockSigner.EXPECT().
+					Sign(gomock.Any()).
+					DoAndReturn(func(claims domain.TokenClaims) (string, error) {
+						assert.Equal(t, "jti_123", claims.ID)
+						assert.Equal(t, "customer_123", claims.Subject)
+						assert.Equal(t, "family_123", claims.SessionID)
+						assert.Equal(t, domain.RoleSupport, claims.Role)
+						assert.Equal(t, string(expectedData), claims.Data)
+						assert.WithinDuration(t, claims.IssuedAt.Add(15*time.Minute), claims.ExpiresAt, time.Second)
+						return "access.jwt.token", nil
+					})
+				mockTokenGen.EXPECT().Generate().Return("refresh_token", nil)
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("refresh_123", nil)
+				mockStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, token *domain.RefreshToken) error {
+						assert.Equal(t, "refresh_123", token.ID)
+						assert.Equal(t, "customer_123", token.CustomerID)
+						assert.Equal(t, "family_123", token.FamilyID)
+						assert.Equal(t, "refresh_hash", token.TokenHash)
+						assert.WithinDuration(t, token.CreatedAt.Add(24*time.Hour), token.ExpiresAt, time.Second)
+						return nil
+					})
+				mockSessionStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, session *domain.Session) error {
+						assert.Equal(t, "family_123", session.ID)
+						assert.Equal(t, "customer_123", session.CustomerID)
+						assert.Equal(t, "jti_123", session.TokenID)
+						assert.Equal(t, "10.0.0.1", session.IP)
+						assert.Equal(t, "curl/8.0", session.UserAgent)
+						assert.Equal(t, session.CreatedAt, session.LastSeenAt)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "long user agents are truncated to the column size",
+			familyID: "",
+			client:   domain.ClientInfo{IP: "10.0.0.1", UserAgent: strings.Repeat("é", 600)},
+			setupMocks: func() {
+				mockIDGen.EXPECT().Generate().Return("family_123", nil)
+				mockIDGen.EXPECT().Generate().Return("jti_123", nil)
+				mockSigner.EXPECT().Sign(gomock.Any()).Return("access.jwt.token", nil)
+				mockTokenGen.EXPECT().Generate().Return("refresh_token", nil)
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("refresh_123", nil)
+				mockStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockSessionStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, session *domain.Session) error {
+						assert.Equal(t, strings.Repeat("é", 512), session.UserAgent)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "rotation keeps the family",
+			familyID: "family_456",
+			client:   client,
+			setupMocks: func() {
+				mockIDGen.EXPECT().Generate().Return("jti_456", nil)
+				mockSigner.EXPECT().
+					Sign(gomock.Any()).
+					DoAndReturn(func(claims domain.TokenClaims) (string, error) {
+						assert.Equal(t, "family_456", claims.SessionID)
+						return "access.jwt.token", nil
+					})
+				mockTokenGen.EXPECT().Generate().Return("refresh_token", nil)
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("refresh_456", nil)
+				mockStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, token *domain.RefreshToken) error {
+						assert.Equal(t, "family_456", token.FamilyID)
+						return nil
+					})
+				mockSessionTouch.EXPECT().Touch(gomock.Any(), "family_456", "jti_456", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "signing error",
+			familyID: "family_123",
+			client:   client,
+			setupMocks: func() {
+				mockIDGen.EXPECT().Generate().Return("jti_123", nil)
+				mockSigner.EXPECT().Sign(gomock.Any()).Return("", errors.New("signing failed"))
+			},
+			expectedError: errors.New("signing failed"),
+		},
+		{
+			name:     "refresh token storage error",
+			familyID: "family_123",
+			client:   client,
+			setupMocks: func() {
+				mockIDGen.EXPECT().Generate().Return("jti_123", nil)
+				mockSigner.EXPECT().Sign(gomock.Any()).Return("access.jwt.token", nil)
+				mockTokenGen.EXPECT().Generate().Return("refresh_token", nil)
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("refresh_123", nil)
+				mockStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewIssueAuthTokensUseCase(15*time.Minute, 24*time.Hour, mockSigner, mockIDGen, mockTokenGen, mockHasher, mockStorer, mockSessionStorer, mockSessionTouch)
+			tokens, err := uc.Issue(context.Background(), customer, tt.familyID, tt.client)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+				assert.Nil(t, tokens)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "access.jwt.token", tokens.AccessToken)
+			assert.Equal(t, "refresh_token", tokens.RefreshToken)
+			assert.WithinDuration(t, time.Now().Add(15*time.Minute), tokens.ExpiresAt, time.Second)
+		})
+	}
+}
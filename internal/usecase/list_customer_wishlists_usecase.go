@@ -12,23 +12,26 @@ type listCustomerWishlistsUseCase struct {
 	customerRepo  domain.GetCustomerByIDRepository
 	wishlistRepo  domain.WishlistByCustomerIdRepository
 	productGetter domain.GetProductUseCase
+	accessPolicy  domain.Policy
 }
 
 func NewListCustomerWishlistsUseCase(
 	customerRepo domain.GetCustomerByIDRepository,
 	wishlistRepo domain.WishlistByCustomerIdRepository,
 	productGetter domain.GetProductUseCase,
+	accessPolicy domain.Policy,
 ) *listCustomerWishlistsUseCase {
 	return &listCustomerWishlistsUseCase{
 		customerRepo:  customerRepo,
 		wishlistRepo:  wishlistRepo,
 		productGetter: productGetter,
+		accessPolicy:  accessPolicy,
 	}
 }
 
 func (u *listCustomerWishlistsUseCase) Execute(ctx context.Context, currentCustomerId string, customerId string) (*[]domain.FullfilledWishlist, error) {
-	if currentCustomerId != customerId {
-		return nil, e.NewUnauthorizedError()
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerId, domain.PermissionWishlistRead, customerId); err != nil {
+		return nil, err
 	}
 
 	customer, err := u.customerRepo.GetByID(ctx, customerId)
@@ -104,7 +107,7 @@ func (u *listCustomerWishlistsUseCase) fillWishlistWithProducts(ctx context.Cont
 			CreatedAt: customer.CreatedAt,
 		},
 		Title: wishlist.Title,
-		Items: make([]domain.Product, len(wishlist.Items)),
+		Items: make([]domain.FullfilledWishlistItem, len(wishlist.Items)),
 	}
 
 	var wg sync.WaitGroup
@@ -112,7 +115,7 @@ func (u *listCustomerWishlistsUseCase) fillWishlistWithProducts(ctx context.Cont
 
 	for i, item := range wishlist.Items {
 		wg.Add(1)
-		go func(i int, productID string) {
+		go func(i int, item domain.WishlistItem) {
 			defer wg.Done()
 
 			select {
@@ -120,13 +123,13 @@ func (u *listCustomerWishlistsUseCase) fillWishlistWithProducts(ctx context.Cont
 				errChan <- ctx.Err()
 				return
 			default:
-				product, err := u.productGetter.Execute(ctx, productID)
+				product, err := u.productGetter.Execute(ctx, item.ProductID)
 				if err != nil {
 					errChan <- err
 					return
 				}
 
-				filledList.Items[i] = *product
+				filledList.Items[i] = domain.FullfilledWishlistItem{WishlistItem: item, Product: *product}
 			}
 		}(i, item)
 	}
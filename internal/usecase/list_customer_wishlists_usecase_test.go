@@ -31,12 +31,12 @@ func TestListCustomerWishlistsUseCase(t *testing.T) {
 		{
 			ID:    "wishlist1",
 			Title: "Wishlist 1",
-			Items: []string{"product1", "product2"},
+			Items: []domain.WishlistItem{{ProductID: "product1"}, {ProductID: "product2"}},
 		},
 		{
 			ID:    "wishlist2",
 			Title: "Wishlist 2",
-			Items: []string{"product3"},
+			Items: []domain.WishlistItem{{ProductID: "product3"}},
 		},
 	}
 
@@ -178,6 +178,7 @@ func TestListCustomerWishlistsUseCase(t *testing.T) {
 				customerRepoMock,
 				wishlistRepoMock,
 				productGetterMock,
+				ownerOnlyPolicy(ctrl),
 			)
 
 			result, err := sut.Execute(context.Background(), tt.currentCustomerId, tt.customerId)
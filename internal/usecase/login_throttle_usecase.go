@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+const (
+	loginFailuresCacheKey = "login_failures::%s::%s"
+	loginBlockedCacheKey  = "login_blocked_until::%s::%s"
+)
+
+type LoginThrottleConfig struct {
+	// FreeAttempts is how many failures are allowed before any delay is applied
+	FreeAttempts int64
+	// BaseDelay is the first delay, it doubles after every further failure
+	BaseDelay time.Duration
+	// LockoutThreshold is the number of failures per email that locks the account for LockoutDuration
+	LockoutThreshold int64
+	// IPLockoutThreshold is the number of failures per client IP, across every email, that blocks the IP
+	IPLockoutThreshold int64
+	LockoutDuration    time.Duration
+	// FailureWindow is how long failures are remembered after the last one
+	FailureWindow time.Duration
+}
+
+type LoginThrottleUseCase struct {
+	config LoginThrottleConfig
+	cache  domain.Cache
+}
+
+func NewLoginThrottleUseCase(config LoginThrottleConfig, cache domain.Cache) *LoginThrottleUseCase {
+	return &LoginThrottleUseCase{
+		config: config,
+		cache:  cache,
+	}
+}
+
+func (u *LoginThrottleUseCase) Check(ctx context.Context, email string, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, subject := range u.subjects(email, ip) {
+		value, err := u.cache.Get(ctx, fmt.Sprintf(loginBlockedCacheKey, subject[0], subject[1]))
+		if err != nil {
+			return err
+		}
+
+		if value == "" {
+			continue
+		}
+
+		until, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if wait := time.Unix(until, 0).Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return e.NewTooManyRequestsError(retryAfter)
+	}
+
+	return nil
+}
+
+// RegisterFailure counts the failure and blocks the email and the IP for a delay that doubles
+// after every failure past the free attempts, reaching a threshold locks them for LockoutDuration
+func (u *LoginThrottleUseCase) RegisterFailure(ctx context.Context, email string, ip string) error {
+	for _, subject := range u.subjects(email, ip) {
+		kind, value := subject[0], subject[1]
+
+		failures, err := u.cache.Increment(ctx, fmt.Sprintf(loginFailuresCacheKey, kind, value), u.config.FailureWindow)
+		if err != nil {
+			return err
+		}
+
+		threshold := u.config.LockoutThreshold
+		if kind == "ip" {
+			threshold = u.config.IPLockoutThreshold
+		}
+
+		delay := u.delay(failures, threshold)
+		if delay <= 0 {
+			continue
+		}
+
+		if failures >= threshold {
+			fmt.Printf("[login_throttle_usecase] WARN %s %s locked for %s after %d failed logins\n", kind, value, delay, failures)
+		}
+
+		until := time.Now().Add(delay).Unix()
+		if err := u.cache.Set(ctx, fmt.Sprintf(loginBlockedCacheKey, kind, value), strconv.FormatInt(until, 10), delay); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterSuccess forgets the failures of the email, the IP counter is kept since
+// logging into an own account must not reset the attempts made against others
+func (u *LoginThrottleUseCase) RegisterSuccess(ctx context.Context, email string) error {
+	return u.Unlock(ctx, email)
+}
+
+func (u *LoginThrottleUseCase) Unlock(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+
+	return u.cache.Delete(
+		ctx,
+		fmt.Sprintf(loginFailuresCacheKey, "email", email),
+		fmt.Sprintf(loginBlockedCacheKey, "email", email),
+	)
+}
+
+// EvictCustomer forgets the failures of the customer email so it does not outlive an erasure
+func (u *LoginThrottleUseCase) EvictCustomer(ctx context.Context, customer *domain.Customer) error {
+	return u.Unlock(ctx, customer.Email)
+}
+
+func (u *LoginThrottleUseCase) delay(failures int64, threshold int64) time.Duration {
+	if failures >= threshold {
+		return u.config.LockoutDuration
+	}
+
+	if failures <= u.config.FreeAttempts {
+		return 0
+	}
+
+	delay := u.config.BaseDelay
+	for i := u.config.FreeAttempts + 1; i < failures && delay < u.config.LockoutDuration; i++ {
+		delay *= 2
+	}
+
+	if delay > u.config.LockoutDuration {
+		return u.config.LockoutDuration
+	}
+
+	return delay
+}
+
+func (u *LoginThrottleUseCase) subjects(email string, ip string) [][2]string {
+	subjects := [][2]string{{"email", normalizeEmail(email)}}
+	if ip != "" {
+		subjects = append(subjects, [2]string{"ip", ip})
+	}
+
+	return subjects
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,158 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+var loginThrottleConfig = usecase.LoginThrottleConfig{
+	FreeAttempts:       3,
+	BaseDelay:          time.Second,
+	LockoutThreshold:   10,
+	IPLockoutThreshold: 100,
+	LockoutDuration:    15 * time.Minute,
+	FailureWindow:      time.Hour,
+}
+
+func TestLoginThrottleUseCase_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	future := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name          string
+		ip            string
+		setupMocks    func()
+		expectBlocked bool
+		expectedError error
+	}{
+		{
+			name: "nothing blocked",
+			ip:   "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::email::john@example.com").Return("", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::ip::10.0.0.1").Return("", nil)
+			},
+		},
+		{
+			name: "email blocked",
+			ip:   "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::email::john@example.com").Return(future, nil)
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::ip::10.0.0.1").Return("", nil)
+			},
+			expectBlocked: true,
+		},
+		{
+			name: "ip blocked",
+			ip:   "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::email::john@example.com").Return("", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::ip::10.0.0.1").Return(future, nil)
+			},
+			expectBlocked: true,
+		},
+		{
+			name: "unknown ip only checks the email",
+			ip:   "",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::email::john@example.com").Return("", nil)
+			},
+		},
+		{
+			name: "cache error",
+			ip:   "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "login_blocked_until::email::john@example.com").Return("", errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewLoginThrottleUseCase(loginThrottleConfig, mockCache)
+			err := uc.Check(context.Background(), " John@Example.com", tt.ip)
+
+			switch {
+			case tt.expectBlocked:
+				assert.True(t, e.IsTooManyRequestsError(err))
+				var tooMany *e.TooManyRequestsError
+				assert.True(t, errors.As(err, &tooMany))
+				assert.True(t, tooMany.RetryAfter > 0 && tooMany.RetryAfter <= time.Minute)
+			case tt.expectedError != nil:
+				assert.EqualError(t, err, tt.expectedError.Error())
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoginThrottleUseCase_RegisterFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+
+	tests := []struct {
+		name          string
+		emailFailures int64
+		ipFailures    int64
+		emailDelay    time.Duration
+		ipDelay       time.Duration
+	}{
+		{name: "free attempt", emailFailures: 3, ipFailures: 3},
+		{name: "first delayed attempt", emailFailures: 4, ipFailures: 4, emailDelay: time.Second, ipDelay: time.Second},
+		{name: "delay doubles", emailFailures: 6, ipFailures: 6, emailDelay: 4 * time.Second, ipDelay: 4 * time.Second},
+		{name: "email locked out", emailFailures: 10, ipFailures: 10, emailDelay: 15 * time.Minute, ipDelay: 64 * time.Second},
+		{name: "delay is capped by the lockout", emailFailures: 1, ipFailures: 40, ipDelay: 15 * time.Minute},
+		{name: "ip locked out", emailFailures: 1, ipFailures: 100, ipDelay: 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache.EXPECT().Increment(gomock.Any(), "login_failures::email::john@example.com", time.Hour).Return(tt.emailFailures, nil)
+			if tt.emailDelay > 0 {
+				mockCache.EXPECT().Set(gomock.Any(), "login_blocked_until::email::john@example.com", gomock.Any(), tt.emailDelay).Return(nil)
+			}
+
+			mockCache.EXPECT().Increment(gomock.Any(), "login_failures::ip::10.0.0.1", time.Hour).Return(tt.ipFailures, nil)
+			if tt.ipDelay > 0 {
+				mockCache.EXPECT().Set(gomock.Any(), "login_blocked_until::ip::10.0.0.1", gomock.Any(), tt.ipDelay).Return(nil)
+			}
+
+			uc := usecase.NewLoginThrottleUseCase(loginThrottleConfig, mockCache)
+			err := uc.RegisterFailure(context.Background(), "john@example.com", "10.0.0.1")
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoginThrottleUseCase_Unlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().
+		Delete(gomock.Any(), "login_failures::email::john@example.com", "login_blocked_until::email::john@example.com").
+		Return(nil)
+
+	uc := usecase.NewLoginThrottleUseCase(loginThrottleConfig, mockCache)
+
+	assert.NoError(t, uc.RegisterSuccess(context.Background(), "John@example.com"))
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+)
+
+// MagicLinkUseCase logs customers in through an emailed link. The link carries a random token whose
+// hash is stored rather than a signed payload, so it can be consumed only once and is invalidated
+// by the next request, which a stateless signature could not do
+type MagicLinkUseCase struct {
+	tokenTTL         time.Duration
+	loginURL         string
+	throttler        domain.OneTimeTokenRequestThrottler
+	runner           domain.BackgroundRunner
+	emailGetter      domain.GetCustomerByEmailRepository
+	customerGetter   domain.GetCustomerByIDRepository
+	idGen            domain.IDGenerator
+	tokenGen         domain.TokenGenerator
+	tokenHasher      domain.Hasher
+	tokenStorer      domain.OneTimeTokenCreationRepository
+	tokenConsumer    domain.ConsumeOneTimeTokenRepository
+	tokenInvalidator domain.InvalidateOneTimeTokensRepository
+	verifiedMarker   domain.MarkCustomerVerifiedRepository
+	restorer         domain.AccountRestorer
+	mailer           domain.Mailer
+	mfa              domain.MFAChallenger
+	issuer           domain.TokenIssuer
+}
+
+func NewMagicLinkUseCase(
+	tokenTTL time.Duration,
+	loginURL string,
+	throttler domain.OneTimeTokenRequestThrottler,
+	runner domain.BackgroundRunner,
+	emailGetter domain.GetCustomerByEmailRepository,
+	customerGetter domain.GetCustomerByIDRepository,
+	idGen domain.IDGenerator,
+	tokenGen domain.TokenGenerator,
+	tokenHasher domain.Hasher,
+	tokenStorer domain.OneTimeTokenCreationRepository,
+	tokenConsumer domain.ConsumeOneTimeTokenRepository,
+	tokenInvalidator domain.InvalidateOneTimeTokensRepository,
+	verifiedMarker domain.MarkCustomerVerifiedRepository,
+	restorer domain.AccountRestorer,
+	mailer domain.Mailer,
+	mfa domain.MFAChallenger,
+	issuer domain.TokenIssuer,
+) *MagicLinkUseCase {
+	return &MagicLinkUseCase{
+		tokenTTL:         tokenTTL,
+		loginURL:         loginURL,
+		throttler:        throttler,
+		runner:           runner,
+		emailGetter:      emailGetter,
+		customerGetter:   customerGetter,
+		idGen:            idGen,
+		tokenGen:         tokenGen,
+		tokenHasher:      tokenHasher,
+		tokenStorer:      tokenStorer,
+		tokenConsumer:    tokenConsumer,
+		tokenInvalidator: tokenInvalidator,
+		verifiedMarker:   verifiedMarker,
+		restorer:         restorer,
+		mailer:           mailer,
+		mfa:              mfa,
+		issuer:           issuer,
+	}
+}
+
+// RequestMagicLink emails a login link to the customer, only the latest link stays valid. Accounts
+// deleted during the grace period get one too and are restored once it is used.
+// Everything past the throttle runs in the background, so neither the response nor its timing
+// tells whether the email is registered
+func (u *MagicLinkUseCase) RequestMagicLink(ctx context.Context, email string, ip string) error {
+	if err := u.throttler.Allow(ctx, domain.OneTimeTokenMagicLink, email, ip); err != nil {
+		return err
+	}
+
+	u.runner.Go(func(ctx context.Context) {
+		if err := u.sendMagicLink(ctx, email); err != nil {
+			fmt.Printf("[magic_link_usecase] ERROR sending magic link: %v\n", err)
+		}
+	})
+
+	return nil
+}
+
+func (u *MagicLinkUseCase) sendMagicLink(ctx context.Context, email string) error {
+	customer, err := u.emailGetter.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		customer, err = u.restorer.GetRestorable(ctx, email)
+		if err != nil {
+			return err
+		}
+	}
+
+	if customer == nil {
+		return nil
+	}
+
+	token, err := u.tokenGen.Generate()
+	if err != nil {
+		return errors.Join(err, errors.New("failed to generate magic link token"))
+	}
+
+	hash, err := u.tokenHasher.Hash(token)
+	if err != nil {
+		return errors.Join(err, errors.New("failed to hash magic link token"))
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return errors.Join(err, errors.New("failed to generate magic link token ID"))
+	}
+
+	now := time.Now()
+	if err := u.tokenInvalidator.InvalidateForCustomer(ctx, customer.ID, domain.OneTimeTokenMagicLink, now); err != nil {
+		return err
+	}
+
+	err = u.tokenStorer.Create(ctx, &domain.OneTimeToken{
+		ID:         id,
+		CustomerID: customer.ID,
+		Purpose:    domain.OneTimeTokenMagicLink,
+		TokenHash:  hash,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(u.tokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, domain.MailMessage{
+		To:      customer.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to log in, it expires in %s and can be used only once:\n\n%s?token=%s\n\nIf you did not ask for it you can ignore this email.",
+			customer.Name,
+			u.tokenTTL,
+			u.loginURL,
+			token,
+		),
+	})
+}
+
+// Authenticate redeems a magic link token, it is consumed even when a second factor is still required
+func (u *MagicLinkUseCase) Authenticate(ctx context.Context, credentials any) (*domain.AuthTokens, error) {
+	magicLink, ok := credentials.(inputs.MagicLinkAuth)
+	if !ok {
+		return nil, &e.ValidationError{
+			Field: "credentials",
+			Err:   "Invalid credentials type",
+		}
+	}
+
+	if magicLink.Token == "" {
+		return nil, e.NewAuthenticationError(domain.AuthMethodMagicLink)
+	}
+
+	tokenHash, err := u.tokenHasher.Hash(magicLink.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	consumed, err := u.tokenConsumer.Consume(ctx, domain.OneTimeTokenMagicLink, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if consumed == nil {
+		return nil, e.NewAuthenticationError(domain.AuthMethodMagicLink)
+	}
+
+	customer, err := u.customerGetter.GetByID(ctx, consumed.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// opening the link proves the customer owns the mailbox, which is enough to restore a deleted
+	// account once the second factor, if any, matched too
+	restoring := false
+	if customer == nil {
+		customer, err = u.restorer.GetRestorableByID(ctx, consumed.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+
+		// the customer may have been purged after the link was sent
+		if customer == nil {
+			return nil, e.NewAuthenticationError(domain.AuthMethodMagicLink)
+		}
+		restoring = true
+	}
+
+	// opening the link proves the customer owns the mailbox, deleted accounts can't be marked until restored
+	if !restoring {
+		if err := u.markVerified(ctx, customer, now); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := u.mfa.Challenge(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending != nil {
+		return pending, nil
+	}
+
+	if restoring {
+		if err := u.restorer.Restore(ctx, customer); err != nil {
+			return nil, err
+		}
+
+		if err := u.markVerified(ctx, customer, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.issuer.Issue(ctx, customer, "", domain.ClientInfo{IP: magicLink.IP, UserAgent: magicLink.UserAgent})
+}
+
+func (u *MagicLinkUseCase) markVerified(ctx context.Context, customer *domain.Customer, now time.Time) error {
+	if !customer.VerifiedAt.IsZero() {
+		return nil
+	}
+
+	if err := u.verifiedMarker.MarkVerified(ctx, customer.ID, now); err != nil {
+		return err
+	}
+	customer.VerifiedAt = now
+
+	return nil
+}
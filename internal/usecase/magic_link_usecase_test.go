@@ -0,0 +1,338 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMagicLinkUseCase_RequestMagicLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEmailGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockThrottler := mocks.NewMockOneTimeTokenRequestThrottler(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockTokenStorer := mocks.NewMockOneTimeTokenCreationRepository(ctrl)
+	mockTokenConsumer := mocks.NewMockConsumeOneTimeTokenRepository(ctrl)
+	mockTokenInvalidator := mocks.NewMockInvalidateOneTimeTokensRepository(ctrl)
+	mockVerifiedMarker := mocks.NewMockMarkCustomerVerifiedRepository(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockMFA := mocks.NewMockMFAChallenger(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	uc := usecase.NewMagicLinkUseCase(
+		15*time.Minute,
+		"https://wishlist.local/magic-link",
+		mockThrottler,
+		inlineRunner(ctrl),
+		mockEmailGetter,
+		mockCustomerGetter,
+		mockIDGen,
+		mockTokenGen,
+		mockTokenHasher,
+		mockTokenStorer,
+		mockTokenConsumer,
+		mockTokenInvalidator,
+		mockVerifiedMarker,
+		mockRestorer,
+		mockMailer,
+		mockMFA,
+		mockIssuer,
+	)
+
+	customer := &domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com"}
+
+	t.Run("stores a new token and emails the link", func(t *testing.T) {
+		gomock.InOrder(
+			mockThrottler.EXPECT().Allow(gomock.Any(), domain.OneTimeTokenMagicLink, "john@example.com", "10.0.0.1").Return(nil),
+			mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(customer, nil),
+			mockTokenGen.EXPECT().Generate().Return("magic_token", nil),
+			mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil),
+			mockIDGen.EXPECT().Generate().Return("token_123", nil),
+			mockTokenInvalidator.EXPECT().
+				InvalidateForCustomer(gomock.Any(), "customer_123", domain.OneTimeTokenMagicLink, gomock.Any()).
+				Return(nil),
+			mockTokenStorer.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, token *domain.OneTimeToken) error {
+					assert.Equal(t, "token_123", token.ID)
+					assert.Equal(t, domain.OneTimeTokenMagicLink, token.Purpose)
+					assert.Equal(t, "magic_hash", token.TokenHash)
+					assert.WithinDuration(t, token.CreatedAt.Add(15*time.Minute), token.ExpiresAt, time.Second)
+					return nil
+				}),
+			mockMailer.EXPECT().
+				Send(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, msg domain.MailMessage) error {
+					assert.Equal(t, "john@example.com", msg.To)
+					assert.Contains(t, msg.Body, "https://wishlist.local/magic-link?token=magic_token")
+					return nil
+				}),
+		)
+
+		assert.NoError(t, uc.RequestMagicLink(context.Background(), "john@example.com", "10.0.0.1"))
+	})
+
+	t.Run("deleted account inside the grace period", func(t *testing.T) {
+		deleted := &domain.Customer{ID: "customer_456", Email: "deleted@example.com", DeletedAt: time.Now()}
+
+		mockThrottler.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "deleted@example.com").Return(nil, nil)
+		mockRestorer.EXPECT().GetRestorable(gomock.Any(), "deleted@example.com").Return(deleted, nil)
+		mockTokenGen.EXPECT().Generate().Return("magic_token", nil)
+		mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+		mockIDGen.EXPECT().Generate().Return("token_123", nil)
+		mockTokenInvalidator.EXPECT().InvalidateForCustomer(gomock.Any(), "customer_456", gomock.Any(), gomock.Any()).Return(nil)
+		mockTokenStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.NoError(t, uc.RequestMagicLink(context.Background(), "deleted@example.com", "10.0.0.1"))
+	})
+
+	t.Run("unknown email behaves like a registered one", func(t *testing.T) {
+		mockThrottler.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "ghost@example.com").Return(nil, nil)
+		mockRestorer.EXPECT().GetRestorable(gomock.Any(), "ghost@example.com").Return(nil, nil)
+
+		assert.NoError(t, uc.RequestMagicLink(context.Background(), "ghost@example.com", "10.0.0.1"))
+	})
+
+	t.Run("lookup failures are not returned", func(t *testing.T) {
+		mockThrottler.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(nil, errors.New("database error"))
+
+		assert.NoError(t, uc.RequestMagicLink(context.Background(), "john@example.com", "10.0.0.1"))
+	})
+
+	t.Run("throttled requests do nothing", func(t *testing.T) {
+		mockThrottler.EXPECT().
+			Allow(gomock.Any(), domain.OneTimeTokenMagicLink, "john@example.com", "10.0.0.1").
+			Return(e.NewTooManyRequestsError(time.Hour))
+
+		err := uc.RequestMagicLink(context.Background(), "john@example.com", "10.0.0.1")
+		assert.EqualError(t, err, e.NewTooManyRequestsError(time.Hour).Error())
+	})
+
+	t.Run("mail delivery failures are not returned", func(t *testing.T) {
+		mockThrottler.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(customer, nil)
+		mockTokenGen.EXPECT().Generate().Return("magic_token", nil)
+		mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+		mockIDGen.EXPECT().Generate().Return("token_123", nil)
+		mockTokenInvalidator.EXPECT().InvalidateForCustomer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockTokenStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+		assert.NoError(t, uc.RequestMagicLink(context.Background(), "john@example.com", "10.0.0.1"))
+	})
+}
+
+func TestMagicLinkUseCase_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEmailGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockTokenStorer := mocks.NewMockOneTimeTokenCreationRepository(ctrl)
+	mockTokenConsumer := mocks.NewMockConsumeOneTimeTokenRepository(ctrl)
+	mockTokenInvalidator := mocks.NewMockInvalidateOneTimeTokensRepository(ctrl)
+	mockVerifiedMarker := mocks.NewMockMarkCustomerVerifiedRepository(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockMFA := mocks.NewMockMFAChallenger(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	customer := &domain.Customer{ID: "customer_123", Email: "john@example.com", VerifiedAt: verifiedAt}
+	tokens := &domain.AuthTokens{AccessToken: "access", RefreshToken: "refresh"}
+	client := domain.ClientInfo{IP: "10.0.0.1", UserAgent: "firefox"}
+
+	tests := []struct {
+		name           string
+		credentials    any
+		setupMocks     func()
+		expectedTokens *domain.AuthTokens
+		expectedError  error
+	}{
+		{
+			name:        "issues tokens for a valid link",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token", IP: "10.0.0.1", UserAgent: "firefox"},
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().
+					Consume(gomock.Any(), domain.OneTimeTokenMagicLink, "magic_hash", gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_123"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), customer).Return(nil, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), customer, "", client).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "verifies the email of an unverified customer",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token", IP: "10.0.0.1", UserAgent: "firefox"},
+			setupMocks: func() {
+				unverified := &domain.Customer{ID: "customer_456"}
+
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().
+					Consume(gomock.Any(), domain.OneTimeTokenMagicLink, "magic_hash", gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_456"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_456").Return(unverified, nil)
+				mockVerifiedMarker.EXPECT().MarkVerified(gomock.Any(), "customer_456", gomock.Any()).Return(nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), unverified).Return(nil, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), unverified, "", client).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "second factor required",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token"},
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_123"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), customer).Return(&domain.AuthTokens{MFAToken: "mfa_token"}, nil)
+			},
+			expectedTokens: &domain.AuthTokens{MFAToken: "mfa_token"},
+		},
+		{
+			name:        "used or expired link",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token"},
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodMagicLink),
+		},
+		{
+			name:        "restores a customer deleted inside the grace period",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token", IP: "10.0.0.1", UserAgent: "firefox"},
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "customer_123", VerifiedAt: time.Now(), DeletedAt: time.Now()}
+
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_123"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorableByID(gomock.Any(), "customer_123").Return(deleted, nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(nil, nil)
+				mockRestorer.EXPECT().Restore(gomock.Any(), deleted).Return(nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), deleted, "", client).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "unverified deleted customer is marked verified once restored",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token", IP: "10.0.0.1", UserAgent: "firefox"},
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "customer_123", DeletedAt: time.Now()}
+
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_123"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorableByID(gomock.Any(), "customer_123").Return(deleted, nil)
+				gomock.InOrder(
+					mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(nil, nil),
+					mockRestorer.EXPECT().Restore(gomock.Any(), deleted).Return(nil),
+					mockVerifiedMarker.EXPECT().MarkVerified(gomock.Any(), "customer_123", gomock.Any()).Return(nil),
+				)
+				mockIssuer.EXPECT().Issue(gomock.Any(), deleted, "", client).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "deleted customer with a second factor waits for it to be restored",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token"},
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "customer_123", DeletedAt: time.Now()}
+
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_123"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorableByID(gomock.Any(), "customer_123").Return(deleted, nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(&domain.AuthTokens{MFAToken: "mfa_token"}, nil)
+			},
+			expectedTokens: &domain.AuthTokens{MFAToken: "mfa_token"},
+		},
+		{
+			name:        "purged customer",
+			credentials: inputs.MagicLinkAuth{Token: "magic_token"},
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("magic_token").Return("magic_hash", nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OneTimeToken{CustomerID: "customer_123"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorableByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodMagicLink),
+		},
+		{
+			name:          "empty token",
+			credentials:   inputs.MagicLinkAuth{},
+			setupMocks:    func() {},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodMagicLink),
+		},
+		{
+			name:          "invalid credentials type",
+			credentials:   inputs.PwdAuth{Email: "john@example.com"},
+			setupMocks:    func() {},
+			expectedError: &e.ValidationError{Field: "credentials", Err: "Invalid credentials type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewMagicLinkUseCase(
+				15*time.Minute,
+				"https://wishlist.local/magic-link",
+				nil,
+				nil,
+				mockEmailGetter,
+				mockCustomerGetter,
+				mockIDGen,
+				mockTokenGen,
+				mockTokenHasher,
+				mockTokenStorer,
+				mockTokenConsumer,
+				mockTokenInvalidator,
+				mockVerifiedMarker,
+				mockRestorer,
+				mockMailer,
+				mockMFA,
+				mockIssuer,
+			)
+
+			got, err := uc.Authenticate(context.Background(), tt.credentials)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedTokens, got)
+		})
+	}
+}
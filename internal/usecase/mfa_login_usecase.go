@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+const (
+	mfaPendingCacheKey  = "mfa_pending::%s"
+	mfaAttemptsCacheKey = "%s::attempts"
+	// mfaMaxAttempts is how many codes can be checked against a pending token before it is dropped
+	mfaMaxAttempts = 5
+)
+
+// mfaPendingLogin is kept in the cache between the password check and the second factor
+type mfaPendingLogin struct {
+	CustomerID string `json:"customer_id"`
+	// Restore is set when the first factor matched an account deleted during the grace period
+	Restore   bool      `json:"restore,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type MFALoginUseCase struct {
+	pendingTTL       time.Duration
+	cache            domain.Cache
+	tokenGen         domain.TokenGenerator
+	tokenHasher      domain.Hasher
+	mfaGetter        domain.GetCustomerMFARepository
+	stepMarker       domain.MarkTOTPStepUsedRepository
+	recoveryConsumer domain.ConsumeRecoveryCodeRepository
+	totp             domain.TOTP
+	secretDecrypter  domain.Decrypter
+	customerGetter   domain.GetCustomerByIDRepository
+	restorer         domain.AccountRestorer
+	issuer           domain.TokenIssuer
+}
+
+func NewMFALoginUseCase(
+	pendingTTL time.Duration,
+	cache domain.Cache,
+	tokenGen domain.TokenGenerator,
+	tokenHasher domain.Hasher,
+	mfaGetter domain.GetCustomerMFARepository,
+	stepMarker domain.MarkTOTPStepUsedRepository,
+	recoveryConsumer domain.ConsumeRecoveryCodeRepository,
+	totp domain.TOTP,
+	secretDecrypter domain.Decrypter,
+	customerGetter domain.GetCustomerByIDRepository,
+	restorer domain.AccountRestorer,
+	issuer domain.TokenIssuer,
+) *MFALoginUseCase {
+	return &MFALoginUseCase{
+		pendingTTL:       pendingTTL,
+		cache:            cache,
+		tokenGen:         tokenGen,
+		tokenHasher:      tokenHasher,
+		mfaGetter:        mfaGetter,
+		stepMarker:       stepMarker,
+		recoveryConsumer: recoveryConsumer,
+		totp:             totp,
+		secretDecrypter:  secretDecrypter,
+		customerGetter:   customerGetter,
+		restorer:         restorer,
+		issuer:           issuer,
+	}
+}
+
+func (u *MFALoginUseCase) Challenge(ctx context.Context, customer *domain.Customer) (*domain.AuthTokens, error) {
+	mfa, err := u.mfaGetter.GetByCustomerID(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !mfa.Enabled() {
+		return nil, nil
+	}
+
+	token, err := u.tokenGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate mfa token"))
+	}
+
+	key, err := u.pendingKey(token)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(u.pendingTTL)
+	pending := mfaPendingLogin{CustomerID: customer.ID, Restore: !customer.DeletedAt.IsZero(), ExpiresAt: expiresAt}
+	if err := u.savePending(ctx, key, pending); err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		MFAToken:  token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyMFA swaps a pending token and a second factor for real tokens, the pending token
+// can only be used once and is dropped after too many wrong codes
+func (u *MFALoginUseCase) VerifyMFA(ctx context.Context, mfaToken string, code string, client domain.ClientInfo) (*domain.AuthTokens, error) {
+	key, err := u.pendingKey(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := u.cache.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == "" {
+		return nil, e.NewAuthenticationError(domain.AuthMethodTOTP)
+	}
+
+	var pending mfaPendingLogin
+	if err := json.Unmarshal([]byte(value), &pending); err != nil {
+		return nil, err
+	}
+
+	// the attempt is counted before the code is checked, so concurrent guesses can't go past the limit
+	attemptsKey := fmt.Sprintf(mfaAttemptsCacheKey, key)
+	attempts, err := u.cache.Increment(ctx, attemptsKey, time.Until(pending.ExpiresAt))
+	if err != nil {
+		return nil, err
+	}
+
+	if attempts > mfaMaxAttempts {
+		if err := u.cache.Delete(ctx, key, attemptsKey); err != nil {
+			return nil, err
+		}
+
+		return nil, e.NewAuthenticationError(domain.AuthMethodTOTP)
+	}
+
+	if err := u.VerifySecondFactor(ctx, pending.CustomerID, code); err != nil {
+		if !e.IsValidationError(err) {
+			return nil, err
+		}
+
+		if attempts == mfaMaxAttempts {
+			if err := u.cache.Delete(ctx, key, attemptsKey); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, e.NewAuthenticationError(domain.AuthMethodTOTP)
+	}
+
+	if err := u.cache.Delete(ctx, key, attemptsKey); err != nil {
+		return nil, err
+	}
+
+	customer, err := u.customerGetter.GetByID(ctx, pending.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// a deleted account is only restored once both factors matched
+	if customer == nil && pending.Restore {
+		customer, err = u.restorer.GetRestorableByID(ctx, pending.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+
+		if customer != nil {
+			if err := u.restorer.Restore(ctx, customer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if customer == nil {
+		return nil, e.NewAuthenticationError(domain.AuthMethodTOTP)
+	}
+
+	return u.issuer.Issue(ctx, customer, "", client)
+}
+
+func (u *MFALoginUseCase) VerifySecondFactor(ctx context.Context, customerID string, code string) error {
+	invalidCode := &e.ValidationError{Field: "code", Err: "is invalid"}
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if code == "" {
+		return e.NewRequiredFieldError("code")
+	}
+
+	mfa, err := u.mfaGetter.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if !mfa.Enabled() {
+		return &e.ValidationError{Field: "mfa", Err: "is not enabled"}
+	}
+
+	now := time.Now()
+	if isTOTPCode(code) {
+		secret, err := u.secretDecrypter.Decrypt(mfa.Secret)
+		if err != nil {
+			return errors.Join(err, errors.New("failed to decrypt totp secret"))
+		}
+
+		step, ok := u.totp.Validate(secret, code, now)
+		if !ok {
+			return invalidCode
+		}
+
+		fresh, err := u.stepMarker.MarkStepUsed(ctx, customerID, step)
+		if err != nil {
+			return err
+		}
+
+		if !fresh {
+			return invalidCode
+		}
+
+		return nil
+	}
+
+	codeHash, err := u.tokenHasher.Hash(code)
+	if err != nil {
+		return err
+	}
+
+	consumed, err := u.recoveryConsumer.ConsumeRecoveryCode(ctx, customerID, codeHash, now)
+	if err != nil {
+		return err
+	}
+
+	if !consumed {
+		return invalidCode
+	}
+
+	return nil
+}
+
+func (u *MFALoginUseCase) pendingKey(token string) (string, error) {
+	hash, err := u.tokenHasher.Hash(token)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(mfaPendingCacheKey, hash), nil
+}
+
+func (u *MFALoginUseCase) savePending(ctx context.Context, key string, pending mfaPendingLogin) error {
+	value, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(pending.ExpiresAt)
+	if ttl <= 0 {
+		return u.cache.Delete(ctx, key)
+	}
+
+	return u.cache.Set(ctx, key, string(value), ttl)
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,368 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+var enabledMFA = &domain.CustomerMFA{
+	CustomerID: "customer_123",
+	Secret:     "encrypted_secret",
+	EnabledAt:  time.Now(),
+}
+
+func TestMFALoginUseCase_Challenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockMFAGetter := mocks.NewMockGetCustomerMFARepository(ctrl)
+	mockStepMarker := mocks.NewMockMarkTOTPStepUsedRepository(ctrl)
+	mockRecoveryConsumer := mocks.NewMockConsumeRecoveryCodeRepository(ctrl)
+	mockTOTP := mocks.NewMockTOTP(ctrl)
+	mockDecrypter := mocks.NewMockDecrypter(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	uc := usecase.NewMFALoginUseCase(
+		5*time.Minute,
+		mockCache,
+		mockTokenGen,
+		mockTokenHasher,
+		mockMFAGetter,
+		mockStepMarker,
+		mockRecoveryConsumer,
+		mockTOTP,
+		mockDecrypter,
+		mockCustomerGetter,
+		nil,
+		mockIssuer,
+	)
+
+	customer := &domain.Customer{ID: "customer_123"}
+
+	t.Run("customer without second factor", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+
+		tokens, err := uc.Challenge(context.Background(), customer)
+		assert.NoError(t, err)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("pending enrollment does not count", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(&domain.CustomerMFA{CustomerID: "customer_123"}, nil)
+
+		tokens, err := uc.Challenge(context.Background(), customer)
+		assert.NoError(t, err)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("remembers that a deleted account waits for its restore", func(t *testing.T) {
+		deleted := &domain.Customer{ID: "customer_123", DeletedAt: time.Now()}
+
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+		mockTokenGen.EXPECT().Generate().Return("mfa_token", nil)
+		mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+		mockCache.EXPECT().
+			Set(gomock.Any(), "mfa_pending::mfa_hash", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, value string, ttl time.Duration) error {
+				assert.Contains(t, value, `"restore":true`)
+				return nil
+			})
+
+		tokens, err := uc.Challenge(context.Background(), deleted)
+		assert.NoError(t, err)
+		assert.Equal(t, "mfa_token", tokens.MFAToken)
+	})
+
+	t.Run("returns a pending token", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+		mockTokenGen.EXPECT().Generate().Return("mfa_token", nil)
+		mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+		mockCache.EXPECT().
+			Set(gomock.Any(), "mfa_pending::mfa_hash", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, value string, ttl time.Duration) error {
+				var pending map[string]any
+				require.NoError(t, json.Unmarshal([]byte(value), &pending))
+				assert.Equal(t, "customer_123", pending["customer_id"])
+				assert.NotContains(t, pending, "restore")
+				assert.InDelta(t, 5*time.Minute, ttl, float64(time.Second))
+				return nil
+			})
+
+		tokens, err := uc.Challenge(context.Background(), customer)
+		assert.NoError(t, err)
+		assert.Equal(t, "mfa_token", tokens.MFAToken)
+		assert.Empty(t, tokens.AccessToken)
+		assert.Empty(t, tokens.RefreshToken)
+	})
+}
+
+func TestMFALoginUseCase_VerifyMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockMFAGetter := mocks.NewMockGetCustomerMFARepository(ctrl)
+	mockStepMarker := mocks.NewMockMarkTOTPStepUsedRepository(ctrl)
+	mockRecoveryConsumer := mocks.NewMockConsumeRecoveryCodeRepository(ctrl)
+	mockTOTP := mocks.NewMockTOTP(ctrl)
+	mockDecrypter := mocks.NewMockDecrypter(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	customer := &domain.Customer{ID: "customer_123"}
+	tokens := &domain.AuthTokens{AccessToken: "access", RefreshToken: "refresh"}
+	client := domain.ClientInfo{IP: "10.0.0.1", UserAgent: "curl/8.0"}
+	pendingValue := func() string {
+		value, _ := json.Marshal(map[string]any{
+			"customer_id": "customer_123",
+			"expires_at":  time.Now().Add(time.Minute),
+		})
+		return string(value)
+	}
+	restoringValue := func() string {
+		value, _ := json.Marshal(map[string]any{
+			"customer_id": "customer_123",
+			"restore":     true,
+			"expires_at":  time.Now().Add(time.Minute),
+		})
+		return string(value)
+	}
+
+	tests := []struct {
+		name           string
+		code           string
+		setupMocks     func()
+		expectedTokens *domain.AuthTokens
+		expectedError  error
+	}{
+		{
+			name: "valid totp code",
+			code: "123456",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return(pendingValue(), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "mfa_pending::mfa_hash::attempts", gomock.Any()).Return(int64(1), nil)
+				mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+				mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTP.EXPECT().Validate("JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(42), true)
+				mockStepMarker.EXPECT().MarkStepUsed(gomock.Any(), "customer_123", int64(42)).Return(true, nil)
+				mockCache.EXPECT().Delete(gomock.Any(), "mfa_pending::mfa_hash", "mfa_pending::mfa_hash::attempts").Return(nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), customer, "", client).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name: "deleted account is restored once the second factor matched",
+			code: "123456",
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "customer_123", DeletedAt: time.Now()}
+
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return(restoringValue(), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "mfa_pending::mfa_hash::attempts", gomock.Any()).Return(int64(1), nil)
+				mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+				mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTP.EXPECT().Validate("JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(42), true)
+				mockStepMarker.EXPECT().MarkStepUsed(gomock.Any(), "customer_123", int64(42)).Return(true, nil)
+				mockCache.EXPECT().Delete(gomock.Any(), "mfa_pending::mfa_hash", "mfa_pending::mfa_hash::attempts").Return(nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorableByID(gomock.Any(), "customer_123").Return(deleted, nil)
+				mockRestorer.EXPECT().Restore(gomock.Any(), deleted).Return(nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), deleted, "", client).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name: "account deleted while the second factor was pending",
+			code: "123456",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return(pendingValue(), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "mfa_pending::mfa_hash::attempts", gomock.Any()).Return(int64(1), nil)
+				mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+				mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTP.EXPECT().Validate("JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(42), true)
+				mockStepMarker.EXPECT().MarkStepUsed(gomock.Any(), "customer_123", int64(42)).Return(true, nil)
+				mockCache.EXPECT().Delete(gomock.Any(), "mfa_pending::mfa_hash", "mfa_pending::mfa_hash::attempts").Return(nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodTOTP),
+		},
+		{
+			name: "valid recovery code",
+			code: "recovery_code_1",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return(pendingValue(), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "mfa_pending::mfa_hash::attempts", gomock.Any()).Return(int64(1), nil)
+				mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+				mockTokenHasher.EXPECT().Hash("recovery_code_1").Return("recovery_hash", nil)
+				mockRecoveryConsumer.EXPECT().ConsumeRecoveryCode(gomock.Any(), "customer_123", "recovery_hash", gomock.Any()).Return(true, nil)
+				mockCache.EXPECT().Delete(gomock.Any(), "mfa_pending::mfa_hash", "mfa_pending::mfa_hash::attempts").Return(nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), customer, "", client).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name: "replayed totp code counts as an attempt",
+			code: "123456",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return(pendingValue(), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "mfa_pending::mfa_hash::attempts", gomock.Any()).Return(int64(1), nil)
+				mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+				mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTP.EXPECT().Validate(gomock.Any(), "123456", gomock.Any()).Return(int64(42), true)
+				mockStepMarker.EXPECT().MarkStepUsed(gomock.Any(), "customer_123", int64(42)).Return(false, nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodTOTP),
+		},
+		{
+			name: "too many wrong codes drop the pending token",
+			code: "000000",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return(pendingValue(), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "mfa_pending::mfa_hash::attempts", gomock.Any()).Return(int64(5), nil)
+				mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+				mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTP.EXPECT().Validate(gomock.Any(), "000000", gomock.Any()).Return(int64(0), false)
+				mockCache.EXPECT().Delete(gomock.Any(), "mfa_pending::mfa_hash", "mfa_pending::mfa_hash::attempts").Return(nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodTOTP),
+		},
+		{
+			name: "codes past the limit are not checked",
+			code: "123456",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return(pendingValue(), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "mfa_pending::mfa_hash::attempts", gomock.Any()).Return(int64(6), nil)
+				mockCache.EXPECT().Delete(gomock.Any(), "mfa_pending::mfa_hash", "mfa_pending::mfa_hash::attempts").Return(nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodTOTP),
+		},
+		{
+			name: "unknown or expired pending token",
+			code: "123456",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return("", nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodTOTP),
+		},
+		{
+			name: "cache failure",
+			code: "123456",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("mfa_token").Return("mfa_hash", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "mfa_pending::mfa_hash").Return("", errors.New("redis down"))
+			},
+			expectedError: errors.New("redis down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewMFALoginUseCase(
+				5*time.Minute,
+				mockCache,
+				mockTokenGen,
+				mockTokenHasher,
+				mockMFAGetter,
+				mockStepMarker,
+				mockRecoveryConsumer,
+				mockTOTP,
+				mockDecrypter,
+				mockCustomerGetter,
+				mockRestorer,
+				mockIssuer,
+			)
+
+			result, err := uc.VerifyMFA(context.Background(), "mfa_token", tt.code, client)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTokens, result)
+		})
+	}
+}
+
+func TestMFALoginUseCase_VerifySecondFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockMFAGetter := mocks.NewMockGetCustomerMFARepository(ctrl)
+	mockStepMarker := mocks.NewMockMarkTOTPStepUsedRepository(ctrl)
+	mockRecoveryConsumer := mocks.NewMockConsumeRecoveryCodeRepository(ctrl)
+	mockTOTP := mocks.NewMockTOTP(ctrl)
+	mockDecrypter := mocks.NewMockDecrypter(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	uc := usecase.NewMFALoginUseCase(
+		5*time.Minute,
+		mockCache,
+		mockTokenGen,
+		mockTokenHasher,
+		mockMFAGetter,
+		mockStepMarker,
+		mockRecoveryConsumer,
+		mockTOTP,
+		mockDecrypter,
+		mockCustomerGetter,
+		nil,
+		mockIssuer,
+	)
+
+	t.Run("mfa not enabled", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+
+		err := uc.VerifySecondFactor(context.Background(), "customer_123", "123456")
+		assert.EqualError(t, err, (&e.ValidationError{Field: "mfa", Err: "is not enabled"}).Error())
+	})
+
+	t.Run("used recovery code", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(enabledMFA, nil)
+		mockTokenHasher.EXPECT().Hash("recovery_code_1").Return("recovery_hash", nil)
+		mockRecoveryConsumer.EXPECT().ConsumeRecoveryCode(gomock.Any(), "customer_123", "recovery_hash", gomock.Any()).Return(false, nil)
+
+		err := uc.VerifySecondFactor(context.Background(), "customer_123", " recovery_code_1 ")
+		assert.EqualError(t, err, (&e.ValidationError{Field: "code", Err: "is invalid"}).Error())
+	})
+
+	t.Run("missing code", func(t *testing.T) {
+		err := uc.VerifySecondFactor(context.Background(), "customer_123", "")
+		assert.EqualError(t, err, e.NewRequiredFieldError("code").Error())
+	})
+}
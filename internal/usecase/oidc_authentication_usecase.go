@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+)
+
+const oidcStateCacheKey = "oidc_state::%s"
+
+// oidcLoginState is kept in the cache between the redirect to the provider and the callback
+type oidcLoginState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+type OIDCAuthenticationUseCase struct {
+	stateTTL        time.Duration
+	cache           domain.Cache
+	provider        domain.OIDCProvider
+	tokenGen        domain.TokenGenerator
+	idGen           domain.IDGenerator
+	identityGetter  domain.GetOIDCIdentityRepository
+	identityStorer  domain.OIDCIdentityCreationRepository
+	customerGetter  domain.GetCustomerByIDRepository
+	emailGetter     domain.GetCustomerByEmailRepository
+	customerCreator domain.CustomerCreationRepository
+	verifiedMarker  domain.MarkCustomerVerifiedRepository
+	passwordUpdater domain.UpdateCustomerPasswordRepository
+	restorer        domain.AccountRestorer
+	revoker         domain.TokenRevoker
+	mfa             domain.MFAChallenger
+	issuer          domain.TokenIssuer
+}
+
+func NewOIDCAuthenticationUseCase(
+	stateTTL time.Duration,
+	cache domain.Cache,
+	provider domain.OIDCProvider,
+	tokenGen domain.TokenGenerator,
+	idGen domain.IDGenerator,
+	identityGetter domain.GetOIDCIdentityRepository,
+	identityStorer domain.OIDCIdentityCreationRepository,
+	customerGetter domain.GetCustomerByIDRepository,
+	emailGetter domain.GetCustomerByEmailRepository,
+	customerCreator domain.CustomerCreationRepository,
+	verifiedMarker domain.MarkCustomerVerifiedRepository,
+	passwordUpdater domain.UpdateCustomerPasswordRepository,
+	restorer domain.AccountRestorer,
+	revoker domain.TokenRevoker,
+	mfa domain.MFAChallenger,
+	issuer domain.TokenIssuer,
+) *OIDCAuthenticationUseCase {
+	return &OIDCAuthenticationUseCase{
+		stateTTL:        stateTTL,
+		cache:           cache,
+		provider:        provider,
+		tokenGen:        tokenGen,
+		idGen:           idGen,
+		identityGetter:  identityGetter,
+		identityStorer:  identityStorer,
+		customerGetter:  customerGetter,
+		emailGetter:     emailGetter,
+		customerCreator: customerCreator,
+		verifiedMarker:  verifiedMarker,
+		passwordUpdater: passwordUpdater,
+		restorer:        restorer,
+		revoker:         revoker,
+		mfa:             mfa,
+		issuer:          issuer,
+	}
+}
+
+// StartLogin creates the state, nonce and PKCE verifier of a new login and returns the provider URL
+func (u *OIDCAuthenticationUseCase) StartLogin(ctx context.Context) (string, error) {
+	secrets := make([]string, 3)
+	for i := range secrets {
+		secret, err := u.tokenGen.Generate()
+		if err != nil {
+			return "", errors.Join(err, errors.New("failed to generate login state"))
+		}
+		secrets[i] = secret
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	value, err := json.Marshal(oidcLoginState{Nonce: nonce, CodeVerifier: verifier})
+	if err != nil {
+		return "", err
+	}
+
+	if err := u.cache.Set(ctx, fmt.Sprintf(oidcStateCacheKey, state), string(value), u.stateTTL); err != nil {
+		return "", err
+	}
+
+	return u.provider.AuthCodeURL(ctx, state, nonce, verifier)
+}
+
+// Authenticate finishes the login started by StartLogin. A known identity logs into its customer,
+// otherwise the verified email is linked to an existing customer or a customer without password is created.
+// Customers deleted during the grace period are restored like on a password login.
+// The provider only replaces the password, customers with a second factor still have to give it
+func (u *OIDCAuthenticationUseCase) Authenticate(ctx context.Context, credentials any) (*domain.AuthTokens, error) {
+	callback, ok := credentials.(inputs.OIDCCallback)
+	if !ok {
+		return nil, &e.ValidationError{
+			Field: "credentials",
+			Err:   "Invalid credentials type",
+		}
+	}
+
+	key := fmt.Sprintf(oidcStateCacheKey, callback.State)
+	value, err := u.cache.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == "" {
+		return nil, e.NewAuthenticationError(domain.AuthMethodOIDC)
+	}
+
+	// a state can only be used once
+	if err := u.cache.Delete(ctx, key); err != nil {
+		return nil, err
+	}
+
+	var state oidcLoginState
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		return nil, err
+	}
+
+	claims, err := u.provider.Exchange(ctx, callback.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		fmt.Printf("[oidc_authentication_usecase] ERROR exchanging the authorization code: %v\n", err)
+		return nil, e.NewAuthenticationError(domain.AuthMethodOIDC)
+	}
+
+	customer, restore, err := u.findOrCreateCustomer(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// a deleted account is restored once the second factor, if any, matched too
+	pending, err := u.mfa.Challenge(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending != nil {
+		return pending, nil
+	}
+
+	if restore != nil {
+		if err := restore(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.issuer.Issue(ctx, customer, "", domain.ClientInfo{IP: callback.IP, UserAgent: callback.UserAgent})
+}
+
+// findOrCreateCustomer leaves an account deleted during the grace period deleted and returns the step
+// restoring it instead, so the caller runs it only once every factor matched. It is nil otherwise
+func (u *OIDCAuthenticationUseCase) findOrCreateCustomer(ctx context.Context, claims *domain.OIDCClaims) (*domain.Customer, func(ctx context.Context) error, error) {
+	identity, err := u.identityGetter.GetByIssuerAndSubject(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if identity != nil {
+		customer, err := u.customerGetter.GetByID(ctx, identity.CustomerID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if customer != nil {
+			return customer, nil, nil
+		}
+
+		customer, err = u.restorer.GetRestorableByID(ctx, identity.CustomerID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if customer == nil {
+			return nil, nil, e.NewAuthenticationError(domain.AuthMethodOIDC)
+		}
+
+		return customer, func(ctx context.Context) error {
+			return u.restorer.Restore(ctx, customer)
+		}, nil
+	}
+
+	// linking by email is only safe when the provider vouches for the address
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, nil, &e.ValidationError{Field: "email", Err: "must be verified by the identity provider"}
+	}
+
+	now := time.Now()
+	customer, err := u.emailGetter.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the provider vouches for the address, which is enough to restore a deleted account
+	var restore func(ctx context.Context) error
+	if customer == nil {
+		customer, err = u.restorer.GetRestorable(ctx, claims.Email)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// the take over of a deleted account waits for its restore, its row can't be updated before
+		if customer != nil {
+			deleted := customer
+			restore = func(ctx context.Context) error {
+				if err := u.restorer.Restore(ctx, deleted); err != nil {
+					return err
+				}
+
+				if !deleted.VerifiedAt.IsZero() {
+					return nil
+				}
+
+				return u.takeOverUnverifiedCustomer(ctx, deleted, time.Now())
+			}
+		}
+	}
+
+	if customer == nil {
+		customer, err = u.createCustomer(ctx, claims, now)
+	} else if customer.VerifiedAt.IsZero() && restore == nil {
+		err = u.takeOverUnverifiedCustomer(ctx, customer, now)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return nil, nil, errors.Join(err, errors.New("failed to generate identity ID"))
+	}
+
+	err = u.identityStorer.Create(ctx, &domain.OIDCIdentity{
+		ID:         id,
+		CustomerID: customer.ID,
+		Issuer:     claims.Issuer,
+		Subject:    claims.Subject,
+		Email:      claims.Email,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return customer, restore, nil
+}
+
+func (u *OIDCAuthenticationUseCase) createCustomer(ctx context.Context, claims *domain.OIDCClaims, now time.Time) (*domain.Customer, error) {
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate customer ID"))
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = strings.Split(claims.Email, "@")[0]
+	}
+
+	// customers created by a provider have no password until they reset it
+	customer := &domain.Customer{
+		ID:         id,
+		Name:       name,
+		Email:      claims.Email,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		VerifiedAt: now,
+		Role:       domain.RoleCustomer,
+	}
+
+	return customer, u.customerCreator.Create(ctx, customer)
+}
+
+// takeOverUnverifiedCustomer hands an unverified account over to the owner of the address,
+// whoever registered it with a password never proved they own the email
+func (u *OIDCAuthenticationUseCase) takeOverUnverifiedCustomer(ctx context.Context, customer *domain.Customer, now time.Time) error {
+	if err := u.verifiedMarker.MarkVerified(ctx, customer.ID, now); err != nil {
+		return err
+	}
+	customer.VerifiedAt = now
+
+	if customer.Password != "" {
+		if err := u.passwordUpdater.UpdatePassword(ctx, customer.ID, "", now); err != nil {
+			return err
+		}
+		customer.Password = ""
+	}
+
+	return u.revoker.RevokeAllForCustomer(ctx, customer.ID)
+}
@@ -0,0 +1,390 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/presentation/inputs"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOIDCAuthenticationUseCase_StartLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockProvider := mocks.NewMockOIDCProvider(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockIdentityGetter := mocks.NewMockGetOIDCIdentityRepository(ctrl)
+	mockIdentityStorer := mocks.NewMockOIDCIdentityCreationRepository(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockEmailGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockCustomerCreator := mocks.NewMockCustomerCreationRepository(ctrl)
+	mockVerifiedMarker := mocks.NewMockMarkCustomerVerifiedRepository(ctrl)
+	mockPasswordUpdater := mocks.NewMockUpdateCustomerPasswordRepository(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+	mockMFA := mocks.NewMockMFAChallenger(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	uc := usecase.NewOIDCAuthenticationUseCase(
+		10*time.Minute,
+		mockCache,
+		mockProvider,
+		mockTokenGen,
+		mockIDGen,
+		mockIdentityGetter,
+		mockIdentityStorer,
+		mockCustomerGetter,
+		mockEmailGetter,
+		mockCustomerCreator,
+		mockVerifiedMarker,
+		mockPasswordUpdater,
+		mockRestorer,
+		mockRevoker,
+		mockMFA,
+		mockIssuer,
+	)
+
+	gomock.InOrder(
+		mockTokenGen.EXPECT().Generate().Return("state_123", nil),
+		mockTokenGen.EXPECT().Generate().Return("nonce_123", nil),
+		mockTokenGen.EXPECT().Generate().Return("verifier_123", nil),
+	)
+	mockCache.EXPECT().
+		Set(gomock.Any(), "oidc_state::state_123", `{"nonce":"nonce_123","code_verifier":"verifier_123"}`, 10*time.Minute).
+		Return(nil)
+	mockProvider.EXPECT().
+		AuthCodeURL(gomock.Any(), "state_123", "nonce_123", "verifier_123").
+		Return("https://issuer.example.com/authorize?state=state_123", nil)
+
+	authURL, err := uc.StartLogin(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://issuer.example.com/authorize?state=state_123", authURL)
+}
+
+func TestOIDCAuthenticationUseCase_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockProvider := mocks.NewMockOIDCProvider(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockIdentityGetter := mocks.NewMockGetOIDCIdentityRepository(ctrl)
+	mockIdentityStorer := mocks.NewMockOIDCIdentityCreationRepository(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockEmailGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockCustomerCreator := mocks.NewMockCustomerCreationRepository(ctrl)
+	mockVerifiedMarker := mocks.NewMockMarkCustomerVerifiedRepository(ctrl)
+	mockPasswordUpdater := mocks.NewMockUpdateCustomerPasswordRepository(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+	mockMFA := mocks.NewMockMFAChallenger(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	callback := inputs.OIDCCallback{Code: "code_123", State: "state_123"}
+	tokens := &domain.AuthTokens{AccessToken: "access", RefreshToken: "refresh"}
+	claims := &domain.OIDCClaims{
+		Issuer:        "https://issuer.example.com",
+		Subject:       "provider_user_123",
+		Email:         "john@example.com",
+		EmailVerified: true,
+		Name:          "John Doe",
+	}
+
+	expectValidState := func() {
+		mockCache.EXPECT().
+			Get(gomock.Any(), "oidc_state::state_123").
+			Return(`{"nonce":"nonce_123","code_verifier":"verifier_123"}`, nil)
+		mockCache.EXPECT().Delete(gomock.Any(), "oidc_state::state_123").Return(nil)
+	}
+
+	tests := []struct {
+		name           string
+		credentials    any
+		setupMocks     func()
+		expectedTokens *domain.AuthTokens
+		expectedError  error
+	}{
+		{
+			name:        "known identity",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), "code_123", "verifier_123", "nonce_123").Return(claims, nil)
+				mockIdentityGetter.EXPECT().
+					GetByIssuerAndSubject(gomock.Any(), "https://issuer.example.com", "provider_user_123").
+					Return(&domain.OIDCIdentity{CustomerID: "customer_123"}, nil)
+
+				customer := &domain.Customer{ID: "customer_123"}
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), customer).Return(nil, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), customer, "", domain.ClientInfo{}).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "second factor required",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().
+					GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OIDCIdentity{CustomerID: "customer_123"}, nil)
+
+				customer := &domain.Customer{ID: "customer_123"}
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), customer).Return(&domain.AuthTokens{MFAToken: "mfa_token"}, nil)
+			},
+			expectedTokens: &domain.AuthTokens{MFAToken: "mfa_token"},
+		},
+		{
+			name:        "new customer is created without password",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(nil, nil)
+				gomock.InOrder(
+					mockIDGen.EXPECT().Generate().Return("customer_123", nil),
+					mockIDGen.EXPECT().Generate().Return("identity_123", nil),
+				)
+				mockCustomerCreator.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, c *domain.Customer) error {
+						assert.Equal(t, "customer_123", c.ID)
+						assert.Equal(t, "John Doe", c.Name)
+						assert.Equal(t, "john@example.com", c.Email)
+						assert.Empty(t, c.Password)
+						assert.False(t, c.VerifiedAt.IsZero())
+						return nil
+					})
+				mockIdentityStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, identity *domain.OIDCIdentity) error {
+						assert.Equal(t, "identity_123", identity.ID)
+						assert.Equal(t, "customer_123", identity.CustomerID)
+						assert.Equal(t, "provider_user_123", identity.Subject)
+						return nil
+					})
+				mockMFA.EXPECT().Challenge(gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), gomock.Any(), "", domain.ClientInfo{}).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "restores the deleted customer of a known identity",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().
+					GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OIDCIdentity{CustomerID: "customer_123"}, nil)
+
+				deleted := &domain.Customer{ID: "customer_123", DeletedAt: time.Now()}
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorableByID(gomock.Any(), "customer_123").Return(deleted, nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(nil, nil)
+				mockRestorer.EXPECT().Restore(gomock.Any(), deleted).Return(nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), deleted, "", domain.ClientInfo{}).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "purged customer of a known identity",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().
+					GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.OIDCIdentity{CustomerID: "customer_123"}, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorableByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodOIDC),
+		},
+		{
+			name:        "restores a deleted customer with the same email",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+				deleted := &domain.Customer{ID: "customer_123", VerifiedAt: time.Now(), DeletedAt: time.Now()}
+				mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(deleted, nil)
+				mockIDGen.EXPECT().Generate().Return("identity_123", nil)
+				mockIdentityStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(nil, nil)
+				mockRestorer.EXPECT().Restore(gomock.Any(), deleted).Return(nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), deleted, "", domain.ClientInfo{}).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "deleted customer with a second factor waits for it to be restored",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+				deleted := &domain.Customer{ID: "customer_123", VerifiedAt: time.Now(), DeletedAt: time.Now()}
+				mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(deleted, nil)
+				mockIDGen.EXPECT().Generate().Return("identity_123", nil)
+				mockIdentityStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(&domain.AuthTokens{MFAToken: "mfa_token"}, nil)
+			},
+			expectedTokens: &domain.AuthTokens{MFAToken: "mfa_token"},
+		},
+		{
+			name:        "unverified deleted customer is taken over once restored",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+				deleted := &domain.Customer{ID: "customer_123", Password: "hash", DeletedAt: time.Now()}
+				mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "john@example.com").Return(deleted, nil)
+				mockIDGen.EXPECT().Generate().Return("identity_123", nil)
+				mockIdentityStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				gomock.InOrder(
+					mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(nil, nil),
+					mockRestorer.EXPECT().Restore(gomock.Any(), deleted).Return(nil),
+					mockVerifiedMarker.EXPECT().MarkVerified(gomock.Any(), "customer_123", gomock.Any()).Return(nil),
+					mockPasswordUpdater.EXPECT().UpdatePassword(gomock.Any(), "customer_123", "", gomock.Any()).Return(nil),
+					mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil),
+				)
+				mockIssuer.EXPECT().Issue(gomock.Any(), deleted, "", domain.ClientInfo{}).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "links a verified customer by email",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+				customer := &domain.Customer{ID: "customer_123", Password: "hash", VerifiedAt: time.Now()}
+				mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(customer, nil)
+				mockIDGen.EXPECT().Generate().Return("identity_123", nil)
+				mockIdentityStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), customer).Return(nil, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), customer, "", domain.ClientInfo{}).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "linking an unverified customer drops its password and sessions",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(claims, nil)
+				mockIdentityGetter.EXPECT().GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+				customer := &domain.Customer{ID: "customer_123", Password: "hash"}
+				mockEmailGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(customer, nil)
+				mockVerifiedMarker.EXPECT().MarkVerified(gomock.Any(), "customer_123", gomock.Any()).Return(nil)
+				mockPasswordUpdater.EXPECT().UpdatePassword(gomock.Any(), "customer_123", "", gomock.Any()).Return(nil)
+				mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil)
+				mockIDGen.EXPECT().Generate().Return("identity_123", nil)
+				mockIdentityStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockMFA.EXPECT().Challenge(gomock.Any(), customer).Return(nil, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), customer, "", domain.ClientInfo{}).Return(tokens, nil)
+			},
+			expectedTokens: tokens,
+		},
+		{
+			name:        "unverified provider email",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				unverified := *claims
+				unverified.EmailVerified = false
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&unverified, nil)
+				mockIdentityGetter.EXPECT().GetByIssuerAndSubject(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedError: &e.ValidationError{Field: "email", Err: "must be verified by the identity provider"},
+		},
+		{
+			name:        "unknown or reused state",
+			credentials: callback,
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "oidc_state::state_123").Return("", nil)
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodOIDC),
+		},
+		{
+			name:        "code exchange fails",
+			credentials: callback,
+			setupMocks: func() {
+				expectValidState()
+				mockProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("invalid_grant"))
+			},
+			expectedError: e.NewAuthenticationError(domain.AuthMethodOIDC),
+		},
+		{
+			name:          "invalid credentials type",
+			credentials:   inputs.PwdAuth{},
+			setupMocks:    func() {},
+			expectedError: &e.ValidationError{Field: "credentials", Err: "Invalid credentials type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewOIDCAuthenticationUseCase(
+				10*time.Minute,
+				mockCache,
+				mockProvider,
+				mockTokenGen,
+				mockIDGen,
+				mockIdentityGetter,
+				mockIdentityStorer,
+				mockCustomerGetter,
+				mockEmailGetter,
+				mockCustomerCreator,
+				mockVerifiedMarker,
+				mockPasswordUpdater,
+				mockRestorer,
+				mockRevoker,
+				mockMFA,
+				mockIssuer,
+			)
+
+			result, err := uc.Authenticate(context.Background(), tt.credentials)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTokens, result)
+		})
+	}
+}
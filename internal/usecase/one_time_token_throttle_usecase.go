@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+const oneTimeTokenRequestsCacheKey = "one_time_token_requests::%s::%s::%s"
+
+type OneTimeTokenThrottleConfig struct {
+	// EmailLimit is how many tokens of the same purpose an email can ask for within the window
+	EmailLimit int64
+	// IPLimit is how many tokens of the same purpose a client IP can ask for, across every email
+	IPLimit int64
+	// Window is how long requests are remembered after the last one
+	Window time.Duration
+}
+
+type OneTimeTokenThrottleUseCase struct {
+	config OneTimeTokenThrottleConfig
+	cache  domain.Cache
+}
+
+func NewOneTimeTokenThrottleUseCase(config OneTimeTokenThrottleConfig, cache domain.Cache) *OneTimeTokenThrottleUseCase {
+	return &OneTimeTokenThrottleUseCase{
+		config: config,
+		cache:  cache,
+	}
+}
+
+// Allow counts the request for the email and the IP, requests over the limit are counted too
+// so a client that keeps asking stays blocked until it stops for a whole window
+func (u *OneTimeTokenThrottleUseCase) Allow(ctx context.Context, purpose domain.OneTimeTokenPurpose, email string, ip string) error {
+	blocked := false
+
+	subjects := [][2]string{{"email", normalizeEmail(email)}}
+	if ip != "" {
+		subjects = append(subjects, [2]string{"ip", ip})
+	}
+
+	for _, subject := range subjects {
+		kind, value := subject[0], subject[1]
+
+		requests, err := u.cache.Increment(ctx, fmt.Sprintf(oneTimeTokenRequestsCacheKey, purpose, kind, value), u.config.Window)
+		if err != nil {
+			return err
+		}
+
+		limit := u.config.EmailLimit
+		if kind == "ip" {
+			limit = u.config.IPLimit
+		}
+
+		if requests > limit {
+			blocked = true
+		}
+	}
+
+	if blocked {
+		return e.NewTooManyRequestsError(u.config.Window)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOneTimeTokenThrottleUseCase_Allow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+
+	config := usecase.OneTimeTokenThrottleConfig{EmailLimit: 3, IPLimit: 20, Window: time.Hour}
+
+	tests := []struct {
+		name          string
+		email         string
+		ip            string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:  "under the limits",
+			email: " John@Example.com ",
+			ip:    "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::email::john@example.com", time.Hour).Return(int64(3), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::ip::10.0.0.1", time.Hour).Return(int64(1), nil)
+			},
+		},
+		{
+			name:  "email over the limit",
+			email: "john@example.com",
+			ip:    "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::email::john@example.com", time.Hour).Return(int64(4), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::ip::10.0.0.1", time.Hour).Return(int64(4), nil)
+			},
+			expectedError: e.NewTooManyRequestsError(time.Hour),
+		},
+		{
+			name:  "ip over the limit",
+			email: "john@example.com",
+			ip:    "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::email::john@example.com", time.Hour).Return(int64(1), nil)
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::ip::10.0.0.1", time.Hour).Return(int64(21), nil)
+			},
+			expectedError: e.NewTooManyRequestsError(time.Hour),
+		},
+		{
+			name:  "unknown ip only counts the email",
+			email: "john@example.com",
+			setupMocks: func() {
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::email::john@example.com", time.Hour).Return(int64(1), nil)
+			},
+		},
+		{
+			name:  "cache error",
+			email: "john@example.com",
+			ip:    "10.0.0.1",
+			setupMocks: func() {
+				mockCache.EXPECT().Increment(gomock.Any(), "one_time_token_requests::magic_link::email::john@example.com", time.Hour).Return(int64(0), errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewOneTimeTokenThrottleUseCase(config, mockCache)
+			err := uc.Allow(context.Background(), domain.OneTimeTokenMagicLink, tt.email, tt.ip)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
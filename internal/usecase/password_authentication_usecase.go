@@ -2,7 +2,8 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/ydoro/wishlist/internal/domain"
 	e "github.com/ydoro/wishlist/internal/domain/errors"
@@ -12,52 +13,127 @@ import (
 type PasswordAuthenticationUseCase struct {
 	HashComparer domain.HashComparer
 	UserGetter   domain.GetCustomerByEmailRepository
-	Encrypter    domain.Encrypter
+	TokenIssuer  domain.TokenIssuer
+	MFA          domain.MFAChallenger
+	Throttler    domain.LoginThrottler
+	// outdated hashes are replaced while the plain password is at hand
+	RehashChecker   domain.RehashChecker
+	Hasher          domain.Hasher
+	PasswordUpdater domain.UpdateCustomerPasswordRepository
+	// Restorer reactivates accounts deleted during the grace period once the password and the second factor match
+	Restorer domain.AccountRestorer
 }
 
-func NewPasswordAuthenticationUseCase(comparer domain.HashComparer, userGetter domain.GetCustomerByEmailRepository, encrypter domain.Encrypter) *PasswordAuthenticationUseCase {
+func NewPasswordAuthenticationUseCase(comparer domain.HashComparer, userGetter domain.GetCustomerByEmailRepository, issuer domain.TokenIssuer, mfa domain.MFAChallenger, throttler domain.LoginThrottler, rehashChecker domain.RehashChecker, hasher domain.Hasher, passwordUpdater domain.UpdateCustomerPasswordRepository, restorer domain.AccountRestorer) *PasswordAuthenticationUseCase {
 	return &PasswordAuthenticationUseCase{
 		HashComparer: comparer,
 		UserGetter:   userGetter,
-		Encrypter:    encrypter,
+		TokenIssuer:  issuer,
+		MFA:          mfa,
+		Throttler:    throttler,
+
+		RehashChecker:   rehashChecker,
+		Hasher:          hasher,
+		PasswordUpdater: passwordUpdater,
+		Restorer:        restorer,
 	}
 }
 
-func (p *PasswordAuthenticationUseCase) Authenticate(ctx context.Context, credentials any) (string, error) {
+func (p *PasswordAuthenticationUseCase) Authenticate(ctx context.Context, credentials any) (*domain.AuthTokens, error) {
 	pwdAuth, ok := credentials.(inputs.PwdAuth)
 	if !ok {
-		return "", &e.ValidationError{
+		return nil, &e.ValidationError{
 			Field: "credentials",
 			Err:   "Invalid credentials type",
 		}
 	}
+	// blocked emails and IPs are rejected before spending a hash comparison on them
+	if err := p.Throttler.Check(ctx, pwdAuth.Email, pwdAuth.IP); err != nil {
+		return nil, err
+	}
+
 	// retrieve the user by email
 	user, err := p.UserGetter.GetByEmail(ctx, pwdAuth.Email)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	// a recently deleted account is only restored after the password check below
+	restoring := false
 	if user == nil {
-		return "", e.NewAuthenticationError(domain.AuthMethodPassword)
+		user, err = p.Restorer.GetRestorable(ctx, pwdAuth.Email)
+		if err != nil {
+			return nil, err
+		}
+		restoring = user != nil
+	}
+
+	// customers created through an identity provider have no password to compare against
+	if user == nil || user.Password == "" {
+		return nil, p.failure(ctx, pwdAuth)
 	}
 
 	// compare the password with the hash
 	err = p.HashComparer.Compare(user.Password, pwdAuth.Password)
 	if err != nil {
-		return "", e.NewAuthenticationError(domain.AuthMethodPassword)
+		return nil, p.failure(ctx, pwdAuth)
+	}
+
+	if err := p.Throttler.RegisterSuccess(ctx, pwdAuth.Email); err != nil {
+		return nil, err
+	}
+
+	p.rehashIfNeeded(ctx, user, pwdAuth.Password)
+
+	// customers with a second factor only get a short lived mfa token at this point,
+	// a deleted account is then restored once the second factor matched
+	pending, err := p.MFA.Challenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending != nil {
+		return pending, nil
+	}
+
+	if restoring {
+		if err := p.Restorer.Restore(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	// if the password matches, issue a short lived access token and a new refresh token
+	tokens, err := p.TokenIssuer.Issue(ctx, user, "", domain.ClientInfo{IP: pwdAuth.IP, UserAgent: pwdAuth.UserAgent})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// failure counts the failed attempt, unknown emails are counted too so they can't be told apart from wrong passwords
+func (p *PasswordAuthenticationUseCase) failure(ctx context.Context, pwdAuth inputs.PwdAuth) error {
+	if err := p.Throttler.RegisterFailure(ctx, pwdAuth.Email, pwdAuth.IP); err != nil {
+		return err
 	}
-	// if the password matches, sign a JWT token and return it
-	outgoing := &domain.OutgoingCustomer{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
+
+	return e.NewAuthenticationError(domain.AuthMethodPassword)
+}
+
+// rehashIfNeeded upgrades the stored hash to the current algorithm and parameters,
+// a failure only leaves the old hash in place so it never blocks the login
+func (p *PasswordAuthenticationUseCase) rehashIfNeeded(ctx context.Context, user *domain.Customer, password string) {
+	if !p.RehashChecker.NeedsRehash(user.Password) {
+		return
 	}
-	data, _ := json.Marshal(outgoing)
-	token, err := p.Encrypter.Encrypt(string(data))
 
+	hash, err := p.Hasher.Hash(password)
 	if err != nil {
-		return "", err
+		fmt.Printf("[password_authentication_usecase] ERROR rehashing password: %v\n", err)
+		return
 	}
 
-	return token, nil
+	if err := p.PasswordUpdater.UpdatePassword(ctx, user.ID, hash, time.Now()); err != nil {
+		fmt.Printf("[password_authentication_usecase] ERROR storing rehashed password: %v\n", err)
+	}
 }
@@ -2,7 +2,6 @@ package usecase_test
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -22,7 +21,13 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 
 	mockHashComparer := mocks.NewMockHashComparer(ctrl)
 	mockUserGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
-	mockEncrypter := mocks.NewMockEncrypter(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+	mockMFA := mocks.NewMockMFAChallenger(ctrl)
+	mockThrottler := mocks.NewMockLoginThrottler(ctrl)
+	mockRehashChecker := mocks.NewMockRehashChecker(ctrl)
+	mockHasher := mocks.NewMockHasher(ctrl)
+	mockPasswordUpdater := mocks.NewMockUpdateCustomerPasswordRepository(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
 
 	testUser := &domain.Customer{
 		ID:        "user123",
@@ -32,18 +37,22 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
-	expectedOutgoing := &domain.OutgoingCustomer{
-		ID:    testUser.ID,
-		Name:  testUser.Name,
-		Email: testUser.Email,
+	issuedTokens := &domain.AuthTokens{
+		AccessToken:  "valid.jwt.token",
+		RefreshToken: "refresh_token",
+		ExpiresAt:    time.Now().Add(15 * time.Minute),
+	}
+
+	pendingTokens := &domain.AuthTokens{
+		MFAToken:  "mfa_token",
+		ExpiresAt: time.Now().Add(5 * time.Minute),
 	}
-	expectedOutgoingJSON, _ := json.Marshal(expectedOutgoing)
 
 	tests := []struct {
 		name          string
 		credentials   any // Changed from inputs.PwdAuth to any to test invalid types
 		setupMocks    func()
-		expectedToken string
+		expectedToken *domain.AuthTokens
 		expectedError error
 	}{
 		{
@@ -51,8 +60,11 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 			credentials: inputs.PwdAuth{
 				Email:    "test@example.com",
 				Password: "correctPassword",
+				IP:       "10.0.0.1",
 			},
 			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "test@example.com", "10.0.0.1").Return(nil)
+
 				mockUserGetter.EXPECT().
 					GetByEmail(gomock.Any(), "test@example.com").
 					Return(testUser, nil)
@@ -61,25 +73,197 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 					Compare("hashedPassword", "correctPassword").
 					Return(nil)
 
-				mockEncrypter.EXPECT().
-					Encrypt(string(expectedOutgoingJSON)).
-					Return("valid.jwt.token", nil)
+				mockThrottler.EXPECT().RegisterSuccess(gomock.Any(), "test@example.com").Return(nil)
+
+				mockRehashChecker.EXPECT().NeedsRehash("hashedPassword").Return(false)
+
+				mockMFA.EXPECT().
+					Challenge(gomock.Any(), testUser).
+					Return(nil, nil)
+
+				mockIssuer.EXPECT().
+					Issue(gomock.Any(), testUser, "", domain.ClientInfo{IP: "10.0.0.1"}).
+					Return(issuedTokens, nil)
 			},
-			expectedToken: "valid.jwt.token",
+			expectedToken: issuedTokens,
 			expectedError: nil,
 		},
+		{
+			name: "outdated hash is upgraded",
+			credentials: inputs.PwdAuth{
+				Email:    "test@example.com",
+				Password: "correctPassword",
+				IP:       "10.0.0.1",
+			},
+			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "test@example.com", "10.0.0.1").Return(nil)
+
+				mockUserGetter.EXPECT().
+					GetByEmail(gomock.Any(), "test@example.com").
+					Return(testUser, nil)
+
+				mockHashComparer.EXPECT().
+					Compare("hashedPassword", "correctPassword").
+					Return(nil)
+
+				mockThrottler.EXPECT().RegisterSuccess(gomock.Any(), "test@example.com").Return(nil)
+
+				mockRehashChecker.EXPECT().NeedsRehash("hashedPassword").Return(true)
+				mockHasher.EXPECT().Hash("correctPassword").Return("$argon2id$newHash", nil)
+				mockPasswordUpdater.EXPECT().
+					UpdatePassword(gomock.Any(), "user123", "$argon2id$newHash", gomock.Any()).
+					Return(nil)
+
+				mockMFA.EXPECT().
+					Challenge(gomock.Any(), testUser).
+					Return(nil, nil)
+
+				mockIssuer.EXPECT().
+					Issue(gomock.Any(), testUser, "", domain.ClientInfo{IP: "10.0.0.1"}).
+					Return(issuedTokens, nil)
+			},
+			expectedToken: issuedTokens,
+			expectedError: nil,
+		},
+		{
+			name: "failing to store the upgraded hash does not block the login",
+			credentials: inputs.PwdAuth{
+				Email:    "test@example.com",
+				Password: "correctPassword",
+				IP:       "10.0.0.1",
+			},
+			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "test@example.com", "10.0.0.1").Return(nil)
+
+				mockUserGetter.EXPECT().
+					GetByEmail(gomock.Any(), "test@example.com").
+					Return(testUser, nil)
+
+				mockHashComparer.EXPECT().
+					Compare("hashedPassword", "correctPassword").
+					Return(nil)
+
+				mockThrottler.EXPECT().RegisterSuccess(gomock.Any(), "test@example.com").Return(nil)
+
+				mockRehashChecker.EXPECT().NeedsRehash("hashedPassword").Return(true)
+				mockHasher.EXPECT().Hash("correctPassword").Return("$argon2id$newHash", nil)
+				mockPasswordUpdater.EXPECT().
+					UpdatePassword(gomock.Any(), "user123", "$argon2id$newHash", gomock.Any()).
+					Return(errors.New("database error"))
+
+				mockMFA.EXPECT().
+					Challenge(gomock.Any(), testUser).
+					Return(nil, nil)
+
+				mockIssuer.EXPECT().
+					Issue(gomock.Any(), testUser, "", domain.ClientInfo{IP: "10.0.0.1"}).
+					Return(issuedTokens, nil)
+			},
+			expectedToken: issuedTokens,
+			expectedError: nil,
+		},
+		{
+			name: "deleted account inside the grace period is restored",
+			credentials: inputs.PwdAuth{
+				Email:    "deleted@example.com",
+				Password: "correctPassword",
+				IP:       "10.0.0.1",
+			},
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "user789", Email: "deleted@example.com", Password: "hashedPassword", DeletedAt: time.Now()}
+
+				mockThrottler.EXPECT().Check(gomock.Any(), "deleted@example.com", "10.0.0.1").Return(nil)
+				mockUserGetter.EXPECT().GetByEmail(gomock.Any(), "deleted@example.com").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "deleted@example.com").Return(deleted, nil)
+				mockHashComparer.EXPECT().Compare("hashedPassword", "correctPassword").Return(nil)
+				mockThrottler.EXPECT().RegisterSuccess(gomock.Any(), "deleted@example.com").Return(nil)
+				mockRehashChecker.EXPECT().NeedsRehash("hashedPassword").Return(false)
+				mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(nil, nil)
+				mockRestorer.EXPECT().Restore(gomock.Any(), deleted).Return(nil)
+				mockIssuer.EXPECT().
+					Issue(gomock.Any(), deleted, "", domain.ClientInfo{IP: "10.0.0.1"}).
+					Return(issuedTokens, nil)
+			},
+			expectedToken: issuedTokens,
+			expectedError: nil,
+		},
+		{
+			name: "deleted account with a second factor waits for it to be restored",
+			credentials: inputs.PwdAuth{
+				Email:    "deleted@example.com",
+				Password: "correctPassword",
+				IP:       "10.0.0.1",
+			},
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "user789", Email: "deleted@example.com", Password: "hashedPassword", DeletedAt: time.Now()}
+
+				mockThrottler.EXPECT().Check(gomock.Any(), "deleted@example.com", "10.0.0.1").Return(nil)
+				mockUserGetter.EXPECT().GetByEmail(gomock.Any(), "deleted@example.com").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "deleted@example.com").Return(deleted, nil)
+				mockHashComparer.EXPECT().Compare("hashedPassword", "correctPassword").Return(nil)
+				mockThrottler.EXPECT().RegisterSuccess(gomock.Any(), "deleted@example.com").Return(nil)
+				mockRehashChecker.EXPECT().NeedsRehash("hashedPassword").Return(false)
+				mockMFA.EXPECT().Challenge(gomock.Any(), deleted).Return(pendingTokens, nil)
+			},
+			expectedToken: pendingTokens,
+			expectedError: nil,
+		},
+		{
+			name: "deleted account is not restored with a wrong password",
+			credentials: inputs.PwdAuth{
+				Email:    "deleted@example.com",
+				Password: "wrongPassword",
+				IP:       "10.0.0.1",
+			},
+			setupMocks: func() {
+				deleted := &domain.Customer{ID: "user789", Email: "deleted@example.com", Password: "hashedPassword", DeletedAt: time.Now()}
+
+				mockThrottler.EXPECT().Check(gomock.Any(), "deleted@example.com", "10.0.0.1").Return(nil)
+				mockUserGetter.EXPECT().GetByEmail(gomock.Any(), "deleted@example.com").Return(nil, nil)
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "deleted@example.com").Return(deleted, nil)
+				mockHashComparer.EXPECT().Compare("hashedPassword", "wrongPassword").Return(errors.New("mismatch"))
+				mockThrottler.EXPECT().RegisterFailure(gomock.Any(), "deleted@example.com", "10.0.0.1").Return(nil)
+			},
+			expectedToken: nil,
+			expectedError: e.NewAuthenticationError(domain.AuthMethodPassword),
+		},
 		{
 			name: "user not found",
 			credentials: inputs.PwdAuth{
 				Email:    "nonexistent@example.com",
 				Password: "password",
+				IP:       "10.0.0.1",
 			},
 			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "nonexistent@example.com", "10.0.0.1").Return(nil)
+
 				mockUserGetter.EXPECT().
 					GetByEmail(gomock.Any(), "nonexistent@example.com").
 					Return(nil, nil)
+
+				mockRestorer.EXPECT().GetRestorable(gomock.Any(), "nonexistent@example.com").Return(nil, nil)
+
+				mockThrottler.EXPECT().RegisterFailure(gomock.Any(), "nonexistent@example.com", "10.0.0.1").Return(nil)
+			},
+			expectedToken: nil,
+			expectedError: e.NewAuthenticationError(domain.AuthMethodPassword),
+		},
+		{
+			name: "customer without password",
+			credentials: inputs.PwdAuth{
+				Email:    "social@example.com",
+				Password: "password",
+			},
+			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "social@example.com", "").Return(nil)
+
+				mockUserGetter.EXPECT().
+					GetByEmail(gomock.Any(), "social@example.com").
+					Return(&domain.Customer{ID: "user456", Email: "social@example.com"}, nil)
+
+				mockThrottler.EXPECT().RegisterFailure(gomock.Any(), "social@example.com", "").Return(nil)
 			},
-			expectedToken: "",
+			expectedToken: nil,
 			expectedError: e.NewAuthenticationError(domain.AuthMethodPassword),
 		},
 		{
@@ -89,11 +273,13 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 				Password: "password",
 			},
 			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "test@example.com", "").Return(nil)
+
 				mockUserGetter.EXPECT().
 					GetByEmail(gomock.Any(), "test@example.com").
 					Return(nil, errors.New("database error"))
 			},
-			expectedToken: "",
+			expectedToken: nil,
 			expectedError: errors.New("database error"),
 		},
 		{
@@ -101,8 +287,11 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 			credentials: inputs.PwdAuth{
 				Email:    "test@example.com",
 				Password: "wrongPassword",
+				IP:       "10.0.0.1",
 			},
 			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "test@example.com", "10.0.0.1").Return(nil)
+
 				mockUserGetter.EXPECT().
 					GetByEmail(gomock.Any(), "test@example.com").
 					Return(testUser, nil)
@@ -110,8 +299,10 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 				mockHashComparer.EXPECT().
 					Compare("hashedPassword", "wrongPassword").
 					Return(errors.New("hash comparison failed"))
+
+				mockThrottler.EXPECT().RegisterFailure(gomock.Any(), "test@example.com", "10.0.0.1").Return(nil)
 			},
-			expectedToken: "",
+			expectedToken: nil,
 			expectedError: e.NewAuthenticationError(domain.AuthMethodPassword),
 		},
 		{
@@ -119,8 +310,11 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 			credentials: inputs.PwdAuth{
 				Email:    "test@example.com",
 				Password: "correctPassword",
+				IP:       "10.0.0.1",
 			},
 			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "test@example.com", "10.0.0.1").Return(nil)
+
 				mockUserGetter.EXPECT().
 					GetByEmail(gomock.Any(), "test@example.com").
 					Return(testUser, nil)
@@ -129,18 +323,70 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 					Compare("hashedPassword", "correctPassword").
 					Return(nil)
 
-				mockEncrypter.EXPECT().
-					Encrypt(string(expectedOutgoingJSON)).
-					Return("", errors.New("encryption failed"))
+				mockThrottler.EXPECT().RegisterSuccess(gomock.Any(), "test@example.com").Return(nil)
+
+				mockRehashChecker.EXPECT().NeedsRehash("hashedPassword").Return(false)
+
+				mockMFA.EXPECT().
+					Challenge(gomock.Any(), testUser).
+					Return(nil, nil)
+
+				mockIssuer.EXPECT().
+					Issue(gomock.Any(), testUser, "", domain.ClientInfo{IP: "10.0.0.1"}).
+					Return(nil, errors.New("encryption failed"))
 			},
-			expectedToken: "",
+			expectedToken: nil,
 			expectedError: errors.New("encryption failed"),
 		},
+		{
+			name: "second factor required",
+			credentials: inputs.PwdAuth{
+				Email:    "test@example.com",
+				Password: "correctPassword",
+				IP:       "10.0.0.1",
+			},
+			setupMocks: func() {
+				mockThrottler.EXPECT().Check(gomock.Any(), "test@example.com", "10.0.0.1").Return(nil)
+
+				mockUserGetter.EXPECT().
+					GetByEmail(gomock.Any(), "test@example.com").
+					Return(testUser, nil)
+
+				mockHashComparer.EXPECT().
+					Compare("hashedPassword", "correctPassword").
+					Return(nil)
+
+				mockThrottler.EXPECT().RegisterSuccess(gomock.Any(), "test@example.com").Return(nil)
+
+				mockRehashChecker.EXPECT().NeedsRehash("hashedPassword").Return(false)
+
+				mockMFA.EXPECT().
+					Challenge(gomock.Any(), testUser).
+					Return(pendingTokens, nil)
+			},
+			expectedToken: pendingTokens,
+			expectedError: nil,
+		},
+		{
+			name: "locked out",
+			credentials: inputs.PwdAuth{
+				Email:    "test@example.com",
+				Password: "correctPassword",
+				IP:       "10.0.0.1",
+			},
+			setupMocks: func() {
+				mockThrottler.EXPECT().
+					Check(gomock.Any(), "test@example.com", "10.0.0.1").
+					Return(e.NewTooManyRequestsError(time.Minute))
+			},
+			expectedToken: nil,
+			expectedError: e.NewTooManyRequestsError(time.Minute),
+		},
 		{
 			name:          "invalid credentials type",
 			credentials:   struct{ foo string }{"bar"},
 			setupMocks:    func() {},
-			expectedToken: "",
+			expectedToken: nil,
 			expectedError: &e.ValidationError{
 				Field: "credentials",
 				Err:   "Invalid credentials type",
@@ -155,7 +401,13 @@ func TestPasswordAuthenticationUseCase_Authenticate(t *testing.T) {
 			useCase := usecase.NewPasswordAuthenticationUseCase(
 				mockHashComparer,
 				mockUserGetter,
-				mockEncrypter,
+				mockIssuer,
+				mockMFA,
+				mockThrottler,
+				mockRehashChecker,
+				mockHasher,
+				mockPasswordUpdater,
+				mockRestorer,
 			)
 
 			token, err := useCase.Authenticate(context.Background(), tt.credentials)
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+var (
+	// localePattern accepts a language with an optional region, like en, pt-BR or es-419
+	localePattern   = regexp.MustCompile(`^[a-z]{2,3}(-([A-Z]{2}|[0-9]{3}))?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+type PreferencesUseCase struct {
+	customerGetter domain.GetCustomerByIDRepository
+	getter         domain.GetPreferencesRepository
+	saver          domain.SavePreferencesRepository
+	accessPolicy   domain.Policy
+}
+
+func NewPreferencesUseCase(
+	customerGetter domain.GetCustomerByIDRepository,
+	getter domain.GetPreferencesRepository,
+	saver domain.SavePreferencesRepository,
+	accessPolicy domain.Policy,
+) *PreferencesUseCase {
+	return &PreferencesUseCase{
+		customerGetter: customerGetter,
+		getter:         getter,
+		saver:          saver,
+		accessPolicy:   accessPolicy,
+	}
+}
+
+func (u *PreferencesUseCase) GetPreferences(ctx context.Context, currentCustomerID string, customerID string) (*domain.Preferences, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerRead, customerID); err != nil {
+		return nil, err
+	}
+
+	if err := u.ensureCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	return u.GetCustomerPreferences(ctx, customerID)
+}
+
+func (u *PreferencesUseCase) UpdatePreferences(ctx context.Context, currentCustomerID string, customerID string, data domain.PreferencesUpdate) (*domain.Preferences, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerWrite, customerID); err != nil {
+		return nil, err
+	}
+
+	if err := u.ensureCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	preferences := &domain.Preferences{
+		CustomerID:         customerID,
+		Locale:             strings.TrimSpace(data.Locale),
+		Currency:           strings.ToUpper(strings.TrimSpace(data.Currency)),
+		WishlistVisibility: data.WishlistVisibility,
+		Notifications:      data.Notifications,
+		RevealReservations: data.RevealReservations,
+		UpdatedAt:          time.Now(),
+	}
+
+	if err := validatePreferences(preferences); err != nil {
+		return nil, err
+	}
+
+	if err := u.saver.Save(ctx, preferences); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (u *PreferencesUseCase) GetCustomerPreferences(ctx context.Context, customerID string) (*domain.Preferences, error) {
+	preferences, err := u.getter.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if preferences == nil {
+		return domain.DefaultPreferences(customerID), nil
+	}
+
+	return preferences, nil
+}
+
+func (u *PreferencesUseCase) ensureCustomer(ctx context.Context, customerID string) error {
+	customer, err := u.customerGetter.GetByID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		return e.NewNotFoundError("customer")
+	}
+
+	return nil
+}
+
+func validatePreferences(preferences *domain.Preferences) error {
+	var errs []*e.ValidationError
+
+	if !localePattern.MatchString(preferences.Locale) {
+		errs = append(errs, &e.ValidationError{Field: "locale", Err: "must be a language tag like en or pt-BR"})
+	}
+
+	if !currencyPattern.MatchString(preferences.Currency) {
+		errs = append(errs, &e.ValidationError{Field: "currency", Err: "must be an ISO 4217 code like USD"})
+	}
+
+	switch preferences.WishlistVisibility {
+	case domain.WishlistVisibilityPrivate, domain.WishlistVisibilityShared:
+	default:
+		errs = append(errs, &e.ValidationError{Field: "wishlist_visibility", Err: "must be either private or shared"})
+	}
+
+	return e.NewValidationErrors(errs)
+}
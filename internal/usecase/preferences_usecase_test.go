@@ -0,0 +1,157 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPreferencesUseCase_GetPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockGetter := mocks.NewMockGetPreferencesRepository(ctrl)
+
+	saved := &domain.Preferences{CustomerID: "customer_123", Locale: "pt-BR", Currency: "BRL", WishlistVisibility: domain.WishlistVisibilityShared}
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		setupMocks        func()
+		expected          *domain.Preferences
+		expectedError     error
+	}{
+		{
+			name:              "saved preferences",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(&domain.Customer{ID: "customer_123"}, nil)
+				mockGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(saved, nil)
+			},
+			expected: saved,
+		},
+		{
+			name:              "defaults",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(&domain.Customer{ID: "customer_123"}, nil)
+				mockGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expected: domain.DefaultPreferences("customer_123"),
+		},
+		{
+			name:              "another customer",
+			currentCustomerID: "other_123",
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewPreferencesUseCase(mockCustomerGetter, mockGetter, nil, ownerOnlyPolicy(ctrl))
+			result, err := uc.GetPreferences(context.Background(), tt.currentCustomerID, "customer_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestPreferencesUseCase_UpdatePreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockSaver := mocks.NewMockSavePreferencesRepository(ctrl)
+
+	tests := []struct {
+		name          string
+		data          domain.PreferencesUpdate
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "saves normalized preferences",
+			data: domain.PreferencesUpdate{
+				Locale:             "pt-BR",
+				Currency:           " brl ",
+				WishlistVisibility: domain.WishlistVisibilityShared,
+				Notifications:      domain.NotificationSettings{PriceDrops: true},
+				RevealReservations: true,
+			},
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(&domain.Customer{ID: "customer_123"}, nil)
+				mockSaver.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, preferences *domain.Preferences) error {
+						assert.Equal(t, "customer_123", preferences.CustomerID)
+						assert.Equal(t, "BRL", preferences.Currency)
+						assert.True(t, preferences.Notifications.PriceDrops)
+						assert.True(t, preferences.RevealReservations)
+						assert.False(t, preferences.UpdatedAt.IsZero())
+						return nil
+					})
+			},
+		},
+		{
+			name: "every invalid preference is reported",
+			data: domain.PreferencesUpdate{Locale: "portuguese", Currency: "R$", WishlistVisibility: "public"},
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(&domain.Customer{ID: "customer_123"}, nil)
+			},
+			expectedError: e.ValidationErrors{
+				{Field: "locale", Err: "must be a language tag like en or pt-BR"},
+				{Field: "currency", Err: "must be an ISO 4217 code like USD"},
+				{Field: "wishlist_visibility", Err: "must be either private or shared"},
+			},
+		},
+		{
+			name: "customer not found",
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("customer"),
+		},
+		{
+			name: "repository error",
+			data: domain.PreferencesUpdate{Locale: "en", Currency: "USD", WishlistVisibility: domain.WishlistVisibilityPrivate},
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(&domain.Customer{ID: "customer_123"}, nil)
+				mockSaver.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewPreferencesUseCase(mockCustomerGetter, nil, mockSaver, ownerOnlyPolicy(ctrl))
+			result, err := uc.UpdatePreferences(context.Background(), "customer_123", "customer_123", tt.data)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+		})
+	}
+}
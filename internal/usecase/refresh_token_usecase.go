@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type RefreshTokenUseCase struct {
+	tokenHasher    domain.Hasher
+	tokenGetter    domain.RefreshTokenByHashRepository
+	tokenMarker    domain.MarkRefreshTokenUsedRepository
+	revoker        domain.TokenRevoker
+	customerGetter domain.GetCustomerByIDRepository
+	issuer         domain.TokenIssuer
+}
+
+func NewRefreshTokenUseCase(
+	tokenHasher domain.Hasher,
+	tokenGetter domain.RefreshTokenByHashRepository,
+	tokenMarker domain.MarkRefreshTokenUsedRepository,
+	revoker domain.TokenRevoker,
+	customerGetter domain.GetCustomerByIDRepository,
+	issuer domain.TokenIssuer,
+) *RefreshTokenUseCase {
+	return &RefreshTokenUseCase{
+		tokenHasher:    tokenHasher,
+		tokenGetter:    tokenGetter,
+		tokenMarker:    tokenMarker,
+		revoker:        revoker,
+		customerGetter: customerGetter,
+		issuer:         issuer,
+	}
+}
+
+// Refresh rotates the given refresh token, presenting a token that was already rotated
+// is treated as a theft and revokes its whole session, access tokens included
+func (u *RefreshTokenUseCase) Refresh(ctx context.Context, refreshToken string) (*domain.AuthTokens, error) {
+	if refreshToken == "" {
+		return nil, e.NewRequiredFieldError("refresh_token")
+	}
+
+	hash, err := u.tokenHasher.Hash(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := u.tokenGetter.GetByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored == nil || !stored.RevokedAt.IsZero() {
+		return nil, e.NewAuthenticationError(domain.AuthMethodRefreshToken)
+	}
+
+	now := time.Now()
+	if !stored.UsedAt.IsZero() {
+		return nil, u.revokeReusedSession(ctx, stored)
+	}
+
+	if now.After(stored.ExpiresAt) {
+		return nil, e.NewAuthenticationError(domain.AuthMethodRefreshToken)
+	}
+
+	rotated, err := u.tokenMarker.MarkUsed(ctx, stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// another request rotated this token between our read and write
+	if !rotated {
+		return nil, u.revokeReusedSession(ctx, stored)
+	}
+
+	customer, err := u.customerGetter.GetByID(ctx, stored.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer == nil {
+		return nil, e.NewAuthenticationError(domain.AuthMethodRefreshToken)
+	}
+
+	return u.issuer.Issue(ctx, customer, stored.FamilyID, domain.ClientInfo{})
+}
+
+// revokeReusedSession revokes the refresh token family and the access tokens already issued
+// from it, the family ID is the session ID carried by those access tokens
+func (u *RefreshTokenUseCase) revokeReusedSession(ctx context.Context, token *domain.RefreshToken) error {
+	fmt.Printf("[refresh_token_usecase] refresh token reuse detected for customer %s, revoking session %s\n", token.CustomerID, token.FamilyID)
+
+	if err := u.revoker.RevokeSession(ctx, token.FamilyID); err != nil {
+		return err
+	}
+
+	return e.NewAuthenticationError(domain.AuthMethodRefreshToken)
+}
@@ -0,0 +1,163 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRefreshTokenUseCase_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHasher := mocks.NewMockHasher(ctrl)
+	mockGetter := mocks.NewMockRefreshTokenByHashRepository(ctrl)
+	mockMarker := mocks.NewMockMarkRefreshTokenUsedRepository(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockIssuer := mocks.NewMockTokenIssuer(ctrl)
+
+	customer := &domain.Customer{ID: "customer_123", Name: "Test User", Email: "test@example.com"}
+	newTokens := &domain.AuthTokens{AccessToken: "new.access.token", RefreshToken: "new_refresh_token"}
+
+	getStoredToken := func() *domain.RefreshToken {
+		return &domain.RefreshToken{
+			ID:         "refresh_123",
+			CustomerID: "customer_123",
+			FamilyID:   "family_123",
+			TokenHash:  "refresh_hash",
+			CreatedAt:  time.Now().Add(-time.Hour),
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+	}
+
+	authErr := e.NewAuthenticationError(domain.AuthMethodRefreshToken)
+
+	tests := []struct {
+		name           string
+		refreshToken   string
+		setupMocks     func()
+		expectedTokens *domain.AuthTokens
+		expectedError  error
+	}{
+		{
+			name:         "successful rotation",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(getStoredToken(), nil)
+				mockMarker.EXPECT().MarkUsed(gomock.Any(), "refresh_123", gomock.Any()).Return(true, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockIssuer.EXPECT().Issue(gomock.Any(), customer, "family_123", domain.ClientInfo{}).Return(newTokens, nil)
+			},
+			expectedTokens: newTokens,
+		},
+		{
+			name:          "empty token",
+			refreshToken:  "",
+			setupMocks:    func() {},
+			expectedError: e.NewRequiredFieldError("refresh_token"),
+		},
+		{
+			name:         "unknown token",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(nil, nil)
+			},
+			expectedError: authErr,
+		},
+		{
+			name:         "revoked token",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				stored := getStoredToken()
+				stored.RevokedAt = time.Now()
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(stored, nil)
+			},
+			expectedError: authErr,
+		},
+		{
+			name:         "expired token",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				stored := getStoredToken()
+				stored.ExpiresAt = time.Now().Add(-time.Minute)
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(stored, nil)
+			},
+			expectedError: authErr,
+		},
+		{
+			name:         "reused token revokes the session",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				stored := getStoredToken()
+				stored.UsedAt = time.Now().Add(-time.Minute)
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(stored, nil)
+				mockRevoker.EXPECT().RevokeSession(gomock.Any(), "family_123").Return(nil)
+			},
+			expectedError: authErr,
+		},
+		{
+			name:         "concurrent rotation revokes the session",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(getStoredToken(), nil)
+				mockMarker.EXPECT().MarkUsed(gomock.Any(), "refresh_123", gomock.Any()).Return(false, nil)
+				mockRevoker.EXPECT().RevokeSession(gomock.Any(), "family_123").Return(nil)
+			},
+			expectedError: authErr,
+		},
+		{
+			name:         "customer no longer exists",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(getStoredToken(), nil)
+				mockMarker.EXPECT().MarkUsed(gomock.Any(), "refresh_123", gomock.Any()).Return(true, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: authErr,
+		},
+		{
+			name:         "repository error",
+			refreshToken: "refresh_token",
+			setupMocks: func() {
+				mockHasher.EXPECT().Hash("refresh_token").Return("refresh_hash", nil)
+				mockGetter.EXPECT().GetByHash(gomock.Any(), "refresh_hash").Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewRefreshTokenUseCase(mockHasher, mockGetter, mockMarker, mockRevoker, mockCustomerGetter, mockIssuer)
+			tokens, err := uc.Refresh(context.Background(), tt.refreshToken)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+				assert.Nil(t, tokens)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTokens, tokens)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type RequestPasswordResetUseCase struct {
+	tokenTTL       time.Duration
+	resetURL       string
+	throttler      domain.OneTimeTokenRequestThrottler
+	runner         domain.BackgroundRunner
+	customerGetter domain.GetCustomerByEmailRepository
+	idGen          domain.IDGenerator
+	tokenGen       domain.TokenGenerator
+	tokenHasher    domain.Hasher
+	tokenStorer    domain.OneTimeTokenCreationRepository
+	mailer         domain.Mailer
+}
+
+func NewRequestPasswordResetUseCase(
+	tokenTTL time.Duration,
+	resetURL string,
+	throttler domain.OneTimeTokenRequestThrottler,
+	runner domain.BackgroundRunner,
+	customerGetter domain.GetCustomerByEmailRepository,
+	idGen domain.IDGenerator,
+	tokenGen domain.TokenGenerator,
+	tokenHasher domain.Hasher,
+	tokenStorer domain.OneTimeTokenCreationRepository,
+	mailer domain.Mailer,
+) *RequestPasswordResetUseCase {
+	return &RequestPasswordResetUseCase{
+		tokenTTL:       tokenTTL,
+		resetURL:       resetURL,
+		throttler:      throttler,
+		runner:         runner,
+		customerGetter: customerGetter,
+		idGen:          idGen,
+		tokenGen:       tokenGen,
+		tokenHasher:    tokenHasher,
+		tokenStorer:    tokenStorer,
+		mailer:         mailer,
+	}
+}
+
+// RequestPasswordReset emails a reset link to the customer. Everything past the throttle runs in the
+// background, so neither the response nor its timing tells whether the email is registered
+func (u *RequestPasswordResetUseCase) RequestPasswordReset(ctx context.Context, email string, ip string) error {
+	if err := u.throttler.Allow(ctx, domain.OneTimeTokenPasswordReset, email, ip); err != nil {
+		return err
+	}
+
+	u.runner.Go(func(ctx context.Context) {
+		if err := u.sendResetLink(ctx, email); err != nil {
+			fmt.Printf("[request_password_reset_usecase] ERROR sending reset email: %v\n", err)
+		}
+	})
+
+	return nil
+}
+
+func (u *RequestPasswordResetUseCase) sendResetLink(ctx context.Context, email string) error {
+	customer, err := u.customerGetter.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		return nil
+	}
+
+	token, err := u.tokenGen.Generate()
+	if err != nil {
+		return errors.Join(err, errors.New("failed to generate reset token"))
+	}
+
+	hash, err := u.tokenHasher.Hash(token)
+	if err != nil {
+		return errors.Join(err, errors.New("failed to hash reset token"))
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return errors.Join(err, errors.New("failed to generate reset token ID"))
+	}
+
+	now := time.Now()
+	err = u.tokenStorer.Create(ctx, &domain.OneTimeToken{
+		ID:         id,
+		CustomerID: customer.ID,
+		Purpose:    domain.OneTimeTokenPasswordReset,
+		TokenHash:  hash,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(u.tokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, domain.MailMessage{
+		To:      customer.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password, it expires in %s and can be used only once:\n\n%s?token=%s\n\nIf you did not ask for it you can ignore this email.",
+			customer.Name,
+			u.tokenTTL,
+			u.resetURL,
+			token,
+		),
+	})
+}
@@ -0,0 +1,120 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequestPasswordResetUseCase_RequestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockThrottler := mocks.NewMockOneTimeTokenRequestThrottler(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockHasher := mocks.NewMockHasher(ctrl)
+	mockStorer := mocks.NewMockOneTimeTokenCreationRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	customer := &domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com"}
+
+	tests := []struct {
+		name          string
+		email         string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:  "sends the reset link",
+			email: "john@example.com",
+			setupMocks: func() {
+				mockThrottler.EXPECT().Allow(gomock.Any(), domain.OneTimeTokenPasswordReset, "john@example.com", "10.0.0.1").Return(nil)
+				mockCustomerGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(customer, nil)
+				mockTokenGen.EXPECT().Generate().Return("reset_token", nil)
+				mockHasher.EXPECT().Hash("reset_token").Return("reset_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("token_123", nil)
+				mockStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, token *domain.OneTimeToken) error {
+						assert.Equal(t, "customer_123", token.CustomerID)
+						assert.Equal(t, domain.OneTimeTokenPasswordReset, token.Purpose)
+						assert.Equal(t, "reset_hash", token.TokenHash)
+						assert.WithinDuration(t, token.CreatedAt.Add(30*time.Minute), token.ExpiresAt, time.Second)
+						return nil
+					})
+				mockMailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, msg domain.MailMessage) error {
+						assert.Equal(t, "john@example.com", msg.To)
+						assert.Contains(t, msg.Body, "https://wishlist.local/reset-password?token=reset_token")
+						return nil
+					})
+			},
+		},
+		{
+			name:  "unknown email does not fail",
+			email: "ghost@example.com",
+			setupMocks: func() {
+				mockThrottler.EXPECT().Allow(gomock.Any(), domain.OneTimeTokenPasswordReset, "ghost@example.com", "10.0.0.1").Return(nil)
+				mockCustomerGetter.EXPECT().GetByEmail(gomock.Any(), "ghost@example.com").Return(nil, nil)
+			},
+		},
+		{
+			name:  "mail delivery failure is not exposed",
+			email: "john@example.com",
+			setupMocks: func() {
+				mockThrottler.EXPECT().Allow(gomock.Any(), domain.OneTimeTokenPasswordReset, "john@example.com", "10.0.0.1").Return(nil)
+				mockCustomerGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(customer, nil)
+				mockTokenGen.EXPECT().Generate().Return("reset_token", nil)
+				mockHasher.EXPECT().Hash("reset_token").Return("reset_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("token_123", nil)
+				mockStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+			},
+		},
+		{
+			name:  "lookup failure is not exposed",
+			email: "john@example.com",
+			setupMocks: func() {
+				mockThrottler.EXPECT().Allow(gomock.Any(), domain.OneTimeTokenPasswordReset, "john@example.com", "10.0.0.1").Return(nil)
+				mockCustomerGetter.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(nil, errors.New("database error"))
+			},
+		},
+		{
+			name:  "throttled requests do nothing",
+			email: "john@example.com",
+			setupMocks: func() {
+				mockThrottler.EXPECT().
+					Allow(gomock.Any(), domain.OneTimeTokenPasswordReset, "john@example.com", "10.0.0.1").
+					Return(e.NewTooManyRequestsError(time.Hour))
+			},
+			expectedError: e.NewTooManyRequestsError(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewRequestPasswordResetUseCase(30*time.Minute, "https://wishlist.local/reset-password", mockThrottler, inlineRunner(ctrl), mockCustomerGetter, mockIDGen, mockTokenGen, mockHasher, mockStorer, mockMailer)
+			err := uc.RequestPasswordReset(context.Background(), tt.email, "10.0.0.1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
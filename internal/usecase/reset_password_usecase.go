@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type ResetPasswordUseCase struct {
+	tokenHasher      domain.Hasher
+	tokenGetter      domain.ActiveOneTimeTokenRepository
+	tokenConsumer    domain.ConsumeOneTimeTokenRepository
+	tokenInvalidator domain.InvalidateOneTimeTokensRepository
+	customerGetter   domain.GetCustomerByIDRepository
+	policy           domain.PasswordPolicy
+	hasher           domain.Hasher
+	passwordUpdater  domain.UpdateCustomerPasswordRepository
+	revoker          domain.TokenRevoker
+	unlocker         domain.AccountUnlocker
+}
+
+func NewResetPasswordUseCase(
+	tokenHasher domain.Hasher,
+	tokenGetter domain.ActiveOneTimeTokenRepository,
+	tokenConsumer domain.ConsumeOneTimeTokenRepository,
+	tokenInvalidator domain.InvalidateOneTimeTokensRepository,
+	customerGetter domain.GetCustomerByIDRepository,
+	policy domain.PasswordPolicy,
+	hasher domain.Hasher,
+	passwordUpdater domain.UpdateCustomerPasswordRepository,
+	revoker domain.TokenRevoker,
+	unlocker domain.AccountUnlocker,
+) *ResetPasswordUseCase {
+	return &ResetPasswordUseCase{
+		tokenHasher:      tokenHasher,
+		tokenGetter:      tokenGetter,
+		tokenConsumer:    tokenConsumer,
+		tokenInvalidator: tokenInvalidator,
+		customerGetter:   customerGetter,
+		policy:           policy,
+		hasher:           hasher,
+		passwordUpdater:  passwordUpdater,
+		revoker:          revoker,
+		unlocker:         unlocker,
+	}
+}
+
+func invalidResetTokenError() error {
+	return &e.ValidationError{Field: "token", Err: "is invalid or expired"}
+}
+
+func (u *ResetPasswordUseCase) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	if token == "" {
+		return e.NewRequiredFieldError("token")
+	}
+
+	tokenHash, err := u.tokenHasher.Hash(token)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	active, err := u.tokenGetter.GetActive(ctx, domain.OneTimeTokenPasswordReset, tokenHash, now)
+	if err != nil {
+		return err
+	}
+
+	if active == nil {
+		return invalidResetTokenError()
+	}
+
+	customer, err := u.customerGetter.GetByID(ctx, active.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		return invalidResetTokenError()
+	}
+
+	// the policy runs before consuming the token so a rejected password does not burn the link
+	if err := u.policy.Validate(newPassword, customer); err != nil {
+		return err
+	}
+
+	consumed, err := u.tokenConsumer.Consume(ctx, domain.OneTimeTokenPasswordReset, tokenHash, now)
+	if err != nil {
+		return err
+	}
+
+	if consumed == nil {
+		return invalidResetTokenError()
+	}
+
+	hash, err := u.hasher.Hash(newPassword)
+	if err != nil {
+		return errors.Join(err, errors.New("failed to hash password"))
+	}
+
+	if err := u.passwordUpdater.UpdatePassword(ctx, customer.ID, hash, now); err != nil {
+		return err
+	}
+
+	if err := u.tokenInvalidator.InvalidateForCustomer(ctx, customer.ID, domain.OneTimeTokenPasswordReset, now); err != nil {
+		return err
+	}
+
+	if err := u.revoker.RevokeAllForCustomer(ctx, customer.ID); err != nil {
+		return err
+	}
+
+	// proving access to the mailbox is enough to lift a login lockout early
+	return u.unlocker.Unlock(ctx, customer.Email)
+}
@@ -0,0 +1,144 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResetPasswordUseCase_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockTokenGetter := mocks.NewMockActiveOneTimeTokenRepository(ctrl)
+	mockTokenConsumer := mocks.NewMockConsumeOneTimeTokenRepository(ctrl)
+	mockTokenInvalidator := mocks.NewMockInvalidateOneTimeTokensRepository(ctrl)
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockPolicy := mocks.NewMockPasswordPolicy(ctrl)
+	mockHasher := mocks.NewMockHasher(ctrl)
+	mockPasswordUpdater := mocks.NewMockUpdateCustomerPasswordRepository(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+	mockUnlocker := mocks.NewMockAccountUnlocker(ctrl)
+
+	customer := &domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com"}
+	resetToken := &domain.OneTimeToken{ID: "token_123", CustomerID: "customer_123", Purpose: domain.OneTimeTokenPasswordReset}
+	invalidToken := &e.ValidationError{Field: "token", Err: "is invalid or expired"}
+
+	tests := []struct {
+		name          string
+		token         string
+		password      string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:     "successful reset",
+			token:    "reset_token",
+			password: "new-password",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("reset_token").Return("reset_hash", nil)
+				mockTokenGetter.EXPECT().GetActive(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(resetToken, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockPolicy.EXPECT().Validate("new-password", customer).Return(nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(resetToken, nil)
+				mockHasher.EXPECT().Hash("new-password").Return("new_hash", nil)
+				mockPasswordUpdater.EXPECT().UpdatePassword(gomock.Any(), "customer_123", "new_hash", gomock.Any()).Return(nil)
+				mockTokenInvalidator.EXPECT().InvalidateForCustomer(gomock.Any(), "customer_123", domain.OneTimeTokenPasswordReset, gomock.Any()).Return(nil)
+				mockRevoker.EXPECT().RevokeAllForCustomer(gomock.Any(), "customer_123").Return(nil)
+				mockUnlocker.EXPECT().Unlock(gomock.Any(), "john@example.com").Return(nil)
+			},
+		},
+		{
+			name:          "missing token",
+			token:         "",
+			password:      "new-password",
+			setupMocks:    func() {},
+			expectedError: e.NewRequiredFieldError("token"),
+		},
+		{
+			name:     "unknown or expired token",
+			token:    "reset_token",
+			password: "new-password",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("reset_token").Return("reset_hash", nil)
+				mockTokenGetter.EXPECT().GetActive(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(nil, nil)
+			},
+			expectedError: invalidToken,
+		},
+		{
+			name:     "weak password keeps the token usable",
+			token:    "reset_token",
+			password: "weak",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("reset_token").Return("reset_hash", nil)
+				mockTokenGetter.EXPECT().GetActive(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(resetToken, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockPolicy.EXPECT().Validate("weak", customer).Return(&e.ValidationError{Field: "password", Err: "must have at least 8 characters"})
+			},
+			expectedError: &e.ValidationError{Field: "password", Err: "must have at least 8 characters"},
+		},
+		{
+			name:     "token consumed concurrently",
+			token:    "reset_token",
+			password: "new-password",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("reset_token").Return("reset_hash", nil)
+				mockTokenGetter.EXPECT().GetActive(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(resetToken, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockPolicy.EXPECT().Validate("new-password", customer).Return(nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(nil, nil)
+			},
+			expectedError: invalidToken,
+		},
+		{
+			name:     "password update error",
+			token:    "reset_token",
+			password: "new-password",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("reset_token").Return("reset_hash", nil)
+				mockTokenGetter.EXPECT().GetActive(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(resetToken, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(customer, nil)
+				mockPolicy.EXPECT().Validate("new-password", customer).Return(nil)
+				mockTokenConsumer.EXPECT().Consume(gomock.Any(), domain.OneTimeTokenPasswordReset, "reset_hash", gomock.Any()).Return(resetToken, nil)
+				mockHasher.EXPECT().Hash("new-password").Return("new_hash", nil)
+				mockPasswordUpdater.EXPECT().UpdatePassword(gomock.Any(), "customer_123", "new_hash", gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewResetPasswordUseCase(
+				mockTokenHasher,
+				mockTokenGetter,
+				mockTokenConsumer,
+				mockTokenInvalidator,
+				mockCustomerGetter,
+				mockPolicy,
+				mockHasher,
+				mockPasswordUpdater,
+				mockRevoker,
+				mockUnlocker,
+			)
+			err := uc.ResetPassword(context.Background(), tt.token, tt.password)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
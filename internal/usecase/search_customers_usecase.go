@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+const (
+	defaultCustomerPageSize = 20
+	maxCustomerPageSize     = 100
+)
+
+type SearchCustomersUseCase struct {
+	searcher     domain.SearchCustomersRepository
+	accessPolicy domain.Policy
+}
+
+func NewSearchCustomersUseCase(
+	searcher domain.SearchCustomersRepository,
+	accessPolicy domain.Policy,
+) *SearchCustomersUseCase {
+	return &SearchCustomersUseCase{
+		searcher:     searcher,
+		accessPolicy: accessPolicy,
+	}
+}
+
+// SearchCustomers is restricted to roles granted PermissionCustomerSearch, the results are not owned
+// by a single customer so every search is audited without an owner
+func (u *SearchCustomersUseCase) SearchCustomers(ctx context.Context, currentCustomerID string, params domain.CustomerSearchParams) (*domain.CustomerPage, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerSearch, ""); err != nil {
+		return nil, err
+	}
+
+	search, err := u.parseSearch(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// one extra row tells whether there is a next page
+	search.Limit++
+	customers, err := u.searcher.Search(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.CustomerPage{Customers: customers}
+	if len(customers) == search.Limit {
+		page.Customers = customers[:search.Limit-1]
+		last := page.Customers[len(page.Customers)-1]
+		page.NextCursor = encodeCustomerCursor(domain.CustomerCursor{Value: search.Sort.Value(last), ID: last.ID})
+	}
+
+	return page, nil
+}
+
+// parseSearch reports every invalid parameter at once
+func (u *SearchCustomersUseCase) parseSearch(params domain.CustomerSearchParams) (domain.CustomerSearch, error) {
+	search := domain.CustomerSearch{
+		Query:         strings.ToLower(strings.TrimSpace(params.Query)),
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Status:        domain.CustomerStatusActive,
+		Sort:          domain.CustomerSortCreatedAt,
+		Limit:         defaultCustomerPageSize,
+	}
+
+	var errs []*e.ValidationError
+
+	switch status := domain.CustomerStatus(params.Status); status {
+	case "":
+	case domain.CustomerStatusActive, domain.CustomerStatusDeleted, domain.CustomerStatusAll:
+		search.Status = status
+	default:
+		errs = append(errs, &e.ValidationError{Field: "status", Err: "must be one of active, deleted or all"})
+	}
+
+	switch sort := domain.CustomerSort(params.Sort); sort {
+	case "":
+	case domain.CustomerSortCreatedAt, domain.CustomerSortName, domain.CustomerSortEmail:
+		search.Sort = sort
+	default:
+		errs = append(errs, &e.ValidationError{Field: "sort", Err: "must be one of created_at, name or email"})
+	}
+
+	switch params.Order {
+	case "", "asc":
+	case "desc":
+		search.Descending = true
+	default:
+		errs = append(errs, &e.ValidationError{Field: "order", Err: "must be either asc or desc"})
+	}
+
+	if params.Limit != 0 {
+		if params.Limit < 0 || params.Limit > maxCustomerPageSize {
+			errs = append(errs, &e.ValidationError{Field: "limit", Err: "must be between 1 and 100"})
+		}
+		search.Limit = params.Limit
+	}
+
+	if !search.CreatedAfter.IsZero() && !search.CreatedBefore.IsZero() && !search.CreatedAfter.Before(search.CreatedBefore) {
+		errs = append(errs, &e.ValidationError{Field: "created_after", Err: "must be before created_before"})
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeCustomerCursor(params.Cursor, search.Sort)
+		if err != nil {
+			errs = append(errs, &e.ValidationError{Field: "cursor", Err: "is not valid"})
+		}
+		search.After = cursor
+	}
+
+	return search, e.NewValidationErrors(errs)
+}
+
+// cursors are opaque to clients, they must be sent back with the same sort and order
+func encodeCustomerCursor(cursor domain.CustomerCursor) string {
+	value, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func decodeCustomerCursor(value string, sort domain.CustomerSort) (*domain.CustomerCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := &domain.CustomerCursor{}
+	if err := json.Unmarshal(raw, cursor); err != nil {
+		return nil, err
+	}
+
+	if sort == domain.CustomerSortCreatedAt {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,128 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchCustomersUseCase_SearchCustomers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := mocks.NewMockSearchCustomersRepository(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+
+	alice := &domain.Customer{ID: "customer_1", Name: "Alice"}
+	bob := &domain.Customer{ID: "customer_2", Name: "Bob"}
+
+	tests := []struct {
+		name          string
+		params        domain.CustomerSearchParams
+		setupMocks    func()
+		expected      *domain.CustomerPage
+		expectedError error
+	}{
+		{
+			name:   "first page with a next cursor",
+			params: domain.CustomerSearchParams{Query: " AL ", Sort: "name", Order: "desc", Limit: 1},
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerSearch, "").Return(nil)
+				mockSearcher.EXPECT().
+					Search(gomock.Any(), domain.CustomerSearch{
+						Query:      "al",
+						Status:     domain.CustomerStatusActive,
+						Sort:       domain.CustomerSortName,
+						Descending: true,
+						Limit:      2,
+					}).
+					Return([]*domain.Customer{alice, bob}, nil)
+			},
+			expected: &domain.CustomerPage{
+				Customers: []*domain.Customer{alice},
+				// {"v":"Alice","id":"customer_1"}
+				NextCursor: "eyJ2IjoiQWxpY2UiLCJpZCI6ImN1c3RvbWVyXzEifQ",
+			},
+		},
+		{
+			name:   "last page",
+			params: domain.CustomerSearchParams{Sort: "name", Cursor: "eyJ2IjoiQWxpY2UiLCJpZCI6ImN1c3RvbWVyXzEifQ", Status: "all"},
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerSearch, "").Return(nil)
+				mockSearcher.EXPECT().
+					Search(gomock.Any(), domain.CustomerSearch{
+						Status: domain.CustomerStatusAll,
+						Sort:   domain.CustomerSortName,
+						After:  &domain.CustomerCursor{Value: "Alice", ID: "customer_1"},
+						Limit:  21,
+					}).
+					Return([]*domain.Customer{bob}, nil)
+			},
+			expected: &domain.CustomerPage{Customers: []*domain.Customer{bob}},
+		},
+		{
+			name: "every invalid parameter is reported",
+			params: domain.CustomerSearchParams{
+				Status:        "gone",
+				Sort:          "password",
+				Order:         "up",
+				Limit:         500,
+				CreatedAfter:  time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Cursor:        "not a cursor",
+			},
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerSearch, "").Return(nil)
+			},
+			expectedError: e.ValidationErrors{
+				{Field: "status", Err: "must be one of active, deleted or all"},
+				{Field: "sort", Err: "must be one of created_at, name or email"},
+				{Field: "order", Err: "must be either asc or desc"},
+				{Field: "limit", Err: "must be between 1 and 100"},
+				{Field: "created_after", Err: "must be before created_before"},
+				{Field: "cursor", Err: "is not valid"},
+			},
+		},
+		{
+			name: "role without the search permission",
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerSearch, "").Return(e.NewUnauthorizedError())
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name: "repository error",
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "admin_123", domain.PermissionCustomerSearch, "").Return(nil)
+				mockSearcher.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewSearchCustomersUseCase(mockSearcher, mockPolicy)
+			result, err := uc.SearchCustomers(context.Background(), "admin_123", tt.params)
+
+			if tt.expectedError != nil {
+				require.Error(t, err)
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
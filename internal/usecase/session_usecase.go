@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+const (
+	sessionSeenCacheKey = "session_seen::%s"
+	// sessionActivityInterval is how often requests of a session may move its LastSeenAt forward
+	sessionActivityInterval = 5 * time.Minute
+)
+
+type SessionUseCase struct {
+	sessionLister domain.ListSessionsRepository
+	sessionGetter domain.GetSessionRepository
+	seenMarker    domain.MarkSessionSeenRepository
+	cache         domain.Cache
+	revoker       domain.TokenRevoker
+	accessPolicy  domain.Policy
+}
+
+func NewSessionUseCase(
+	sessionLister domain.ListSessionsRepository,
+	sessionGetter domain.GetSessionRepository,
+	seenMarker domain.MarkSessionSeenRepository,
+	cache domain.Cache,
+	revoker domain.TokenRevoker,
+	accessPolicy domain.Policy,
+) *SessionUseCase {
+	return &SessionUseCase{
+		sessionLister: sessionLister,
+		sessionGetter: sessionGetter,
+		seenMarker:    seenMarker,
+		cache:         cache,
+		revoker:       revoker,
+		accessPolicy:  accessPolicy,
+	}
+}
+
+func (u *SessionUseCase) ListSessions(ctx context.Context, currentCustomerID string, customerID string) ([]*domain.Session, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerRead, customerID); err != nil {
+		return nil, err
+	}
+
+	return u.sessionLister.ListActiveByCustomerID(ctx, customerID)
+}
+
+// RevokeSession logs the device out, its refresh token stops working and its access tokens are rejected right away
+func (u *SessionUseCase) RevokeSession(ctx context.Context, currentCustomerID string, customerID string, sessionID string) error {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerWrite, customerID); err != nil {
+		return err
+	}
+
+	session, err := u.sessionGetter.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.CustomerID != customerID || !session.RevokedAt.IsZero() {
+		return e.NewNotFoundError("session")
+	}
+
+	return u.revoker.RevokeSession(ctx, sessionID)
+}
+
+// RecordActivity moves LastSeenAt of the session forward, at most once every sessionActivityInterval
+// so authenticated requests don't each write to the database
+func (u *SessionUseCase) RecordActivity(ctx context.Context, sessionID string) error {
+	key := fmt.Sprintf(sessionSeenCacheKey, sessionID)
+
+	seen, err := u.cache.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if seen != "" {
+		return nil
+	}
+
+	if err := u.cache.Set(ctx, key, "1", sessionActivityInterval); err != nil {
+		return err
+	}
+
+	return u.seenMarker.MarkSeen(ctx, sessionID, time.Now())
+}
@@ -0,0 +1,183 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionUseCase_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := mocks.NewMockListSessionsRepository(ctrl)
+	mockGetter := mocks.NewMockGetSessionRepository(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+
+	sessions := []*domain.Session{{ID: "session_123", CustomerID: "customer_123", UserAgent: "curl/8.0", IP: "10.0.0.1"}}
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		setupMocks        func()
+		expected          []*domain.Session
+		expectedError     error
+	}{
+		{
+			name:              "own sessions",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockLister.EXPECT().ListActiveByCustomerID(gomock.Any(), "customer_123").Return(sessions, nil)
+			},
+			expected: sessions,
+		},
+		{
+			name:              "another customer",
+			currentCustomerID: "other_123",
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewSessionUseCase(mockLister, mockGetter, nil, nil, mockRevoker, ownerOnlyPolicy(ctrl))
+			result, err := uc.ListSessions(context.Background(), tt.currentCustomerID, "customer_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestSessionUseCase_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := mocks.NewMockListSessionsRepository(ctrl)
+	mockGetter := mocks.NewMockGetSessionRepository(ctrl)
+	mockRevoker := mocks.NewMockTokenRevoker(ctrl)
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "revokes the session",
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "session_123").Return(&domain.Session{ID: "session_123", CustomerID: "customer_123"}, nil)
+				mockRevoker.EXPECT().RevokeSession(gomock.Any(), "session_123").Return(nil)
+			},
+		},
+		{
+			name: "unknown session",
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "session_123").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("session"),
+		},
+		{
+			name: "session of another customer",
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "session_123").Return(&domain.Session{ID: "session_123", CustomerID: "other_123"}, nil)
+			},
+			expectedError: e.NewNotFoundError("session"),
+		},
+		{
+			name: "already revoked",
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "session_123").Return(&domain.Session{ID: "session_123", CustomerID: "customer_123", RevokedAt: time.Now()}, nil)
+			},
+			expectedError: e.NewNotFoundError("session"),
+		},
+		{
+			name: "revocation error",
+			setupMocks: func() {
+				mockGetter.EXPECT().GetByID(gomock.Any(), "session_123").Return(&domain.Session{ID: "session_123", CustomerID: "customer_123"}, nil)
+				mockRevoker.EXPECT().RevokeSession(gomock.Any(), "session_123").Return(errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewSessionUseCase(mockLister, mockGetter, nil, nil, mockRevoker, ownerOnlyPolicy(ctrl))
+			err := uc.RevokeSession(context.Background(), "customer_123", "customer_123", "session_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_RecordActivity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSeenMarker := mocks.NewMockMarkSessionSeenRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "marks the session as seen",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "session_seen::session_123").Return("", nil)
+				mockCache.EXPECT().Set(gomock.Any(), "session_seen::session_123", "1", 5*time.Minute).Return(nil)
+				mockSeenMarker.EXPECT().MarkSeen(gomock.Any(), "session_123", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "already seen within the interval",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "session_seen::session_123").Return("1", nil)
+			},
+		},
+		{
+			name: "cache error",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "session_seen::session_123").Return("", errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewSessionUseCase(nil, nil, mockSeenMarker, mockCache, nil, nil)
+			err := uc.RecordActivity(context.Background(), "session_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -8,18 +8,20 @@ import (
 )
 
 type GetCustomerData struct {
-	Getter domain.GetCustomerByIDRepository
+	Getter       domain.GetCustomerByIDRepository
+	AccessPolicy domain.Policy
 }
 
-func NewGetCustomerData(getter domain.GetCustomerByIDRepository) *GetCustomerData {
+func NewGetCustomerData(getter domain.GetCustomerByIDRepository, accessPolicy domain.Policy) *GetCustomerData {
 	return &GetCustomerData{
-		Getter: getter,
+		Getter:       getter,
+		AccessPolicy: accessPolicy,
 	}
 }
 
 func (g *GetCustomerData) ShowCustomerData(ctx context.Context, currentCustomerId string, id string) (*domain.OutgoingCustomer, error) {
-	if currentCustomerId != id {
-		return nil, e.NewUnauthorizedError()
+	if err := g.AccessPolicy.Authorize(ctx, currentCustomerId, domain.PermissionCustomerRead, id); err != nil {
+		return nil, err
 	}
 	customer, err := g.Getter.GetByID(ctx, id)
 	if err != nil {
@@ -91,7 +91,7 @@ func TestGetCustomerData_ShowCustomerData(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
 
-			uc := usecase.NewGetCustomerData(mockRepo)
+			uc := usecase.NewGetCustomerData(mockRepo, ownerOnlyPolicy(ctrl))
 			customer, err := uc.ShowCustomerData(context.Background(), tt.currentCustomerID, tt.customerID)
 
 			if tt.expectedError != nil {
@@ -12,24 +12,26 @@ type ShowWishlistUseCase struct {
 	wishlistGetter domain.WishlistByIdRepository
 	customerGetter domain.GetCustomerByIDRepository
 	productGetter  domain.GetProductUseCase
+	wishlistPolicy domain.WishlistPolicy
 }
 
 func NewShowWishlistUseCase(
 	wishlistGetter domain.WishlistByIdRepository,
 	customerGetter domain.GetCustomerByIDRepository,
 	productGetter domain.GetProductUseCase,
-
+	wishlistPolicy domain.WishlistPolicy,
 ) *ShowWishlistUseCase {
 	return &ShowWishlistUseCase{
 		wishlistGetter: wishlistGetter,
 		customerGetter: customerGetter,
 		productGetter:  productGetter,
+		wishlistPolicy: wishlistPolicy,
 	}
 }
 
 func (u *ShowWishlistUseCase) ShowWishlist(ctx context.Context, currentCustomerId string, customerId string, wishlistId string) (*domain.FullfilledWishlist, error) {
-	if currentCustomerId != customerId {
-		return nil, e.NewUnauthorizedError()
+	if err := u.wishlistPolicy.AuthorizeWishlist(ctx, currentCustomerId, domain.PermissionWishlistRead, customerId, wishlistId); err != nil {
+		return nil, err
 	}
 
 	customer, err := u.customerGetter.GetByID(ctx, customerId)
@@ -63,41 +65,43 @@ func (u *ShowWishlistUseCase) ShowWishlist(ctx context.Context, currentCustomerI
 			CreatedAt: customer.CreatedAt,
 		},
 		Title: wishlist.Title,
-		Items: make([]domain.Product, 0, len(wishlist.Items)),
+		Items: make([]domain.FullfilledWishlistItem, 0, len(wishlist.Items)),
 	}
 
 	if len(wishlist.Items) == 0 {
 		return ffwl, nil
 	}
 
-	items := u.fetchProductsConcurrently(ctx, wishlist.Items)
+	items := fetchProductsConcurrently(ctx, u.productGetter, wishlist.Items)
 
 	ffwl.Items = items
 
 	return ffwl, nil
 }
 
-func (u *ShowWishlistUseCase) fetchProductsConcurrently(ctx context.Context, itemIDs []string) []domain.Product {
+// fetchProductsConcurrently keeps the order of the items, items whose product can't be fetched are left out
+func fetchProductsConcurrently(ctx context.Context, productGetter domain.GetProductUseCase, wishlistItems []domain.WishlistItem) []domain.FullfilledWishlistItem {
 	type productResult struct {
+		index   int
 		product *domain.Product
 		err     error
 	}
-	results := make(chan productResult, len(itemIDs))
-	items := make([]domain.Product, 0, len(itemIDs))
+	results := make(chan productResult, len(wishlistItems))
+	products := make([]*domain.Product, len(wishlistItems))
 
-	for _, itemID := range itemIDs {
-		go func(id string) {
+	for i, item := range wishlistItems {
+		go func(i int, id string) {
 			select {
 			case <-ctx.Done():
-				results <- productResult{err: ctx.Err()}
+				results <- productResult{index: i, err: ctx.Err()}
 			default:
-				product, err := u.productGetter.Execute(ctx, id)
-				results <- productResult{product: product, err: err}
+				product, err := productGetter.Execute(ctx, id)
+				results <- productResult{index: i, product: product, err: err}
 			}
-		}(itemID)
+		}(i, item.ProductID)
 	}
 
-	for i := 0; i < len(itemIDs); i++ {
+	for i := 0; i < len(wishlistItems); i++ {
 		select {
 		case <-ctx.Done():
 			fmt.Printf("Context cancelled: %v\n", ctx.Err())
@@ -107,9 +111,14 @@ func (u *ShowWishlistUseCase) fetchProductsConcurrently(ctx context.Context, ite
 				fmt.Printf("Error fetching product: %v\n", result.err)
 				continue
 			}
-			if result.product != nil {
-				items = append(items, *result.product)
-			}
+			products[result.index] = result.product
+		}
+	}
+
+	items := make([]domain.FullfilledWishlistItem, 0, len(wishlistItems))
+	for i, product := range products {
+		if product != nil {
+			items = append(items, domain.FullfilledWishlistItem{WishlistItem: wishlistItems[i], Product: *product})
 		}
 	}
 
@@ -79,7 +79,7 @@ func TestShowWishlist_AuthorizationAndValidation(t *testing.T) {
 
 			tt.setupMocks(mockCustomerGetter, mockWishlistGetter)
 
-			uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter)
+			uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter, ownerOnlyWishlistPolicy(ctrl))
 			wishlist, err := uc.ShowWishlist(context.Background(), tt.currentCustomerID, tt.customerID, tt.wishlistID)
 
 			assert.Error(t, err)
@@ -105,7 +105,7 @@ func TestShowWishlist_EmptyWishlist(t *testing.T) {
 		ID:         "wishlist1",
 		CustomerId: "customer1",
 		Title:      "Empty Wishlist",
-		Items:      []string{},
+		Items:      []domain.WishlistItem{},
 	}
 
 	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
@@ -120,7 +120,7 @@ func TestShowWishlist_EmptyWishlist(t *testing.T) {
 		GetById(gomock.Any(), "wishlist1").
 		Return(emptyWishlist, nil)
 
-	uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter)
+	uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter, ownerOnlyWishlistPolicy(ctrl))
 	result, err := uc.ShowWishlist(context.Background(), "customer1", "customer1", "wishlist1")
 
 	outCustomer := &domain.OutgoingCustomer{
@@ -152,7 +152,7 @@ func TestShowWishlist_WithProducts(t *testing.T) {
 		ID:         "wishlist1",
 		CustomerId: "customer1",
 		Title:      "My Wishlist",
-		Items:      []string{"product1", "product2"},
+		Items:      []domain.WishlistItem{{ProductID: "product1"}, {ProductID: "product2"}},
 	}
 
 	product1 := &domain.Product{ID: "product1", Name: "Product 1"}
@@ -179,7 +179,7 @@ func TestShowWishlist_WithProducts(t *testing.T) {
 		Execute(gomock.Any(), "product2").
 		Return(product2, nil)
 
-	uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter)
+	uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter, ownerOnlyWishlistPolicy(ctrl))
 	result, err := uc.ShowWishlist(context.Background(), "customer1", "customer1", "wishlist1")
 
 	outCustomer := &domain.OutgoingCustomer{
@@ -195,17 +195,10 @@ func TestShowWishlist_WithProducts(t *testing.T) {
 	assert.Equal(t, outCustomer, result.Customer)
 	assert.Len(t, result.Items, 2)
 
-	foundProducts := make(map[string]bool)
-	for _, item := range result.Items {
-		foundProducts[item.ID] = true
-		if item.ID == "product1" {
-			assert.Equal(t, "Product 1", item.Name)
-		} else if item.ID == "product2" {
-			assert.Equal(t, "Product 2", item.Name)
-		}
-	}
-	assert.True(t, foundProducts["product1"])
-	assert.True(t, foundProducts["product2"])
+	assert.Equal(t, "product1", result.Items[0].ProductID)
+	assert.Equal(t, "Product 1", result.Items[0].Product.Name)
+	assert.Equal(t, "product2", result.Items[1].ProductID)
+	assert.Equal(t, "Product 2", result.Items[1].Product.Name)
 }
 
 func TestShowWishlist_RepositoryErrors(t *testing.T) {
@@ -248,7 +241,7 @@ func TestShowWishlist_RepositoryErrors(t *testing.T) {
 
 			tt.setupMocks(mockCustomerGetter, mockWishlistGetter)
 
-			uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter)
+			uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter, ownerOnlyWishlistPolicy(ctrl))
 			result, err := uc.ShowWishlist(context.Background(), "customer1", "customer1", "wishlist1")
 
 			assert.Error(t, err)
@@ -293,7 +286,7 @@ func TestShowWishlist_ProductErrors(t *testing.T) {
 			wishlist := &domain.Wishlist{
 				ID:         "wishlist1",
 				CustomerId: "customer1",
-				Items:      []string{"product1"},
+				Items:      []domain.WishlistItem{{ProductID: "product1"}},
 			}
 
 			mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
@@ -310,7 +303,7 @@ func TestShowWishlist_ProductErrors(t *testing.T) {
 
 			tt.setupMocks(mockProductGetter)
 
-			uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter)
+			uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter, ownerOnlyWishlistPolicy(ctrl))
 			result, err := uc.ShowWishlist(context.Background(), "customer1", "customer1", "wishlist1")
 
 			if tt.expectedError == nil {
@@ -336,7 +329,7 @@ func TestShowWishlist_ContextCancellationInGoroutine(t *testing.T) {
 	wishlist := &domain.Wishlist{
 		ID:         "wishlist1",
 		CustomerId: "customer1",
-		Items:      []string{"product1", "product2"},
+		Items:      []domain.WishlistItem{{ProductID: "product1"}, {ProductID: "product2"}},
 	}
 
 	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
@@ -359,7 +352,7 @@ func TestShowWishlist_ContextCancellationInGoroutine(t *testing.T) {
 			return nil, ctx.Err()
 		}).AnyTimes()
 
-	uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter)
+	uc := usecase.NewShowWishlistUseCase(mockWishlistGetter, mockCustomerGetter, mockProductGetter, ownerOnlyWishlistPolicy(ctrl))
 	result, err := uc.ShowWishlist(ctx, "customer1", "customer1", "wishlist1")
 
 	assert.NoError(t, err)
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type TokenRevocationUseCase struct {
+	accessTTL       time.Duration
+	cache           domain.Cache
+	familyRevoker   domain.RevokeRefreshTokenFamilyRepository
+	customerRevoker domain.RevokeCustomerRefreshTokensRepository
+	sessionRevoker  domain.RevokeSessionRepository
+	sessionsRevoker domain.RevokeCustomerSessionsRepository
+}
+
+func NewTokenRevocationUseCase(
+	accessTTL time.Duration,
+	cache domain.Cache,
+	familyRevoker domain.RevokeRefreshTokenFamilyRepository,
+	customerRevoker domain.RevokeCustomerRefreshTokensRepository,
+	sessionRevoker domain.RevokeSessionRepository,
+	sessionsRevoker domain.RevokeCustomerSessionsRepository,
+) *TokenRevocationUseCase {
+	return &TokenRevocationUseCase{
+		accessTTL:       accessTTL,
+		cache:           cache,
+		familyRevoker:   familyRevoker,
+		customerRevoker: customerRevoker,
+		sessionRevoker:  sessionRevoker,
+		sessionsRevoker: sessionsRevoker,
+	}
+}
+
+func revokedTokenKey(tokenID string) string {
+	return fmt.Sprintf("revoked_token::%s", tokenID)
+}
+
+func revokedSessionKey(sessionID string) string {
+	return fmt.Sprintf("revoked_session::%s", sessionID)
+}
+
+func revokedBeforeKey(customerID string) string {
+	return fmt.Sprintf("tokens_revoked_before::%s", customerID)
+}
+
+func (u *TokenRevocationUseCase) RevokeToken(ctx context.Context, claims *domain.TokenClaims) error {
+	// the denylist entry only needs to live as long as the token itself
+	if ttl := time.Until(claims.ExpiresAt); ttl > 0 && claims.ID != "" {
+		if err := u.cache.Set(ctx, revokedTokenKey(claims.ID), "1", ttl); err != nil {
+			return err
+		}
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	return u.RevokeSession(ctx, claims.SessionID)
+}
+
+func (u *TokenRevocationUseCase) RevokeSession(ctx context.Context, sessionID string) error {
+	now := time.Now()
+
+	// access tokens of the session were all issued within the last accessTTL
+	if err := u.cache.Set(ctx, revokedSessionKey(sessionID), "1", u.accessTTL); err != nil {
+		return err
+	}
+
+	if err := u.familyRevoker.RevokeFamily(ctx, sessionID, now); err != nil {
+		return err
+	}
+
+	return u.sessionRevoker.Revoke(ctx, sessionID, now)
+}
+
+func (u *TokenRevocationUseCase) RevokeAllForCustomer(ctx context.Context, customerID string) error {
+	now := time.Now()
+
+	// every access token issued up to now expires within accessTTL, so the marker can expire with them
+	err := u.cache.Set(ctx, revokedBeforeKey(customerID), strconv.FormatInt(now.Unix(), 10), u.accessTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := u.customerRevoker.RevokeAllForCustomer(ctx, customerID, now); err != nil {
+		return err
+	}
+
+	return u.sessionsRevoker.RevokeAllForCustomer(ctx, customerID, now)
+}
+
+func (u *TokenRevocationUseCase) IsRevoked(ctx context.Context, claims *domain.TokenClaims) (bool, error) {
+	revoked, err := u.cache.Get(ctx, revokedTokenKey(claims.ID))
+	if err != nil {
+		return false, err
+	}
+
+	if revoked != "" {
+		return true, nil
+	}
+
+	if claims.SessionID != "" {
+		revoked, err = u.cache.Get(ctx, revokedSessionKey(claims.SessionID))
+		if err != nil {
+			return false, err
+		}
+
+		if revoked != "" {
+			return true, nil
+		}
+	}
+
+	revokedBefore, err := u.cache.Get(ctx, revokedBeforeKey(claims.Subject))
+	if err != nil {
+		return false, err
+	}
+
+	if revokedBefore == "" {
+		return false, nil
+	}
+
+	before, err := strconv.ParseInt(revokedBefore, 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	// iat has a one second precision, tokens issued in the second of the revocation are kept
+	// so a login right after a password change is not revoked along with the old tokens
+	return claims.IssuedAt.Unix() < before, nil
+}
@@ -0,0 +1,250 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTokenRevocationUseCase_RevokeToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockFamilyRevoker := mocks.NewMockRevokeRefreshTokenFamilyRepository(ctrl)
+	mockCustomerRevoker := mocks.NewMockRevokeCustomerRefreshTokensRepository(ctrl)
+	mockSessionRevoker := mocks.NewMockRevokeSessionRepository(ctrl)
+	mockSessionsRevoker := mocks.NewMockRevokeCustomerSessionsRepository(ctrl)
+
+	tests := []struct {
+		name          string
+		claims        *domain.TokenClaims
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "revokes token and session",
+			claims: &domain.TokenClaims{
+				ID:        "jti_123",
+				Subject:   "customer_123",
+				SessionID: "family_123",
+				ExpiresAt: time.Now().Add(10 * time.Minute),
+			},
+			setupMocks: func() {
+				mockCache.EXPECT().
+					Set(gomock.Any(), "revoked_token::jti_123", "1", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, key, value string, ttl time.Duration) error {
+						assert.InDelta(t, 10*time.Minute, ttl, float64(time.Second))
+						return nil
+					})
+				mockCache.EXPECT().
+					Set(gomock.Any(), "revoked_session::family_123", "1", 15*time.Minute).
+					Return(nil)
+				mockFamilyRevoker.EXPECT().
+					RevokeFamily(gomock.Any(), "family_123", gomock.Any()).
+					Return(nil)
+				mockSessionRevoker.EXPECT().
+					Revoke(gomock.Any(), "family_123", gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			name: "expired token only revokes the session",
+			claims: &domain.TokenClaims{
+				ID:        "jti_123",
+				SessionID: "family_123",
+				ExpiresAt: time.Now().Add(-time.Minute),
+			},
+			setupMocks: func() {
+				mockCache.EXPECT().
+					Set(gomock.Any(), "revoked_session::family_123", "1", 15*time.Minute).
+					Return(nil)
+				mockFamilyRevoker.EXPECT().
+					RevokeFamily(gomock.Any(), "family_123", gomock.Any()).
+					Return(nil)
+				mockSessionRevoker.EXPECT().
+					Revoke(gomock.Any(), "family_123", gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			name: "cache error",
+			claims: &domain.TokenClaims{
+				ID:        "jti_123",
+				ExpiresAt: time.Now().Add(time.Minute),
+			},
+			setupMocks: func() {
+				mockCache.EXPECT().
+					Set(gomock.Any(), "revoked_token::jti_123", "1", gomock.Any()).
+					Return(errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewTokenRevocationUseCase(15*time.Minute, mockCache, mockFamilyRevoker, mockCustomerRevoker, mockSessionRevoker, mockSessionsRevoker)
+			err := uc.RevokeToken(context.Background(), tt.claims)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTokenRevocationUseCase_RevokeAllForCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockFamilyRevoker := mocks.NewMockRevokeRefreshTokenFamilyRepository(ctrl)
+	mockCustomerRevoker := mocks.NewMockRevokeCustomerRefreshTokensRepository(ctrl)
+	mockSessionRevoker := mocks.NewMockRevokeSessionRepository(ctrl)
+	mockSessionsRevoker := mocks.NewMockRevokeCustomerSessionsRepository(ctrl)
+
+	mockCache.EXPECT().
+		Set(gomock.Any(), "tokens_revoked_before::customer_123", gomock.Any(), 15*time.Minute).
+		Return(nil)
+	mockCustomerRevoker.EXPECT().
+		RevokeAllForCustomer(gomock.Any(), "customer_123", gomock.Any()).
+		Return(nil)
+	mockSessionsRevoker.EXPECT().
+		RevokeAllForCustomer(gomock.Any(), "customer_123", gomock.Any()).
+		Return(nil)
+
+	uc := usecase.NewTokenRevocationUseCase(15*time.Minute, mockCache, mockFamilyRevoker, mockCustomerRevoker, mockSessionRevoker, mockSessionsRevoker)
+	assert.NoError(t, uc.RevokeAllForCustomer(context.Background(), "customer_123"))
+}
+
+func TestTokenRevocationUseCase_IsRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockFamilyRevoker := mocks.NewMockRevokeRefreshTokenFamilyRepository(ctrl)
+	mockCustomerRevoker := mocks.NewMockRevokeCustomerRefreshTokensRepository(ctrl)
+	mockSessionRevoker := mocks.NewMockRevokeSessionRepository(ctrl)
+	mockSessionsRevoker := mocks.NewMockRevokeCustomerSessionsRepository(ctrl)
+
+	now := time.Now()
+	claims := &domain.TokenClaims{ID: "jti_123", Subject: "customer_123", IssuedAt: now}
+	sessionClaims := &domain.TokenClaims{ID: "jti_456", Subject: "customer_123", SessionID: "family_123", IssuedAt: now}
+
+	tests := []struct {
+		name          string
+		claims        *domain.TokenClaims
+		setupMocks    func()
+		expected      bool
+		expectedError error
+	}{
+		{
+			name: "token not revoked",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_123").Return("", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "tokens_revoked_before::customer_123").Return("", nil)
+			},
+			expected: false,
+		},
+		{
+			name: "token on the denylist",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_123").Return("1", nil)
+			},
+			expected: true,
+		},
+		{
+			name: "token issued before revoke all",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_123").Return("", nil)
+				mockCache.EXPECT().
+					Get(gomock.Any(), "tokens_revoked_before::customer_123").
+					Return(strconv.FormatInt(now.Add(time.Minute).Unix(), 10), nil)
+			},
+			expected: true,
+		},
+		{
+			name: "token issued after revoke all",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_123").Return("", nil)
+				mockCache.EXPECT().
+					Get(gomock.Any(), "tokens_revoked_before::customer_123").
+					Return(strconv.FormatInt(now.Add(-time.Minute).Unix(), 10), nil)
+			},
+			expected: false,
+		},
+		{
+			name: "token issued in the second of revoke all",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_123").Return("", nil)
+				mockCache.EXPECT().
+					Get(gomock.Any(), "tokens_revoked_before::customer_123").
+					Return(strconv.FormatInt(now.Unix(), 10), nil)
+			},
+			expected: false,
+		},
+		{
+			name:   "session revoked",
+			claims: sessionClaims,
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_456").Return("", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_session::family_123").Return("1", nil)
+			},
+			expected: true,
+		},
+		{
+			name:   "session active",
+			claims: sessionClaims,
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_456").Return("", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_session::family_123").Return("", nil)
+				mockCache.EXPECT().Get(gomock.Any(), "tokens_revoked_before::customer_123").Return("", nil)
+			},
+			expected: false,
+		},
+		{
+			name: "cache error",
+			setupMocks: func() {
+				mockCache.EXPECT().Get(gomock.Any(), "revoked_token::jti_123").Return("", errors.New("cache error"))
+			},
+			expectedError: errors.New("cache error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewTokenRevocationUseCase(15*time.Minute, mockCache, mockFamilyRevoker, mockCustomerRevoker, mockSessionRevoker, mockSessionsRevoker)
+			tokenClaims := claims
+			if tt.claims != nil {
+				tokenClaims = tt.claims
+			}
+
+			revoked, err := uc.IsRevoked(context.Background(), tokenClaims)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, revoked)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+// recoveryCodesCount is how many recovery codes are handed out when TOTP is enabled
+const recoveryCodesCount = 10
+
+type TOTPEnrollmentUseCase struct {
+	customerGetter  domain.GetCustomerByIDRepository
+	mfaGetter       domain.GetCustomerMFARepository
+	mfaSaver        domain.SaveCustomerMFARepository
+	mfaEnabler      domain.EnableCustomerMFARepository
+	mfaDeleter      domain.DeleteCustomerMFARepository
+	stepMarker      domain.MarkTOTPStepUsedRepository
+	codesReplacer   domain.ReplaceRecoveryCodesRepository
+	totp            domain.TOTP
+	secretEncrypter domain.Encrypter
+	secretDecrypter domain.Decrypter
+	idGen           domain.IDGenerator
+	codeGen         domain.TokenGenerator
+	codeHasher      domain.Hasher
+	secondFactor    domain.SecondFactorVerifier
+}
+
+func NewTOTPEnrollmentUseCase(
+	customerGetter domain.GetCustomerByIDRepository,
+	mfaGetter domain.GetCustomerMFARepository,
+	mfaSaver domain.SaveCustomerMFARepository,
+	mfaEnabler domain.EnableCustomerMFARepository,
+	mfaDeleter domain.DeleteCustomerMFARepository,
+	stepMarker domain.MarkTOTPStepUsedRepository,
+	codesReplacer domain.ReplaceRecoveryCodesRepository,
+	totp domain.TOTP,
+	secretEncrypter domain.Encrypter,
+	secretDecrypter domain.Decrypter,
+	idGen domain.IDGenerator,
+	codeGen domain.TokenGenerator,
+	codeHasher domain.Hasher,
+	secondFactor domain.SecondFactorVerifier,
+) *TOTPEnrollmentUseCase {
+	return &TOTPEnrollmentUseCase{
+		customerGetter:  customerGetter,
+		mfaGetter:       mfaGetter,
+		mfaSaver:        mfaSaver,
+		mfaEnabler:      mfaEnabler,
+		mfaDeleter:      mfaDeleter,
+		stepMarker:      stepMarker,
+		codesReplacer:   codesReplacer,
+		totp:            totp,
+		secretEncrypter: secretEncrypter,
+		secretDecrypter: secretDecrypter,
+		idGen:           idGen,
+		codeGen:         codeGen,
+		codeHasher:      codeHasher,
+		secondFactor:    secondFactor,
+	}
+}
+
+// StartEnrollment creates a new secret for the customer, it only takes effect once confirmed
+// so starting again replaces a pending enrollment
+func (u *TOTPEnrollmentUseCase) StartEnrollment(ctx context.Context, currentCustomerID string) (*domain.MFAEnrollment, error) {
+	mfa, err := u.mfaGetter.GetByCustomerID(ctx, currentCustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa.Enabled() {
+		return nil, &e.ValidationError{Field: "mfa", Err: "is already enabled"}
+	}
+
+	customer, err := u.customerGetter.GetByID(ctx, currentCustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer == nil {
+		return nil, e.NewNotFoundError("customer")
+	}
+
+	secret, err := u.totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate totp secret"))
+	}
+
+	encrypted, err := u.secretEncrypter.Encrypt(secret)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to encrypt totp secret"))
+	}
+
+	err = u.mfaSaver.Save(ctx, &domain.CustomerMFA{
+		CustomerID: customer.ID,
+		Secret:     encrypted,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: u.totp.ProvisioningURI(secret, customer.Email),
+	}, nil
+}
+
+func (u *TOTPEnrollmentUseCase) ConfirmEnrollment(ctx context.Context, currentCustomerID string, code string) ([]string, error) {
+	if code == "" {
+		return nil, e.NewRequiredFieldError("code")
+	}
+
+	mfa, err := u.mfaGetter.GetByCustomerID(ctx, currentCustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa == nil {
+		return nil, &e.ValidationError{Field: "mfa", Err: "enrollment was not started"}
+	}
+
+	if mfa.Enabled() {
+		return nil, &e.ValidationError{Field: "mfa", Err: "is already enabled"}
+	}
+
+	secret, err := u.secretDecrypter.Decrypt(mfa.Secret)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to decrypt totp secret"))
+	}
+
+	now := time.Now()
+	invalidCode := &e.ValidationError{Field: "code", Err: "is invalid"}
+	step, ok := u.totp.Validate(secret, code, now)
+	if !ok {
+		return nil, invalidCode
+	}
+
+	// a code that was already accepted once can't be replayed
+	fresh, err := u.stepMarker.MarkStepUsed(ctx, currentCustomerID, step)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fresh {
+		return nil, invalidCode
+	}
+
+	codes := make([]string, recoveryCodesCount)
+	records := make([]domain.RecoveryCode, recoveryCodesCount)
+	for i := range codes {
+		codes[i], err = u.codeGen.Generate()
+		if err != nil {
+			return nil, errors.Join(err, errors.New("failed to generate recovery code"))
+		}
+
+		hash, err := u.codeHasher.Hash(codes[i])
+		if err != nil {
+			return nil, errors.Join(err, errors.New("failed to hash recovery code"))
+		}
+
+		id, err := u.idGen.Generate()
+		if err != nil {
+			return nil, errors.Join(err, errors.New("failed to generate recovery code ID"))
+		}
+
+		records[i] = domain.RecoveryCode{
+			ID:         id,
+			CustomerID: currentCustomerID,
+			CodeHash:   hash,
+			CreatedAt:  now,
+		}
+	}
+
+	if err := u.codesReplacer.ReplaceRecoveryCodes(ctx, currentCustomerID, records); err != nil {
+		return nil, err
+	}
+
+	if err := u.mfaEnabler.Enable(ctx, currentCustomerID, now); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable removes TOTP and the recovery codes, it needs a valid code so a stolen session can't turn it off
+func (u *TOTPEnrollmentUseCase) Disable(ctx context.Context, currentCustomerID string, code string) error {
+	if err := u.secondFactor.VerifySecondFactor(ctx, currentCustomerID, code); err != nil {
+		return err
+	}
+
+	return u.mfaDeleter.Delete(ctx, currentCustomerID)
+}
@@ -0,0 +1,219 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTOTPEnrollmentUseCase_StartEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockMFAGetter := mocks.NewMockGetCustomerMFARepository(ctrl)
+	mockMFASaver := mocks.NewMockSaveCustomerMFARepository(ctrl)
+	mockMFAEnabler := mocks.NewMockEnableCustomerMFARepository(ctrl)
+	mockMFADeleter := mocks.NewMockDeleteCustomerMFARepository(ctrl)
+	mockStepMarker := mocks.NewMockMarkTOTPStepUsedRepository(ctrl)
+	mockCodesReplacer := mocks.NewMockReplaceRecoveryCodesRepository(ctrl)
+	mockTOTP := mocks.NewMockTOTP(ctrl)
+	mockEncrypter := mocks.NewMockEncrypter(ctrl)
+	mockDecrypter := mocks.NewMockDecrypter(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockCodeGen := mocks.NewMockTokenGenerator(ctrl)
+	mockCodeHasher := mocks.NewMockHasher(ctrl)
+	mockSecondFactor := mocks.NewMockSecondFactorVerifier(ctrl)
+
+	uc := usecase.NewTOTPEnrollmentUseCase(
+		mockCustomerGetter,
+		mockMFAGetter,
+		mockMFASaver,
+		mockMFAEnabler,
+		mockMFADeleter,
+		mockStepMarker,
+		mockCodesReplacer,
+		mockTOTP,
+		mockEncrypter,
+		mockDecrypter,
+		mockIDGen,
+		mockCodeGen,
+		mockCodeHasher,
+		mockSecondFactor,
+	)
+
+	t.Run("stores the encrypted secret", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+		mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(&domain.Customer{ID: "customer_123", Email: "john@example.com"}, nil)
+		mockTOTP.EXPECT().GenerateSecret().Return("JBSWY3DPEHPK3PXP", nil)
+		mockEncrypter.EXPECT().Encrypt("JBSWY3DPEHPK3PXP").Return("encrypted_secret", nil)
+		mockMFASaver.EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, mfa *domain.CustomerMFA) error {
+				assert.Equal(t, "customer_123", mfa.CustomerID)
+				assert.Equal(t, "encrypted_secret", mfa.Secret)
+				assert.False(t, mfa.Enabled())
+				return nil
+			})
+		mockTOTP.EXPECT().ProvisioningURI("JBSWY3DPEHPK3PXP", "john@example.com").Return("otpauth://totp/Wishlist:john@example.com")
+
+		enrollment, err := uc.StartEnrollment(context.Background(), "customer_123")
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.MFAEnrollment{
+			Secret:          "JBSWY3DPEHPK3PXP",
+			ProvisioningURI: "otpauth://totp/Wishlist:john@example.com",
+		}, enrollment)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(&domain.CustomerMFA{EnabledAt: time.Now()}, nil)
+
+		_, err := uc.StartEnrollment(context.Background(), "customer_123")
+		assert.EqualError(t, err, (&e.ValidationError{Field: "mfa", Err: "is already enabled"}).Error())
+	})
+}
+
+func TestTOTPEnrollmentUseCase_ConfirmEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockMFAGetter := mocks.NewMockGetCustomerMFARepository(ctrl)
+	mockMFASaver := mocks.NewMockSaveCustomerMFARepository(ctrl)
+	mockMFAEnabler := mocks.NewMockEnableCustomerMFARepository(ctrl)
+	mockMFADeleter := mocks.NewMockDeleteCustomerMFARepository(ctrl)
+	mockStepMarker := mocks.NewMockMarkTOTPStepUsedRepository(ctrl)
+	mockCodesReplacer := mocks.NewMockReplaceRecoveryCodesRepository(ctrl)
+	mockTOTP := mocks.NewMockTOTP(ctrl)
+	mockEncrypter := mocks.NewMockEncrypter(ctrl)
+	mockDecrypter := mocks.NewMockDecrypter(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockCodeGen := mocks.NewMockTokenGenerator(ctrl)
+	mockCodeHasher := mocks.NewMockHasher(ctrl)
+	mockSecondFactor := mocks.NewMockSecondFactorVerifier(ctrl)
+
+	uc := usecase.NewTOTPEnrollmentUseCase(
+		mockCustomerGetter,
+		mockMFAGetter,
+		mockMFASaver,
+		mockMFAEnabler,
+		mockMFADeleter,
+		mockStepMarker,
+		mockCodesReplacer,
+		mockTOTP,
+		mockEncrypter,
+		mockDecrypter,
+		mockIDGen,
+		mockCodeGen,
+		mockCodeHasher,
+		mockSecondFactor,
+	)
+
+	pending := &domain.CustomerMFA{CustomerID: "customer_123", Secret: "encrypted_secret"}
+
+	t.Run("enables the factor and returns recovery codes", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(pending, nil)
+		mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+		mockTOTP.EXPECT().Validate("JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(42), true)
+		mockStepMarker.EXPECT().MarkStepUsed(gomock.Any(), "customer_123", int64(42)).Return(true, nil)
+		mockCodeGen.EXPECT().Generate().Return("recovery_code", nil).Times(10)
+		mockCodeHasher.EXPECT().Hash("recovery_code").Return("recovery_hash", nil).Times(10)
+		mockIDGen.EXPECT().Generate().Return("code_id", nil).Times(10)
+		mockCodesReplacer.EXPECT().
+			ReplaceRecoveryCodes(gomock.Any(), "customer_123", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, customerID string, codes []domain.RecoveryCode) error {
+				assert.Len(t, codes, 10)
+				assert.Equal(t, "recovery_hash", codes[0].CodeHash)
+				return nil
+			})
+		mockMFAEnabler.EXPECT().Enable(gomock.Any(), "customer_123", gomock.Any()).Return(nil)
+
+		codes, err := uc.ConfirmEnrollment(context.Background(), "customer_123", "123456")
+		assert.NoError(t, err)
+		assert.Len(t, codes, 10)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(pending, nil)
+		mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+		mockTOTP.EXPECT().Validate("JBSWY3DPEHPK3PXP", "000000", gomock.Any()).Return(int64(0), false)
+
+		_, err := uc.ConfirmEnrollment(context.Background(), "customer_123", "000000")
+		assert.EqualError(t, err, (&e.ValidationError{Field: "code", Err: "is invalid"}).Error())
+	})
+
+	t.Run("replayed code", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(pending, nil)
+		mockDecrypter.EXPECT().Decrypt("encrypted_secret").Return("JBSWY3DPEHPK3PXP", nil)
+		mockTOTP.EXPECT().Validate("JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(42), true)
+		mockStepMarker.EXPECT().MarkStepUsed(gomock.Any(), "customer_123", int64(42)).Return(false, nil)
+
+		_, err := uc.ConfirmEnrollment(context.Background(), "customer_123", "123456")
+		assert.EqualError(t, err, (&e.ValidationError{Field: "code", Err: "is invalid"}).Error())
+	})
+
+	t.Run("enrollment not started", func(t *testing.T) {
+		mockMFAGetter.EXPECT().GetByCustomerID(gomock.Any(), "customer_123").Return(nil, nil)
+
+		_, err := uc.ConfirmEnrollment(context.Background(), "customer_123", "123456")
+		assert.EqualError(t, err, (&e.ValidationError{Field: "mfa", Err: "enrollment was not started"}).Error())
+	})
+}
+
+func TestTOTPEnrollmentUseCase_Disable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockMFAGetter := mocks.NewMockGetCustomerMFARepository(ctrl)
+	mockMFASaver := mocks.NewMockSaveCustomerMFARepository(ctrl)
+	mockMFAEnabler := mocks.NewMockEnableCustomerMFARepository(ctrl)
+	mockMFADeleter := mocks.NewMockDeleteCustomerMFARepository(ctrl)
+	mockStepMarker := mocks.NewMockMarkTOTPStepUsedRepository(ctrl)
+	mockCodesReplacer := mocks.NewMockReplaceRecoveryCodesRepository(ctrl)
+	mockTOTP := mocks.NewMockTOTP(ctrl)
+	mockEncrypter := mocks.NewMockEncrypter(ctrl)
+	mockDecrypter := mocks.NewMockDecrypter(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockCodeGen := mocks.NewMockTokenGenerator(ctrl)
+	mockCodeHasher := mocks.NewMockHasher(ctrl)
+	mockSecondFactor := mocks.NewMockSecondFactorVerifier(ctrl)
+
+	uc := usecase.NewTOTPEnrollmentUseCase(
+		mockCustomerGetter,
+		mockMFAGetter,
+		mockMFASaver,
+		mockMFAEnabler,
+		mockMFADeleter,
+		mockStepMarker,
+		mockCodesReplacer,
+		mockTOTP,
+		mockEncrypter,
+		mockDecrypter,
+		mockIDGen,
+		mockCodeGen,
+		mockCodeHasher,
+		mockSecondFactor,
+	)
+
+	t.Run("valid code", func(t *testing.T) {
+		mockSecondFactor.EXPECT().VerifySecondFactor(gomock.Any(), "customer_123", "123456").Return(nil)
+		mockMFADeleter.EXPECT().Delete(gomock.Any(), "customer_123").Return(nil)
+
+		assert.NoError(t, uc.Disable(context.Background(), "customer_123", "123456"))
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		invalid := &e.ValidationError{Field: "code", Err: "is invalid"}
+		mockSecondFactor.EXPECT().VerifySecondFactor(gomock.Any(), "customer_123", "000000").Return(invalid)
+
+		assert.Equal(t, invalid, uc.Disable(context.Background(), "customer_123", "000000"))
+	})
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/ydoro/wishlist/internal/domain"
@@ -9,36 +10,50 @@ import (
 )
 
 type UpdateCustomerUseCase struct {
-	Updater     domain.UpdateCustomerRepository
-	Getter      domain.GetCustomerByIDRepository
-	EmailGetter domain.GetCustomerByEmailRepository
+	Updater      domain.UpdateCustomerRepository
+	Getter       domain.GetCustomerByIDRepository
+	EmailGetter  domain.GetCustomerByEmailRepository
+	Restorer     domain.AccountRestorer
+	Verifier     domain.EmailVerificationSender
+	AccessPolicy domain.Policy
 }
 
 func NewUpdateCustomerUseCase(
 	updater domain.UpdateCustomerRepository,
 	getter domain.GetCustomerByIDRepository,
 	emailGetter domain.GetCustomerByEmailRepository,
+	restorer domain.AccountRestorer,
+	verifier domain.EmailVerificationSender,
+	accessPolicy domain.Policy,
 ) *UpdateCustomerUseCase {
 	return &UpdateCustomerUseCase{
-		Updater:     updater,
-		Getter:      getter,
-		EmailGetter: emailGetter,
+		Updater:      updater,
+		Getter:       getter,
+		EmailGetter:  emailGetter,
+		Restorer:     restorer,
+		Verifier:     verifier,
+		AccessPolicy: accessPolicy,
 	}
 }
 
 func (u *UpdateCustomerUseCase) UpdateCustomer(ctx context.Context, currentCustomerID string, customerID string, data domain.CustomerEditableFields) (*domain.OutgoingCustomer, error) {
-	if currentCustomerID != customerID {
-		return nil, e.NewUnauthorizedError()
+	if err := u.AccessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionCustomerWrite, customerID); err != nil {
+		return nil, err
 	}
 	customer, err := u.Getter.GetByID(ctx, customerID)
 	if err != nil {
 		return nil, err
 	}
 
+	if customer == nil {
+		return nil, e.NewNotFoundError("customer")
+	}
+
 	if data.Name != "" {
 		customer.Name = data.Name
 	}
 
+	emailChanged := false
 	if data.Email != "" {
 		if customer.Email != data.Email {
 			existingCustomer, err := u.EmailGetter.GetByEmail(ctx, data.Email)
@@ -46,11 +61,22 @@ func (u *UpdateCustomerUseCase) UpdateCustomer(ctx context.Context, currentCusto
 				return nil, err
 			}
 
+			// the email of a deleted account stays taken until it is purged
+			if existingCustomer == nil {
+				existingCustomer, err = u.Restorer.GetRestorable(ctx, data.Email)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			if existingCustomer != nil && existingCustomer.ID != customerID {
 				return nil, &e.ValidationError{Field: "email", Err: "email already in use"}
 			}
 
 			customer.Email = data.Email
+			// a new address has to be confirmed again
+			customer.VerifiedAt = time.Time{}
+			emailChanged = true
 		}
 	}
 
@@ -61,6 +87,12 @@ func (u *UpdateCustomerUseCase) UpdateCustomer(ctx context.Context, currentCusto
 		return nil, err
 	}
 
+	if emailChanged {
+		if err := u.Verifier.SendVerification(ctx, customer); err != nil {
+			fmt.Printf("[update_customer_usecase] ERROR sending verification email: %v\n", err)
+		}
+	}
+
 	return &domain.OutgoingCustomer{
 		ID:        customer.ID,
 		Name:      customer.Name,
@@ -21,15 +21,18 @@ func TestUpdateCustomerUseCase_UpdateCustomer(t *testing.T) {
 	mockUpdater := mocks.NewMockUpdateCustomerRepository(ctrl)
 	mockGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
 	mockEmailGetter := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockRestorer := mocks.NewMockAccountRestorer(ctrl)
+	mockVerifier := mocks.NewMockEmailVerificationSender(ctrl)
 
 	getBaseCustomer := func() *domain.Customer {
 		return &domain.Customer{
-			ID:        "customer_123",
-			Name:      "Old Name",
-			Email:     "old@example.com",
-			Password:  "hashed_password",
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			ID:         "customer_123",
+			Name:       "Old Name",
+			Email:      "old@example.com",
+			Password:   "hashed_password",
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+			VerifiedAt: time.Now(),
 		}
 	}
 
@@ -60,12 +63,23 @@ func TestUpdateCustomerUseCase_UpdateCustomer(t *testing.T) {
 					GetByEmail(gomock.Any(), "new@example.com").
 					Return(nil, nil)
 
+				mockRestorer.EXPECT().
+					GetRestorable(gomock.Any(), "new@example.com").
+					Return(nil, nil)
+
 				updatedCustomer := *customer
 				updatedCustomer.Name = "New Name"
 				updatedCustomer.Email = "new@example.com"
 
 				mockUpdater.EXPECT().
 					Update(gomock.Any(), matchesCustomer(&updatedCustomer)).
+					DoAndReturn(func(ctx context.Context, c *domain.Customer) error {
+						assert.True(t, c.VerifiedAt.IsZero())
+						return nil
+					})
+
+				mockVerifier.EXPECT().
+					SendVerification(gomock.Any(), matchesCustomer(&updatedCustomer)).
 					Return(nil)
 			},
 			expectedCustomer: &domain.OutgoingCustomer{
@@ -101,6 +115,21 @@ func TestUpdateCustomerUseCase_UpdateCustomer(t *testing.T) {
 			expectedCustomer: nil,
 			expectedError:    errors.New("customer not found"),
 		},
+		{
+			name:              "unknown or deleted customer",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			updateData: domain.CustomerEditableFields{
+				Name: "New Name",
+			},
+			setupMocks: func() {
+				mockGetter.EXPECT().
+					GetByID(gomock.Any(), "customer_123").
+					Return(nil, nil)
+			},
+			expectedCustomer: nil,
+			expectedError:    e.NewNotFoundError("customer"),
+		},
 		{
 			name:              "error retrieving customer by email",
 			currentCustomerID: "customer_123",
@@ -141,6 +170,30 @@ func TestUpdateCustomerUseCase_UpdateCustomer(t *testing.T) {
 			expectedCustomer: nil,
 			expectedError:    &e.ValidationError{Field: "email", Err: "email already in use"},
 		},
+		{
+			name:              "email of a deleted account",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			updateData: domain.CustomerEditableFields{
+				Email: "deleted@example.com",
+			},
+			setupMocks: func() {
+				customer := getBaseCustomer()
+				mockGetter.EXPECT().
+					GetByID(gomock.Any(), "customer_123").
+					Return(customer, nil)
+
+				mockEmailGetter.EXPECT().
+					GetByEmail(gomock.Any(), "deleted@example.com").
+					Return(nil, nil)
+
+				mockRestorer.EXPECT().
+					GetRestorable(gomock.Any(), "deleted@example.com").
+					Return(&domain.Customer{ID: "deleted_customer", DeletedAt: time.Now()}, nil)
+			},
+			expectedCustomer: nil,
+			expectedError:    &e.ValidationError{Field: "email", Err: "email already in use"},
+		},
 		{
 			name:              "successful partial update - name only",
 			currentCustomerID: "customer_123",
@@ -168,6 +221,42 @@ func TestUpdateCustomerUseCase_UpdateCustomer(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:              "verification email failure does not fail the update",
+			currentCustomerID: "customer_123",
+			customerID:        "customer_123",
+			updateData: domain.CustomerEditableFields{
+				Email: "new@example.com",
+			},
+			setupMocks: func() {
+				customer := getBaseCustomer()
+				mockGetter.EXPECT().
+					GetByID(gomock.Any(), "customer_123").
+					Return(customer, nil)
+
+				mockEmailGetter.EXPECT().
+					GetByEmail(gomock.Any(), "new@example.com").
+					Return(nil, nil)
+
+				mockRestorer.EXPECT().
+					GetRestorable(gomock.Any(), "new@example.com").
+					Return(nil, nil)
+
+				mockUpdater.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					Return(nil)
+
+				mockVerifier.EXPECT().
+					SendVerification(gomock.Any(), gomock.Any()).
+					Return(errors.New("smtp error"))
+			},
+			expectedCustomer: &domain.OutgoingCustomer{
+				ID:    "customer_123",
+				Name:  "Old Name",
+				Email: "new@example.com",
+			},
+			expectedError: nil,
+		},
 		{
 			name:              "repository update error",
 			currentCustomerID: "customer_123",
@@ -194,7 +283,7 @@ func TestUpdateCustomerUseCase_UpdateCustomer(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
 
-			uc := usecase.NewUpdateCustomerUseCase(mockUpdater, mockGetter, mockEmailGetter)
+			uc := usecase.NewUpdateCustomerUseCase(mockUpdater, mockGetter, mockEmailGetter, mockRestorer, mockVerifier, ownerOnlyPolicy(ctrl))
 			customer, err := uc.UpdateCustomer(context.Background(), tt.currentCustomerID, tt.customerID, tt.updateData)
 
 			if tt.expectedError != nil {
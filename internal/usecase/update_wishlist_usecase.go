@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/ydoro/wishlist/internal/domain"
 	e "github.com/ydoro/wishlist/internal/domain/errors"
@@ -14,6 +15,7 @@ type UpdateWishListUseCase struct {
 	getterRepository   domain.WishlistByIdRepository
 	updateRepository   domain.UpdateWishlistRepository
 	productGetter      domain.GetProductUseCase
+	wishlistPolicy     domain.WishlistPolicy
 }
 
 func NewUpdateWishListUseCase(
@@ -21,18 +23,20 @@ func NewUpdateWishListUseCase(
 	getterRepository domain.WishlistByIdRepository,
 	updateRepository domain.UpdateWishlistRepository,
 	productGetter domain.GetProductUseCase,
+	wishlistPolicy domain.WishlistPolicy,
 ) *UpdateWishListUseCase {
 	return &UpdateWishListUseCase{
 		customerRepository: customerRepository,
 		getterRepository:   getterRepository,
 		updateRepository:   updateRepository,
 		productGetter:      productGetter,
+		wishlistPolicy:     wishlistPolicy,
 	}
 }
 
 func (u *UpdateWishListUseCase) UpdateWishlist(ctx context.Context, currentCustomerId string, wishlist *domain.Wishlist) error {
-	if currentCustomerId != wishlist.CustomerId {
-		return e.NewUnauthorizedError()
+	if err := u.wishlistPolicy.AuthorizeWishlist(ctx, currentCustomerId, domain.PermissionWishlistWrite, wishlist.CustomerId, wishlist.ID); err != nil {
+		return err
 	}
 
 	customer, err := u.customerRepository.GetByID(ctx, wishlist.CustomerId)
@@ -53,11 +57,11 @@ func (u *UpdateWishListUseCase) UpdateWishlist(ctx context.Context, currentCusto
 		return e.NewNotFoundError("wishlist")
 	}
 
-	if dbWishlist.CustomerId != currentCustomerId {
+	if dbWishlist.CustomerId != wishlist.CustomerId {
 		return e.NewUnauthorizedError()
 	}
 
-	if wishlist.Title == dbWishlist.Title && slices.Equal(wishlist.Items, dbWishlist.Items) {
+	if wishlist.Title == dbWishlist.Title && slices.Equal(wishlist.ProductIDs(), dbWishlist.ProductIDs()) {
 		return nil
 	}
 
@@ -66,8 +70,16 @@ func (u *UpdateWishListUseCase) UpdateWishlist(ctx context.Context, currentCusto
 	}
 
 	if wishlist.Items != nil {
-		var newProducts []string
-		for _, productId := range wishlist.Items {
+		// products already in the wishlist keep their metadata, the order of the request becomes the new order
+		newItems := make([]domain.WishlistItem, 0, len(wishlist.Items))
+		seen := make(map[string]bool, len(wishlist.Items))
+		for _, requested := range wishlist.Items {
+			productId := requested.ProductID
+			if seen[productId] {
+				continue
+			}
+			seen[productId] = true
+
 			product, err := u.productGetter.Execute(ctx, productId)
 
 			if err != nil {
@@ -78,10 +90,20 @@ func (u *UpdateWishListUseCase) UpdateWishlist(ctx context.Context, currentCusto
 				return e.NewNotFoundError(fmt.Sprintf("product_%s", productId))
 			}
 
-			newProducts = append(newProducts, productId)
+			item := domain.WishlistItem{
+				ProductID: productId,
+				Quantity:  domain.DefaultWishlistItemQuantity,
+				AddedAt:   time.Now(),
+			}
+			if existing := dbWishlist.Item(productId); existing != nil {
+				item = *existing
+			}
+			item.Position = len(newItems)
+
+			newItems = append(newItems, item)
 		}
 
-		dbWishlist.Items = newProducts
+		dbWishlist.Items = newItems
 	}
 
 	return u.updateRepository.Update(ctx, dbWishlist)
@@ -109,7 +109,7 @@ func TestUpdateWishListUseCase(t *testing.T) {
 			products:          []string{"product1"},
 			setupMocks: func() {
 				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer1").Return(&domain.Customer{ID: "customer1"}, nil)
-				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist1").Return(&domain.Wishlist{ID: "wishlist1", Title: "superlist", CustomerId: "customer1", Items: []string{"product1"}}, nil)
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist1").Return(&domain.Wishlist{ID: "wishlist1", Title: "superlist", CustomerId: "customer1", Items: []domain.WishlistItem{{ProductID: "product1"}}}, nil)
 			},
 			expectedError: nil,
 		},
@@ -183,6 +183,34 @@ func TestUpdateWishListUseCase(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:              "should keep the metadata of products already in the wishlist",
+			currentCustomerID: "customer1",
+			customerID:        "customer1",
+			wishlistID:        "wishlist1",
+			products:          []string{"product2", "product1", "product2"},
+			setupMocks: func() {
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer1").Return(&domain.Customer{ID: "customer1"}, nil)
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist1").Return(&domain.Wishlist{
+					ID:         "wishlist1",
+					CustomerId: "customer1",
+					Items:      []domain.WishlistItem{{ProductID: "product1", Quantity: 3, Note: "blue"}},
+				}, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product2").Return(&domain.Product{ID: "product2"}, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product1").Return(&domain.Product{ID: "product1"}, nil)
+				mockWishlistUpdater.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, wishlist *domain.Wishlist) error {
+						assert.Len(t, wishlist.Items, 2)
+						assert.Equal(t, "product2", wishlist.Items[0].ProductID)
+						assert.Equal(t, domain.DefaultWishlistItemQuantity, wishlist.Items[0].Quantity)
+						assert.Equal(t, 0, wishlist.Items[0].Position)
+						assert.Equal(t, domain.WishlistItem{ProductID: "product1", Quantity: 3, Note: "blue", Position: 1}, wishlist.Items[1])
+						return nil
+					})
+			},
+			expectedError: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -194,13 +222,19 @@ func TestUpdateWishListUseCase(t *testing.T) {
 				mockWishlistGetter,
 				mockWishlistUpdater,
 				mockProductGetter,
+				ownerOnlyWishlistPolicy(ctrl),
 			)
 
+			var items []domain.WishlistItem
+			for _, productID := range tt.products {
+				items = append(items, domain.WishlistItem{ProductID: productID})
+			}
+
 			err := uc.UpdateWishlist(context.Background(), tt.currentCustomerID, &domain.Wishlist{
 				ID:         tt.wishlistID,
 				Title:      tt.wishlistTitle,
 				CustomerId: tt.customerID,
-				Items:      tt.products,
+				Items:      items,
 			})
 			assert.Equal(t, err, tt.expectedError)
 		})
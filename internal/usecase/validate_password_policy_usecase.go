@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+// personal info shorter than this is too likely to show up by chance
+const minPersonalInfoLength = 3
+
+type ValidatePasswordPolicyUseCase struct {
+	config   domain.PasswordPolicyConfig
+	breached domain.BreachedPasswordRange
+}
+
+func NewValidatePasswordPolicyUseCase(config domain.PasswordPolicyConfig, breached domain.BreachedPasswordRange) *ValidatePasswordPolicyUseCase {
+	return &ValidatePasswordPolicyUseCase{
+		config:   config,
+		breached: breached,
+	}
+}
+
+// Validate runs every rule and returns all the ones the password failed as a ValidationErrors list
+func (u *ValidatePasswordPolicyUseCase) Validate(password string, customer *domain.Customer) error {
+	if password == "" {
+		return e.NewRequiredFieldError("password")
+	}
+
+	var violations []*e.ValidationError
+	violate := func(message string) {
+		violations = append(violations, &e.ValidationError{Field: "password", Err: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < u.config.MinLength {
+		violate(fmt.Sprintf("must have at least %d characters", u.config.MinLength))
+	}
+
+	// keeps the hashing cost bounded, bcrypt hashes also ignored everything after 72 bytes
+	if u.config.MaxLength > 0 && len(password) > u.config.MaxLength {
+		violate(fmt.Sprintf("must have at most %d bytes", u.config.MaxLength))
+	}
+
+	if charClasses(password) < u.config.MinCharClasses {
+		violate(fmt.Sprintf("must mix at least %d of lowercase letters, uppercase letters, digits and symbols", u.config.MinCharClasses))
+	}
+
+	if u.config.BlockPersonalInfo && containsPersonalInfo(password, customer) {
+		violate("must not contain your name or email")
+	}
+
+	breached, err := u.isBreached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		violate("has appeared in a data breach, please choose another one")
+	}
+
+	return e.NewValidationErrors(violations)
+}
+
+// isBreached only sends the first 5 characters of the SHA-1 to the range source
+func (u *ValidatePasswordPolicyUseCase) isBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := u.breached.Range(hash[:5])
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(suffixes, hash[5:]), nil
+}
+
+func charClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes
+}
+
+func containsPersonalInfo(password string, customer *domain.Customer) bool {
+	if customer == nil {
+		return false
+	}
+
+	candidates := strings.Fields(customer.Name)
+	if local, _, found := strings.Cut(customer.Email, "@"); found {
+		candidates = append(candidates, local)
+	}
+
+	password = strings.ToLower(password)
+	for _, candidate := range candidates {
+		if utf8.RuneCountInString(candidate) < minPersonalInfoLength {
+			continue
+		}
+		if strings.Contains(password, strings.ToLower(candidate)) {
+			return true
+		}
+	}
+
+	return false
+}
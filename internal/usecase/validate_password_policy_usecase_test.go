@@ -0,0 +1,119 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidatePasswordPolicyUseCase_Validate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBreached := mocks.NewMockBreachedPasswordRange(ctrl)
+
+	policy := usecase.NewValidatePasswordPolicyUseCase(domain.PasswordPolicyConfig{
+		MinLength:         8,
+		MaxLength:         72,
+		MinCharClasses:    2,
+		BlockPersonalInfo: true,
+	}, mockBreached)
+
+	customer := &domain.Customer{Name: "John Doe", Email: "jdoe@example.com"}
+
+	tests := []struct {
+		name          string
+		password      string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:     "valid password",
+			password: "a-long-enough-password",
+			setupMocks: func() {
+				mockBreached.EXPECT().Range(gomock.Any()).Return([]string{"0000000000000000000000000000000000A"}, nil)
+			},
+		},
+		{
+			name:          "empty password",
+			password:      "",
+			setupMocks:    func() {},
+			expectedError: e.NewRequiredFieldError("password"),
+		},
+		{
+			name:     "too short",
+			password: "sh0rt",
+			setupMocks: func() {
+				mockBreached.EXPECT().Range(gomock.Any()).Return(nil, nil)
+			},
+			expectedError: &e.ValidationError{Field: "password", Err: "must have at least 8 characters"},
+		},
+		{
+			name:     "too long",
+			password: strings.Repeat("Ab1", 25),
+			setupMocks: func() {
+				mockBreached.EXPECT().Range(gomock.Any()).Return(nil, nil)
+			},
+			expectedError: &e.ValidationError{Field: "password", Err: "must have at most 72 bytes"},
+		},
+		{
+			name:     "every failed rule is listed",
+			password: "johnny",
+			setupMocks: func() {
+				mockBreached.EXPECT().Range(gomock.Any()).Return(nil, nil)
+			},
+			expectedError: e.ValidationErrors{
+				{Field: "password", Err: "must have at least 8 characters"},
+				{Field: "password", Err: "must mix at least 2 of lowercase letters, uppercase letters, digits and symbols"},
+				{Field: "password", Err: "must not contain your name or email"},
+			},
+		},
+		{
+			name:     "contains the email",
+			password: "my-JDOE-password",
+			setupMocks: func() {
+				mockBreached.EXPECT().Range(gomock.Any()).Return(nil, nil)
+			},
+			expectedError: &e.ValidationError{Field: "password", Err: "must not contain your name or email"},
+		},
+		{
+			name:     "breached password",
+			password: "P@ssw0rd",
+			setupMocks: func() {
+				// SHA-1 of "P@ssw0rd" is 21BD12DC183F740EE76F27B78EB39C8AD972A757
+				mockBreached.EXPECT().Range("21BD1").Return([]string{"2DC183F740EE76F27B78EB39C8AD972A757"}, nil)
+			},
+			expectedError: &e.ValidationError{Field: "password", Err: "has appeared in a data breach, please choose another one"},
+		},
+		{
+			name:     "breached list failure",
+			password: "a-long-enough-password",
+			setupMocks: func() {
+				mockBreached.EXPECT().Range(gomock.Any()).Return(nil, errors.New("read error"))
+			},
+			expectedError: errors.New("read error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			err := policy.Validate(tt.password, customer)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
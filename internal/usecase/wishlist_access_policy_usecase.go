@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ydoro/wishlist/internal/domain"
+)
+
+type WishlistAccessPolicyUseCase struct {
+	collaboratorGetter domain.GetCollaboratorRepository
+	accessPolicy       domain.Policy
+}
+
+func NewWishlistAccessPolicyUseCase(
+	collaboratorGetter domain.GetCollaboratorRepository,
+	accessPolicy domain.Policy,
+) *WishlistAccessPolicyUseCase {
+	return &WishlistAccessPolicyUseCase{
+		collaboratorGetter: collaboratorGetter,
+		accessPolicy:       accessPolicy,
+	}
+}
+
+// AuthorizeWishlist lets accepted collaborators use the permissions of their role on the wishlist,
+// owners and staff roles go through the access policy so their accesses keep being audited
+func (u *WishlistAccessPolicyUseCase) AuthorizeWishlist(ctx context.Context, actorID string, permission domain.Permission, ownerID string, wishlistID string) error {
+	if actorID != "" && actorID != ownerID {
+		collaborator, err := u.collaboratorGetter.Get(ctx, wishlistID, actorID)
+		if err != nil {
+			return err
+		}
+
+		if collaborator != nil && collaborator.Accepted() && collaborator.Role.Can(permission) {
+			return nil
+		}
+	}
+
+	return u.accessPolicy.Authorize(ctx, actorID, permission, ownerID)
+}
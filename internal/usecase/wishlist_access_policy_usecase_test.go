@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWishlistAccessPolicyUseCase_AuthorizeWishlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCollaboratorGetter := mocks.NewMockGetCollaboratorRepository(ctrl)
+
+	acceptedAt := time.Now()
+	viewer := &domain.WishlistCollaborator{WishlistID: "wishlist_123", CustomerID: "friend_123", Role: domain.CollaboratorRoleViewer, AcceptedAt: &acceptedAt}
+	editor := &domain.WishlistCollaborator{WishlistID: "wishlist_123", CustomerID: "friend_123", Role: domain.CollaboratorRoleEditor, AcceptedAt: &acceptedAt}
+	pending := &domain.WishlistCollaborator{WishlistID: "wishlist_123", CustomerID: "friend_123", Role: domain.CollaboratorRoleEditor}
+
+	tests := []struct {
+		name          string
+		actorID       string
+		permission    domain.Permission
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name:       "owner",
+			actorID:    "customer_123",
+			permission: domain.PermissionWishlistWrite,
+			setupMocks: func() {},
+		},
+		{
+			name:       "viewer reads",
+			actorID:    "friend_123",
+			permission: domain.PermissionWishlistRead,
+			setupMocks: func() {
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(viewer, nil)
+			},
+		},
+		{
+			name:       "viewer can't write",
+			actorID:    "friend_123",
+			permission: domain.PermissionWishlistWrite,
+			setupMocks: func() {
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(viewer, nil)
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "editor writes",
+			actorID:    "friend_123",
+			permission: domain.PermissionWishlistWrite,
+			setupMocks: func() {
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(editor, nil)
+			},
+		},
+		{
+			name:       "pending invitation",
+			actorID:    "friend_123",
+			permission: domain.PermissionWishlistRead,
+			setupMocks: func() {
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(pending, nil)
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "not a collaborator",
+			actorID:    "other_123",
+			permission: domain.PermissionWishlistRead,
+			setupMocks: func() {
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "other_123").Return(nil, nil)
+			},
+			expectedError: e.NewUnauthorizedError(),
+		},
+		{
+			name:       "repository error",
+			actorID:    "friend_123",
+			permission: domain.PermissionWishlistRead,
+			setupMocks: func() {
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistAccessPolicyUseCase(mockCollaboratorGetter, ownerOnlyPolicy(ctrl))
+			err := uc.AuthorizeWishlist(context.Background(), tt.actorID, tt.permission, "customer_123", "wishlist_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// ownerOnlyWishlistPolicy is the wishlist policy of a wishlist without collaborators
+func ownerOnlyWishlistPolicy(ctrl *gomock.Controller) *mocks.MockWishlistPolicy {
+	policy := mocks.NewMockWishlistPolicy(ctrl)
+	policy.EXPECT().
+		AuthorizeWishlist(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, actorID string, permission domain.Permission, ownerID string, wishlistID string) error {
+			if actorID != ownerID {
+				return e.NewUnauthorizedError()
+			}
+			return nil
+		}).
+		AnyTimes()
+
+	return policy
+}
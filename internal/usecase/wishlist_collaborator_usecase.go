@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type WishlistCollaboratorUseCase struct {
+	customerGetter      domain.GetCustomerByIDRepository
+	customerByEmail     domain.GetCustomerByEmailRepository
+	wishlistGetter      domain.WishlistByIdRepository
+	collaboratorSaver   domain.SaveCollaboratorRepository
+	collaboratorGetter  domain.GetCollaboratorRepository
+	collaboratorLister  domain.ListCollaboratorsRepository
+	collaboratorRemover domain.RemoveCollaboratorRepository
+	mailer              domain.Mailer
+	runner              domain.BackgroundRunner
+	emailGuard          domain.VerifiedEmailGuard
+	accessPolicy        domain.Policy
+}
+
+func NewWishlistCollaboratorUseCase(
+	customerGetter domain.GetCustomerByIDRepository,
+	customerByEmail domain.GetCustomerByEmailRepository,
+	wishlistGetter domain.WishlistByIdRepository,
+	collaboratorSaver domain.SaveCollaboratorRepository,
+	collaboratorGetter domain.GetCollaboratorRepository,
+	collaboratorLister domain.ListCollaboratorsRepository,
+	collaboratorRemover domain.RemoveCollaboratorRepository,
+	mailer domain.Mailer,
+	runner domain.BackgroundRunner,
+	emailGuard domain.VerifiedEmailGuard,
+	accessPolicy domain.Policy,
+) *WishlistCollaboratorUseCase {
+	return &WishlistCollaboratorUseCase{
+		customerGetter:      customerGetter,
+		customerByEmail:     customerByEmail,
+		wishlistGetter:      wishlistGetter,
+		collaboratorSaver:   collaboratorSaver,
+		collaboratorGetter:  collaboratorGetter,
+		collaboratorLister:  collaboratorLister,
+		collaboratorRemover: collaboratorRemover,
+		mailer:              mailer,
+		runner:              runner,
+		emailGuard:          emailGuard,
+		accessPolicy:        accessPolicy,
+	}
+}
+
+// InviteCollaborator answers the same way whether the email is registered or not, unknown emails are
+// skipped and the invitation email is sent in the background so its delivery doesn't tell them apart
+func (u *WishlistCollaboratorUseCase) InviteCollaborator(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, email string, role domain.CollaboratorRole) error {
+	wishlist, err := u.ownedWishlist(ctx, currentCustomerID, domain.PermissionWishlistWrite, customerID, wishlistID)
+	if err != nil {
+		return err
+	}
+
+	// the invitation comes from the owner even when support sends it for them
+	if err := ensureVerifiedCustomer(ctx, u.customerGetter, u.emailGuard, customerID); err != nil {
+		return err
+	}
+
+	if !role.Valid() {
+		return &e.ValidationError{Field: "role", Err: "must be either viewer or editor"}
+	}
+
+	invitee, err := u.customerByEmail.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return err
+	}
+
+	if invitee == nil {
+		return nil
+	}
+
+	if invitee.ID == customerID {
+		return &e.ValidationError{Field: "email", Err: "belongs to the owner of the wishlist"}
+	}
+
+	collaborator, err := u.collaboratorGetter.Get(ctx, wishlistID, invitee.ID)
+	if err != nil {
+		return err
+	}
+
+	// inviting again only changes the role, an accepted invitation stays accepted
+	if collaborator == nil {
+		collaborator = &domain.WishlistCollaborator{
+			WishlistID: wishlistID,
+			CustomerID: invitee.ID,
+			InvitedAt:  time.Now(),
+		}
+	}
+	collaborator.Role = role
+
+	if err := u.collaboratorSaver.Save(ctx, collaborator); err != nil {
+		return err
+	}
+
+	if collaborator.Accepted() {
+		return nil
+	}
+
+	u.runner.Go(func(ctx context.Context) {
+		if err := u.sendInvitation(ctx, invitee, customerID, wishlist, role); err != nil {
+			fmt.Printf("[wishlist_collaborator_usecase] ERROR sending invitation email: %v\n", err)
+		}
+	})
+
+	return nil
+}
+
+func (u *WishlistCollaboratorUseCase) sendInvitation(ctx context.Context, invitee *domain.Customer, customerID string, wishlist *domain.Wishlist, role domain.CollaboratorRole) error {
+	owner, err := u.customerGetter.GetByID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	ownerName := "A customer"
+	if owner != nil {
+		ownerName = owner.Name
+	}
+
+	return u.mailer.Send(ctx, domain.MailMessage{
+		To:      invitee.Email,
+		Subject: "You were invited to a wishlist",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n%s invited you as %s of the wishlist \"%s\".\n\nTo accept, sign in and send POST /api/customers/%s/wishlists/%s/collaborators/accept\n\nIf you don't know them you can ignore this email.",
+			invitee.Name,
+			ownerName,
+			role,
+			wishlist.Title,
+			customerID,
+			wishlist.ID,
+		),
+	})
+}
+
+func (u *WishlistCollaboratorUseCase) AcceptInvitation(ctx context.Context, currentCustomerID string, customerID string, wishlistID string) (*domain.WishlistCollaborator, error) {
+	if currentCustomerID == "" {
+		return nil, e.NewUnauthorizedError()
+	}
+
+	wishlist, err := u.wishlistGetter.GetById(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator, err := u.collaboratorGetter.Get(ctx, wishlistID, currentCustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wishlist == nil || wishlist.CustomerId != customerID || collaborator == nil {
+		return nil, e.NewNotFoundError("invitation")
+	}
+
+	if collaborator.Accepted() {
+		return collaborator, nil
+	}
+
+	acceptedAt := time.Now()
+	collaborator.AcceptedAt = &acceptedAt
+
+	if err := u.collaboratorSaver.Save(ctx, collaborator); err != nil {
+		return nil, err
+	}
+
+	return collaborator, nil
+}
+
+func (u *WishlistCollaboratorUseCase) ListCollaborators(ctx context.Context, currentCustomerID string, customerID string, wishlistID string) ([]*domain.WishlistCollaborator, error) {
+	if _, err := u.ownedWishlist(ctx, currentCustomerID, domain.PermissionWishlistRead, customerID, wishlistID); err != nil {
+		return nil, err
+	}
+
+	return u.collaboratorLister.ListByWishlistID(ctx, wishlistID)
+}
+
+func (u *WishlistCollaboratorUseCase) RemoveCollaborator(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, collaboratorID string) error {
+	if _, err := u.ownedWishlist(ctx, currentCustomerID, domain.PermissionWishlistWrite, customerID, wishlistID); err != nil {
+		return err
+	}
+
+	removed, err := u.collaboratorRemover.Remove(ctx, wishlistID, collaboratorID)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return e.NewNotFoundError("collaborator")
+	}
+
+	return nil
+}
+
+// ownedWishlist goes through the access policy and not the wishlist policy,
+// collaborators can't manage the other collaborators
+func (u *WishlistCollaboratorUseCase) ownedWishlist(ctx context.Context, currentCustomerID string, permission domain.Permission, customerID string, wishlistID string) (*domain.Wishlist, error) {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, permission, customerID); err != nil {
+		return nil, err
+	}
+
+	wishlist, err := u.wishlistGetter.GetById(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wishlist == nil || wishlist.CustomerId != customerID {
+		return nil, e.NewNotFoundError("wishlist")
+	}
+
+	return wishlist, nil
+}
@@ -0,0 +1,375 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWishlistCollaboratorUseCase_InviteCollaborator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockCustomerByEmail := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockCollaboratorSaver := mocks.NewMockSaveCollaboratorRepository(ctrl)
+	mockCollaboratorGetter := mocks.NewMockGetCollaboratorRepository(ctrl)
+	mockCollaboratorLister := mocks.NewMockListCollaboratorsRepository(ctrl)
+	mockCollaboratorRemover := mocks.NewMockRemoveCollaboratorRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockEmailGuard := mocks.NewMockVerifiedEmailGuard(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+
+	ownWishlist := &domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123", Title: "Home"}
+	owner := &domain.Customer{ID: "customer_123", Name: "John"}
+	friend := &domain.Customer{ID: "friend_123", Name: "Jane", Email: "jane@example.com"}
+	acceptedAt := time.Now()
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		policy            domain.Policy
+		role              domain.CollaboratorRole
+		setupMocks        func()
+		expectedError     error
+	}{
+		{
+			name:              "invites and emails the customer",
+			currentCustomerID: "customer_123",
+			role:              domain.CollaboratorRoleEditor,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockCustomerByEmail.EXPECT().GetByEmail(gomock.Any(), "jane@example.com").Return(friend, nil)
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(nil, nil)
+				mockCollaboratorSaver.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, collaborator *domain.WishlistCollaborator) error {
+						assert.Equal(t, "friend_123", collaborator.CustomerID)
+						assert.Equal(t, domain.CollaboratorRoleEditor, collaborator.Role)
+						assert.False(t, collaborator.Accepted())
+						return nil
+					})
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockMailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, message domain.MailMessage) error {
+						assert.Equal(t, "jane@example.com", message.To)
+						assert.Contains(t, message.Body, "John invited you as editor")
+						return nil
+					})
+			},
+		},
+		{
+			name:              "changing the role of an accepted collaborator keeps it accepted",
+			currentCustomerID: "customer_123",
+			role:              domain.CollaboratorRoleViewer,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockCustomerByEmail.EXPECT().GetByEmail(gomock.Any(), "jane@example.com").Return(friend, nil)
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(&domain.WishlistCollaborator{
+					WishlistID: "wishlist_123",
+					CustomerID: "friend_123",
+					Role:       domain.CollaboratorRoleEditor,
+					AcceptedAt: &acceptedAt,
+				}, nil)
+				mockCollaboratorSaver.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, collaborator *domain.WishlistCollaborator) error {
+						assert.Equal(t, domain.CollaboratorRoleViewer, collaborator.Role)
+						assert.True(t, collaborator.Accepted())
+						return nil
+					})
+			},
+		},
+		{
+			name:              "unknown role",
+			currentCustomerID: "customer_123",
+			role:              "owner",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+			},
+			expectedError: &e.ValidationError{Field: "role", Err: "must be either viewer or editor"},
+		},
+		{
+			name:              "inviting the owner",
+			currentCustomerID: "customer_123",
+			role:              domain.CollaboratorRoleViewer,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockCustomerByEmail.EXPECT().GetByEmail(gomock.Any(), "jane@example.com").Return(&domain.Customer{ID: "customer_123"}, nil)
+			},
+			expectedError: &e.ValidationError{Field: "email", Err: "belongs to the owner of the wishlist"},
+		},
+		{
+			name:              "unknown email answers like a registered one",
+			currentCustomerID: "customer_123",
+			role:              domain.CollaboratorRoleViewer,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockCustomerByEmail.EXPECT().GetByEmail(gomock.Any(), "jane@example.com").Return(nil, nil)
+			},
+		},
+		{
+			name:              "mail delivery failures are not returned",
+			currentCustomerID: "customer_123",
+			role:              domain.CollaboratorRoleViewer,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockCustomerByEmail.EXPECT().GetByEmail(gomock.Any(), "jane@example.com").Return(friend, nil)
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(nil, nil)
+				mockCollaboratorSaver.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+			},
+		},
+		{
+			name:              "unverified email",
+			currentCustomerID: "customer_123",
+			role:              domain.CollaboratorRoleViewer,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(e.NewForbiddenError("email address is not verified"))
+			},
+			expectedError: e.NewForbiddenError("email address is not verified"),
+		},
+		{
+			name:              "collaborators can't invite",
+			currentCustomerID: "friend_123",
+			role:              domain.CollaboratorRoleViewer,
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+		{
+			name:              "support invites for an unverified owner",
+			currentCustomerID: "support_123",
+			policy:            mockPolicy,
+			role:              domain.CollaboratorRoleViewer,
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "support_123", domain.PermissionWishlistWrite, "customer_123").Return(nil)
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(e.NewForbiddenError("email address is not verified"))
+			},
+			expectedError: e.NewForbiddenError("email address is not verified"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			policy := tt.policy
+			if policy == nil {
+				policy = ownerOnlyPolicy(ctrl)
+			}
+
+			uc := usecase.NewWishlistCollaboratorUseCase(
+				mockCustomerGetter,
+				mockCustomerByEmail,
+				mockWishlistGetter,
+				mockCollaboratorSaver,
+				mockCollaboratorGetter,
+				mockCollaboratorLister,
+				mockCollaboratorRemover,
+				mockMailer,
+				inlineRunner(ctrl),
+				mockEmailGuard,
+				policy,
+			)
+
+			err := uc.InviteCollaborator(context.Background(), tt.currentCustomerID, "customer_123", "wishlist_123", "jane@example.com", tt.role)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWishlistCollaboratorUseCase_AcceptInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockCustomerByEmail := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockCollaboratorSaver := mocks.NewMockSaveCollaboratorRepository(ctrl)
+	mockCollaboratorGetter := mocks.NewMockGetCollaboratorRepository(ctrl)
+	mockCollaboratorLister := mocks.NewMockListCollaboratorsRepository(ctrl)
+	mockCollaboratorRemover := mocks.NewMockRemoveCollaboratorRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	ownWishlist := &domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "accepts the invitation",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(&domain.WishlistCollaborator{WishlistID: "wishlist_123", CustomerID: "friend_123", Role: domain.CollaboratorRoleViewer}, nil)
+				mockCollaboratorSaver.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, collaborator *domain.WishlistCollaborator) error {
+						assert.True(t, collaborator.Accepted())
+						return nil
+					})
+			},
+		},
+		{
+			name: "not invited",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("invitation"),
+		},
+		{
+			name: "wishlist of another owner",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "other_123"}, nil)
+				mockCollaboratorGetter.EXPECT().Get(gomock.Any(), "wishlist_123", "friend_123").Return(&domain.WishlistCollaborator{WishlistID: "wishlist_123", CustomerID: "friend_123"}, nil)
+			},
+			expectedError: e.NewNotFoundError("invitation"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistCollaboratorUseCase(
+				mockCustomerGetter,
+				mockCustomerByEmail,
+				mockWishlistGetter,
+				mockCollaboratorSaver,
+				mockCollaboratorGetter,
+				mockCollaboratorLister,
+				mockCollaboratorRemover,
+				mockMailer,
+				nil,
+				nil,
+				ownerOnlyPolicy(ctrl),
+			)
+
+			collaborator, err := uc.AcceptInvitation(context.Background(), "friend_123", "customer_123", "wishlist_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, collaborator)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, collaborator.Accepted())
+		})
+	}
+}
+
+func TestWishlistCollaboratorUseCase_RemoveCollaborator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockCustomerByEmail := mocks.NewMockGetCustomerByEmailRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockCollaboratorSaver := mocks.NewMockSaveCollaboratorRepository(ctrl)
+	mockCollaboratorGetter := mocks.NewMockGetCollaboratorRepository(ctrl)
+	mockCollaboratorLister := mocks.NewMockListCollaboratorsRepository(ctrl)
+	mockCollaboratorRemover := mocks.NewMockRemoveCollaboratorRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		setupMocks        func()
+		expectedError     error
+	}{
+		{
+			name:              "removes the collaborator",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}, nil)
+				mockCollaboratorRemover.EXPECT().Remove(gomock.Any(), "wishlist_123", "friend_123").Return(true, nil)
+			},
+		},
+		{
+			name:              "not a collaborator",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}, nil)
+				mockCollaboratorRemover.EXPECT().Remove(gomock.Any(), "wishlist_123", "friend_123").Return(false, nil)
+			},
+			expectedError: e.NewNotFoundError("collaborator"),
+		},
+		{
+			name:              "repository error",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+		{
+			name:              "collaborators can't remove others",
+			currentCustomerID: "friend_123",
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistCollaboratorUseCase(
+				mockCustomerGetter,
+				mockCustomerByEmail,
+				mockWishlistGetter,
+				mockCollaboratorSaver,
+				mockCollaboratorGetter,
+				mockCollaboratorLister,
+				mockCollaboratorRemover,
+				mockMailer,
+				nil,
+				nil,
+				ownerOnlyPolicy(ctrl),
+			)
+
+			err := uc.RemoveCollaborator(context.Background(), tt.currentCustomerID, "customer_123", "wishlist_123", "friend_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+// WishlistItemsUseCase changes one item at a time, unlike UpdateWishListUseCase it never rewrites
+// the whole list so two clients editing the same wishlist don't overwrite each other
+type WishlistItemsUseCase struct {
+	wishlistGetter domain.WishlistByIdRepository
+	itemRepository domain.WishlistItemRepository
+	productGetter  domain.GetProductUseCase
+	wishlistPolicy domain.WishlistPolicy
+}
+
+func NewWishlistItemsUseCase(
+	wishlistGetter domain.WishlistByIdRepository,
+	itemRepository domain.WishlistItemRepository,
+	productGetter domain.GetProductUseCase,
+	wishlistPolicy domain.WishlistPolicy,
+) *WishlistItemsUseCase {
+	return &WishlistItemsUseCase{
+		wishlistGetter: wishlistGetter,
+		itemRepository: itemRepository,
+		productGetter:  productGetter,
+		wishlistPolicy: wishlistPolicy,
+	}
+}
+
+func (u *WishlistItemsUseCase) AddWishlistItem(ctx context.Context, currentCustomerId string, customerId string, wishlistId string, item domain.WishlistItem) error {
+	if err := u.ensureWishlist(ctx, currentCustomerId, customerId, wishlistId); err != nil {
+		return err
+	}
+
+	if item.Quantity == 0 {
+		item.Quantity = domain.DefaultWishlistItemQuantity
+	}
+
+	if err := validateWishlistItem(item); err != nil {
+		return err
+	}
+
+	product, err := u.productGetter.Execute(ctx, item.ProductID)
+	if err != nil {
+		return err
+	}
+
+	if product == nil {
+		return e.NewNotFoundError(fmt.Sprintf("product_%s", item.ProductID))
+	}
+
+	item.AddedAt = time.Now()
+
+	// nothing changes when the product is already in the wishlist, which is fine
+	_, err = u.itemRepository.AddItem(ctx, wishlistId, customerId, &item)
+	return err
+}
+
+func (u *WishlistItemsUseCase) RemoveWishlistItem(ctx context.Context, currentCustomerId string, customerId string, wishlistId string, productId string) error {
+	if err := u.ensureWishlist(ctx, currentCustomerId, customerId, wishlistId); err != nil {
+		return err
+	}
+
+	removed, err := u.itemRepository.RemoveItem(ctx, wishlistId, customerId, productId)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return e.NewNotFoundError("wishlist item")
+	}
+
+	return nil
+}
+
+func (u *WishlistItemsUseCase) ensureWishlist(ctx context.Context, currentCustomerId string, customerId string, wishlistId string) error {
+	if err := u.wishlistPolicy.AuthorizeWishlist(ctx, currentCustomerId, domain.PermissionWishlistWrite, customerId, wishlistId); err != nil {
+		return err
+	}
+
+	wishlist, err := u.wishlistGetter.GetById(ctx, wishlistId)
+	if err != nil {
+		return err
+	}
+
+	if wishlist == nil || wishlist.CustomerId != customerId {
+		return e.NewNotFoundError("wishlist")
+	}
+
+	return nil
+}
+
+func validateWishlistItem(item domain.WishlistItem) error {
+	var errs []*e.ValidationError
+
+	if item.Quantity < 1 {
+		errs = append(errs, &e.ValidationError{Field: "quantity", Err: "must be at least 1"})
+	}
+
+	if item.Priority < 0 {
+		errs = append(errs, &e.ValidationError{Field: "priority", Err: "can't be negative"})
+	}
+
+	if utf8.RuneCountInString(item.Note) > domain.MaxWishlistItemNoteLength {
+		errs = append(errs, &e.ValidationError{Field: "note", Err: fmt.Sprintf("must have at most %d characters", domain.MaxWishlistItemNoteLength)})
+	}
+
+	return e.NewValidationErrors(errs)
+}
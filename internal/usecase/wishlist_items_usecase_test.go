@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWishlistItemsUseCase_AddWishlistItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockItemRepository := mocks.NewMockWishlistItemRepository(ctrl)
+	mockProductGetter := mocks.NewMockGetProductUseCase(ctrl)
+
+	wishlist := &domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123", Items: []domain.WishlistItem{{ProductID: "product_1"}}}
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		item              domain.WishlistItem
+		setupMocks        func()
+		expectedError     error
+	}{
+		{
+			name:              "adds the product with the default quantity",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2", Priority: 2, Note: "size M"},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product_2").Return(&domain.Product{ID: "product_2"}, nil)
+				mockItemRepository.EXPECT().
+					AddItem(gomock.Any(), "wishlist_123", "customer_123", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, wishlistId string, customerId string, item *domain.WishlistItem) (bool, error) {
+						assert.Equal(t, "product_2", item.ProductID)
+						assert.Equal(t, domain.DefaultWishlistItemQuantity, item.Quantity)
+						assert.Equal(t, 2, item.Priority)
+						assert.Equal(t, "size M", item.Note)
+						assert.False(t, item.AddedAt.IsZero())
+						return true, nil
+					})
+			},
+		},
+		{
+			name:              "invalid metadata",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2", Quantity: -1, Priority: -1},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+			},
+			expectedError: e.ValidationErrors{
+				{Field: "quantity", Err: "must be at least 1"},
+				{Field: "priority", Err: "can't be negative"},
+			},
+		},
+		{
+			name:              "note length counts characters",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2", Note: strings.Repeat("é", domain.MaxWishlistItemNoteLength)},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product_2").Return(&domain.Product{ID: "product_2"}, nil)
+				mockItemRepository.EXPECT().AddItem(gomock.Any(), "wishlist_123", "customer_123", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name:              "note too long",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2", Note: strings.Repeat("é", domain.MaxWishlistItemNoteLength+1)},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+			},
+			expectedError: e.ValidationErrors{
+				{Field: "note", Err: "must have at most 500 characters"},
+			},
+		},
+		{
+			name:              "product already in the wishlist",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2"},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product_2").Return(&domain.Product{ID: "product_2"}, nil)
+				mockItemRepository.EXPECT().AddItem(gomock.Any(), "wishlist_123", "customer_123", gomock.Any()).Return(false, nil)
+			},
+		},
+		{
+			name:              "product not found",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2"},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product_2").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("product_product_2"),
+		},
+		{
+			name:              "wishlist of another customer",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2"},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "other_123"}, nil)
+			},
+			expectedError: e.NewNotFoundError("wishlist"),
+		},
+		{
+			name:              "unauthorized",
+			currentCustomerID: "other_123",
+			item:              domain.WishlistItem{ProductID: "product_2"},
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+		{
+			name:              "repository error",
+			currentCustomerID: "customer_123",
+			item:              domain.WishlistItem{ProductID: "product_2"},
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product_2").Return(&domain.Product{ID: "product_2"}, nil)
+				mockItemRepository.EXPECT().AddItem(gomock.Any(), "wishlist_123", "customer_123", gomock.Any()).Return(false, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistItemsUseCase(mockWishlistGetter, mockItemRepository, mockProductGetter, ownerOnlyWishlistPolicy(ctrl))
+			err := uc.AddWishlistItem(context.Background(), tt.currentCustomerID, "customer_123", "wishlist_123", tt.item)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWishlistItemsUseCase_RemoveWishlistItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockItemRepository := mocks.NewMockWishlistItemRepository(ctrl)
+
+	wishlist := &domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123", Items: []domain.WishlistItem{{ProductID: "product_1"}}}
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "removes the product",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+				mockItemRepository.EXPECT().RemoveItem(gomock.Any(), "wishlist_123", "customer_123", "product_1").Return(true, nil)
+			},
+		},
+		{
+			name: "product not in the wishlist",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(wishlist, nil)
+				mockItemRepository.EXPECT().RemoveItem(gomock.Any(), "wishlist_123", "customer_123", "product_1").Return(false, nil)
+			},
+			expectedError: e.NewNotFoundError("wishlist item"),
+		},
+		{
+			name: "wishlist not found",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("wishlist"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistItemsUseCase(mockWishlistGetter, mockItemRepository, nil, ownerOnlyWishlistPolicy(ctrl))
+			err := uc.RemoveWishlistItem(context.Background(), "customer_123", "customer_123", "wishlist_123", "product_1")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+// WishlistReservationUseCase lets viewers of a shared wishlist reserve the items they are going to buy,
+// the share token is the authorization like on the shared view
+type WishlistReservationUseCase struct {
+	customerGetter    domain.GetCustomerByIDRepository
+	wishlistGetter    domain.WishlistByIdRepository
+	tokenHasher       domain.Hasher
+	shareGetter       domain.WishlistShareByHashRepository
+	idGen             domain.IDGenerator
+	reserver          domain.ReserveWishlistItemRepository
+	lister            domain.ListWishlistReservationsRepository
+	releaser          domain.ReleaseWishlistItemRepository
+	preferencesReader domain.PreferencesReader
+	emailGuard        domain.VerifiedEmailGuard
+	ttl               time.Duration
+}
+
+func NewWishlistReservationUseCase(
+	customerGetter domain.GetCustomerByIDRepository,
+	wishlistGetter domain.WishlistByIdRepository,
+	tokenHasher domain.Hasher,
+	shareGetter domain.WishlistShareByHashRepository,
+	idGen domain.IDGenerator,
+	reserver domain.ReserveWishlistItemRepository,
+	lister domain.ListWishlistReservationsRepository,
+	releaser domain.ReleaseWishlistItemRepository,
+	preferencesReader domain.PreferencesReader,
+	emailGuard domain.VerifiedEmailGuard,
+	ttl time.Duration,
+) *WishlistReservationUseCase {
+	return &WishlistReservationUseCase{
+		customerGetter:    customerGetter,
+		wishlistGetter:    wishlistGetter,
+		tokenHasher:       tokenHasher,
+		shareGetter:       shareGetter,
+		idGen:             idGen,
+		reserver:          reserver,
+		lister:            lister,
+		releaser:          releaser,
+		preferencesReader: preferencesReader,
+		emailGuard:        emailGuard,
+		ttl:               ttl,
+	}
+}
+
+func (u *WishlistReservationUseCase) ReserveWishlistItem(ctx context.Context, currentCustomerID string, token string, productID string, anonymous bool) (*domain.WishlistReservation, error) {
+	if currentCustomerID == "" {
+		return nil, e.NewUnauthorizedError()
+	}
+
+	wishlist, err := resolveSharedWishlist(ctx, u.tokenHasher, u.shareGetter, u.wishlistGetter, u.preferencesReader, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// the owner would spoil the surprise for themselves
+	if wishlist.CustomerId == currentCustomerID {
+		return nil, e.NewForbiddenError("you can't reserve items of your own wishlist")
+	}
+
+	if wishlist.Item(productID) == nil {
+		return nil, e.NewNotFoundError("wishlist item")
+	}
+
+	if err := ensureVerifiedCustomer(ctx, u.customerGetter, u.emailGuard, currentCustomerID); err != nil {
+		return nil, err
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate reservation ID"))
+	}
+
+	now := time.Now()
+	reservation := &domain.WishlistReservation{
+		ID:         id,
+		WishlistID: wishlist.ID,
+		ProductID:  productID,
+		CustomerID: currentCustomerID,
+		Anonymous:  anonymous,
+		ReservedAt: now,
+		ExpiresAt:  now.Add(u.ttl),
+	}
+
+	if err := u.reserver.Reserve(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (u *WishlistReservationUseCase) ReleaseWishlistItem(ctx context.Context, currentCustomerID string, token string, productID string) error {
+	if currentCustomerID == "" {
+		return e.NewUnauthorizedError()
+	}
+
+	wishlist, err := resolveSharedWishlist(ctx, u.tokenHasher, u.shareGetter, u.wishlistGetter, u.preferencesReader, token)
+	if err != nil {
+		return err
+	}
+
+	released, err := u.releaser.Release(ctx, wishlist.ID, productID, currentCustomerID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// someone else's reservation is reported the same way as a missing one
+	if !released {
+		return e.NewNotFoundError("reservation")
+	}
+
+	return nil
+}
+
+func (u *WishlistReservationUseCase) GetReservationStatuses(ctx context.Context, viewerID string, wishlist *domain.Wishlist) (map[string]*domain.ReservationStatus, error) {
+	// the owner can open their own link signed out, so signed out viewers never see reservations
+	if viewerID == "" {
+		return nil, nil
+	}
+
+	if viewerID == wishlist.CustomerId {
+		preferences, err := u.preferencesReader.GetCustomerPreferences(ctx, viewerID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !preferences.RevealReservations {
+			return nil, nil
+		}
+	}
+
+	reservations, err := u.lister.ListActiveByWishlistID(ctx, wishlist.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]*domain.ReservationStatus, len(wishlist.Items))
+	for _, item := range wishlist.Items {
+		statuses[item.ProductID] = &domain.ReservationStatus{}
+	}
+
+	for _, reservation := range reservations {
+		status, ok := statuses[reservation.ProductID]
+		if !ok {
+			continue
+		}
+
+		status.Reserved = true
+		if reservation.CustomerID == viewerID {
+			status.ReservedByMe = true
+			status.ExpiresAt = &reservation.ExpiresAt
+		}
+
+		if reservation.Anonymous {
+			continue
+		}
+
+		customer, err := u.customerGetter.GetByID(ctx, reservation.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+
+		if customer != nil {
+			status.ReservedBy = customer.Name
+		}
+	}
+
+	return statuses, nil
+}
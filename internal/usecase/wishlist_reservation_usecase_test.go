@@ -0,0 +1,332 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func expectSharedWishlist(tokenHasher *mocks.MockHasher, shareGetter *mocks.MockWishlistShareByHashRepository, wishlistGetter *mocks.MockWishlistByIdRepository, preferencesReader *mocks.MockPreferencesReader) {
+	tokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+	shareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(&domain.WishlistShare{ID: "share_123", WishlistID: "wishlist_123", CustomerID: "customer_123"}, nil)
+	wishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{
+		ID:         "wishlist_123",
+		CustomerId: "customer_123",
+		Items:      []domain.WishlistItem{{ProductID: "product_1"}},
+	}, nil)
+	preferencesReader.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(domain.DefaultPreferences("customer_123"), nil)
+}
+
+func TestWishlistReservationUseCase_ReserveWishlistItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockShareGetter := mocks.NewMockWishlistShareByHashRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockReserver := mocks.NewMockReserveWishlistItemRepository(ctrl)
+	mockLister := mocks.NewMockListWishlistReservationsRepository(ctrl)
+	mockReleaser := mocks.NewMockReleaseWishlistItemRepository(ctrl)
+	mockPreferencesReader := mocks.NewMockPreferencesReader(ctrl)
+	mockEmailGuard := mocks.NewMockVerifiedEmailGuard(ctrl)
+
+	viewer := &domain.Customer{ID: "viewer_123"}
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		productID         string
+		setupMocks        func()
+		expectedError     error
+	}{
+		{
+			name:              "reserves the item",
+			currentCustomerID: "viewer_123",
+			productID:         "product_1",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "viewer_123").Return(viewer, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(viewer).Return(nil)
+				mockIDGen.EXPECT().Generate().Return("reservation_123", nil)
+				mockReserver.EXPECT().
+					Reserve(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, reservation *domain.WishlistReservation) error {
+						assert.Equal(t, "wishlist_123", reservation.WishlistID)
+						assert.Equal(t, "viewer_123", reservation.CustomerID)
+						assert.True(t, reservation.Anonymous)
+						assert.Equal(t, 30*24*time.Hour, reservation.ExpiresAt.Sub(reservation.ReservedAt))
+						return nil
+					})
+			},
+		},
+		{
+			name:              "someone else reserved it first",
+			currentCustomerID: "viewer_123",
+			productID:         "product_1",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "viewer_123").Return(viewer, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(viewer).Return(nil)
+				mockIDGen.EXPECT().Generate().Return("reservation_123", nil)
+				mockReserver.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(e.NewConflictError("item is already reserved"))
+			},
+			expectedError: e.NewConflictError("item is already reserved"),
+		},
+		{
+			name:              "owner can't reserve their own items",
+			currentCustomerID: "customer_123",
+			productID:         "product_1",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+			},
+			expectedError: e.NewForbiddenError("you can't reserve items of your own wishlist"),
+		},
+		{
+			name:              "product not in the wishlist",
+			currentCustomerID: "viewer_123",
+			productID:         "product_2",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+			},
+			expectedError: e.NewNotFoundError("wishlist item"),
+		},
+		{
+			name:              "unverified email",
+			currentCustomerID: "viewer_123",
+			productID:         "product_1",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "viewer_123").Return(viewer, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(viewer).Return(e.NewForbiddenError("email address is not verified"))
+			},
+			expectedError: e.NewForbiddenError("email address is not verified"),
+		},
+		{
+			name:              "unknown token",
+			currentCustomerID: "viewer_123",
+			productID:         "product_1",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockShareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("shared wishlist"),
+		},
+		{
+			name:          "not signed in",
+			productID:     "product_1",
+			setupMocks:    func() {},
+			expectedError: e.NewUnauthorizedError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistReservationUseCase(
+				mockCustomerGetter,
+				mockWishlistGetter,
+				mockTokenHasher,
+				mockShareGetter,
+				mockIDGen,
+				mockReserver,
+				mockLister,
+				mockReleaser,
+				mockPreferencesReader,
+				mockEmailGuard,
+				30*24*time.Hour,
+			)
+
+			reservation, err := uc.ReserveWishlistItem(context.Background(), tt.currentCustomerID, "share_token", tt.productID, true)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, reservation)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "reservation_123", reservation.ID)
+		})
+	}
+}
+
+func TestWishlistReservationUseCase_ReleaseWishlistItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockShareGetter := mocks.NewMockWishlistShareByHashRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockReserver := mocks.NewMockReserveWishlistItemRepository(ctrl)
+	mockLister := mocks.NewMockListWishlistReservationsRepository(ctrl)
+	mockReleaser := mocks.NewMockReleaseWishlistItemRepository(ctrl)
+	mockPreferencesReader := mocks.NewMockPreferencesReader(ctrl)
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "releases the reservation",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+				mockReleaser.EXPECT().Release(gomock.Any(), "wishlist_123", "product_1", "viewer_123", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name: "no reservation of the viewer",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+				mockReleaser.EXPECT().Release(gomock.Any(), "wishlist_123", "product_1", "viewer_123", gomock.Any()).Return(false, nil)
+			},
+			expectedError: e.NewNotFoundError("reservation"),
+		},
+		{
+			name: "repository error",
+			setupMocks: func() {
+				expectSharedWishlist(mockTokenHasher, mockShareGetter, mockWishlistGetter, mockPreferencesReader)
+				mockReleaser.EXPECT().Release(gomock.Any(), "wishlist_123", "product_1", "viewer_123", gomock.Any()).Return(false, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistReservationUseCase(
+				mockCustomerGetter,
+				mockWishlistGetter,
+				mockTokenHasher,
+				mockShareGetter,
+				mockIDGen,
+				mockReserver,
+				mockLister,
+				mockReleaser,
+				mockPreferencesReader,
+				nil,
+				30*24*time.Hour,
+			)
+
+			err := uc.ReleaseWishlistItem(context.Background(), "viewer_123", "share_token", "product_1")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWishlistReservationUseCase_GetReservationStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockShareGetter := mocks.NewMockWishlistShareByHashRepository(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockReserver := mocks.NewMockReserveWishlistItemRepository(ctrl)
+	mockLister := mocks.NewMockListWishlistReservationsRepository(ctrl)
+	mockReleaser := mocks.NewMockReleaseWishlistItemRepository(ctrl)
+	mockPreferencesReader := mocks.NewMockPreferencesReader(ctrl)
+
+	wishlist := &domain.Wishlist{
+		ID:         "wishlist_123",
+		CustomerId: "customer_123",
+		Items:      []domain.WishlistItem{{ProductID: "product_1"}, {ProductID: "product_2"}, {ProductID: "product_3"}},
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	reservations := []*domain.WishlistReservation{
+		{ProductID: "product_1", CustomerID: "friend_123", ExpiresAt: expiresAt},
+		{ProductID: "product_2", CustomerID: "viewer_123", Anonymous: true, ExpiresAt: expiresAt},
+	}
+
+	tests := []struct {
+		name       string
+		viewerID   string
+		setupMocks func()
+		expected   map[string]*domain.ReservationStatus
+	}{
+		{
+			name:     "viewer sees names and their own reservations",
+			viewerID: "viewer_123",
+			setupMocks: func() {
+				mockLister.EXPECT().ListActiveByWishlistID(gomock.Any(), "wishlist_123", gomock.Any()).Return(reservations, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "friend_123").Return(&domain.Customer{ID: "friend_123", Name: "Mary"}, nil)
+			},
+			expected: map[string]*domain.ReservationStatus{
+				"product_1": {Reserved: true, ReservedBy: "Mary"},
+				"product_2": {Reserved: true, ReservedByMe: true, ExpiresAt: &expiresAt},
+				"product_3": {},
+			},
+		},
+		{
+			name:       "signed out viewer sees nothing",
+			setupMocks: func() {},
+		},
+		{
+			name:     "owner who didn't opt in sees nothing",
+			viewerID: "customer_123",
+			setupMocks: func() {
+				mockPreferencesReader.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(domain.DefaultPreferences("customer_123"), nil)
+			},
+		},
+		{
+			name:     "owner who opted in",
+			viewerID: "customer_123",
+			setupMocks: func() {
+				mockPreferencesReader.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(&domain.Preferences{CustomerID: "customer_123", RevealReservations: true}, nil)
+				mockLister.EXPECT().ListActiveByWishlistID(gomock.Any(), "wishlist_123", gomock.Any()).Return(reservations, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "friend_123").Return(&domain.Customer{ID: "friend_123", Name: "Mary"}, nil)
+			},
+			expected: map[string]*domain.ReservationStatus{
+				"product_1": {Reserved: true, ReservedBy: "Mary"},
+				"product_2": {Reserved: true},
+				"product_3": {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistReservationUseCase(
+				mockCustomerGetter,
+				mockWishlistGetter,
+				mockTokenHasher,
+				mockShareGetter,
+				mockIDGen,
+				mockReserver,
+				mockLister,
+				mockReleaser,
+				mockPreferencesReader,
+				nil,
+				30*24*time.Hour,
+			)
+
+			statuses, err := uc.GetReservationStatuses(context.Background(), tt.viewerID, wishlist)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, statuses)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+)
+
+type WishlistShareUseCase struct {
+	customerGetter domain.GetCustomerByIDRepository
+	wishlistGetter domain.WishlistByIdRepository
+	productGetter  domain.GetProductUseCase
+	idGen          domain.IDGenerator
+	tokenGen       domain.TokenGenerator
+	tokenHasher    domain.Hasher
+	shareStorer    domain.WishlistShareCreationRepository
+	shareLister    domain.ListWishlistSharesRepository
+	shareGetter    domain.WishlistShareByHashRepository
+	shareRevoker   domain.RevokeWishlistShareRepository
+	reservations   domain.ReservationStatusReader
+	preferences    domain.PreferencesReader
+	emailGuard     domain.VerifiedEmailGuard
+	accessPolicy   domain.Policy
+}
+
+func NewWishlistShareUseCase(
+	customerGetter domain.GetCustomerByIDRepository,
+	wishlistGetter domain.WishlistByIdRepository,
+	productGetter domain.GetProductUseCase,
+	idGen domain.IDGenerator,
+	tokenGen domain.TokenGenerator,
+	tokenHasher domain.Hasher,
+	shareStorer domain.WishlistShareCreationRepository,
+	shareLister domain.ListWishlistSharesRepository,
+	shareGetter domain.WishlistShareByHashRepository,
+	shareRevoker domain.RevokeWishlistShareRepository,
+	reservations domain.ReservationStatusReader,
+	preferences domain.PreferencesReader,
+	emailGuard domain.VerifiedEmailGuard,
+	accessPolicy domain.Policy,
+) *WishlistShareUseCase {
+	return &WishlistShareUseCase{
+		customerGetter: customerGetter,
+		wishlistGetter: wishlistGetter,
+		productGetter:  productGetter,
+		idGen:          idGen,
+		tokenGen:       tokenGen,
+		tokenHasher:    tokenHasher,
+		shareStorer:    shareStorer,
+		shareLister:    shareLister,
+		shareGetter:    shareGetter,
+		shareRevoker:   shareRevoker,
+		reservations:   reservations,
+		preferences:    preferences,
+		emailGuard:     emailGuard,
+		accessPolicy:   accessPolicy,
+	}
+}
+
+func (u *WishlistShareUseCase) CreateWishlistShare(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, expiresAt *time.Time) (*domain.CreatedWishlistShare, error) {
+	if err := u.ensureOwnWishlist(ctx, currentCustomerID, customerID, wishlistID); err != nil {
+		return nil, err
+	}
+
+	// the link exposes the owner's wishlist, so the owner is the one who must be verified even when
+	// support creates it for them
+	if err := ensureVerifiedCustomer(ctx, u.customerGetter, u.emailGuard, customerID); err != nil {
+		return nil, err
+	}
+
+	preferences, err := u.preferences.GetCustomerPreferences(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if preferences.WishlistVisibility != domain.WishlistVisibilityShared {
+		return nil, e.NewForbiddenError("wishlists are private, set wishlist_visibility to shared in your preferences to share them")
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, &e.ValidationError{Field: "expires_at", Err: "must be in the future"}
+	}
+
+	token, err := u.tokenGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate share token"))
+	}
+
+	tokenHash, err := u.tokenHasher.Hash(token)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to hash share token"))
+	}
+
+	id, err := u.idGen.Generate()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to generate share ID"))
+	}
+
+	share := domain.WishlistShare{
+		ID:         id,
+		WishlistID: wishlistID,
+		CustomerID: customerID,
+		TokenHash:  tokenHash,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	}
+
+	if err := u.shareStorer.Create(ctx, &share); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedWishlistShare{WishlistShare: share, Token: token}, nil
+}
+
+func (u *WishlistShareUseCase) ListWishlistShares(ctx context.Context, currentCustomerID string, customerID string, wishlistID string) ([]*domain.WishlistShare, error) {
+	if err := u.ensureOwnWishlist(ctx, currentCustomerID, customerID, wishlistID); err != nil {
+		return nil, err
+	}
+
+	return u.shareLister.ListByWishlistID(ctx, wishlistID)
+}
+
+func (u *WishlistShareUseCase) RevokeWishlistShare(ctx context.Context, currentCustomerID string, customerID string, wishlistID string, shareID string) error {
+	if err := u.ensureOwnWishlist(ctx, currentCustomerID, customerID, wishlistID); err != nil {
+		return err
+	}
+
+	revoked, err := u.shareRevoker.Revoke(ctx, wishlistID, shareID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return e.NewNotFoundError("wishlist share")
+	}
+
+	return nil
+}
+
+// ShowSharedWishlist doesn't go through the access policy, holding the token is the authorization.
+// The owner is shown by name only, and item reservations are shown as the viewer is allowed to see them
+func (u *WishlistShareUseCase) ShowSharedWishlist(ctx context.Context, currentCustomerID string, token string) (*domain.FullfilledWishlist, error) {
+	wishlist, err := resolveSharedWishlist(ctx, u.tokenHasher, u.shareGetter, u.wishlistGetter, u.preferences, token)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := u.customerGetter.GetByID(ctx, wishlist.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner == nil {
+		return nil, e.NewNotFoundError("shared wishlist")
+	}
+
+	statuses, err := u.reservations.GetReservationStatuses(ctx, currentCustomerID, wishlist)
+	if err != nil {
+		return nil, err
+	}
+
+	items := fetchProductsConcurrently(ctx, u.productGetter, wishlist.Items)
+	for i := range items {
+		items[i].Reservation = statuses[items[i].ProductID]
+	}
+
+	return &domain.FullfilledWishlist{
+		ID: wishlist.ID,
+		Customer: &domain.OutgoingCustomer{
+			ID:        owner.ID,
+			Name:      owner.Name,
+			CreatedAt: owner.CreatedAt,
+		},
+		Title: wishlist.Title,
+		Items: items,
+	}, nil
+}
+
+func (u *WishlistShareUseCase) ensureOwnWishlist(ctx context.Context, currentCustomerID string, customerID string, wishlistID string) error {
+	if err := u.accessPolicy.Authorize(ctx, currentCustomerID, domain.PermissionWishlistWrite, customerID); err != nil {
+		return err
+	}
+
+	wishlist, err := u.wishlistGetter.GetById(ctx, wishlistID)
+	if err != nil {
+		return err
+	}
+
+	if wishlist == nil || wishlist.CustomerId != customerID {
+		return e.NewNotFoundError("wishlist")
+	}
+
+	return nil
+}
+
+// resolveSharedWishlist returns the wishlist a share token exposes, unknown, revoked and expired tokens
+// and tokens of owners whose wishlists went private are all reported as not found
+func resolveSharedWishlist(
+	ctx context.Context,
+	tokenHasher domain.Hasher,
+	shareGetter domain.WishlistShareByHashRepository,
+	wishlistGetter domain.WishlistByIdRepository,
+	preferences domain.PreferencesReader,
+	token string,
+) (*domain.Wishlist, error) {
+	if token == "" {
+		return nil, e.NewNotFoundError("shared wishlist")
+	}
+
+	tokenHash, err := tokenHasher.Hash(token)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := shareGetter.GetActiveByHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if share == nil || share.Expired(time.Now()) {
+		return nil, e.NewNotFoundError("shared wishlist")
+	}
+
+	wishlist, err := wishlistGetter.GetById(ctx, share.WishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	// a wishlist that changed hands or was deleted takes its shares with it
+	if wishlist == nil || wishlist.CustomerId != share.CustomerID {
+		return nil, e.NewNotFoundError("shared wishlist")
+	}
+
+	ownerPreferences, err := preferences.GetCustomerPreferences(ctx, wishlist.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerPreferences.WishlistVisibility != domain.WishlistVisibilityShared {
+		return nil, e.NewNotFoundError("shared wishlist")
+	}
+
+	return wishlist, nil
+}
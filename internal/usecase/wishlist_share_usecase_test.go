@@ -0,0 +1,383 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ydoro/wishlist/internal/domain"
+	e "github.com/ydoro/wishlist/internal/domain/errors"
+	"github.com/ydoro/wishlist/internal/usecase"
+	mocks "github.com/ydoro/wishlist/mock/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWishlistShareUseCase_CreateWishlistShare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockProductGetter := mocks.NewMockGetProductUseCase(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockShareStorer := mocks.NewMockWishlistShareCreationRepository(ctrl)
+	mockShareLister := mocks.NewMockListWishlistSharesRepository(ctrl)
+	mockShareGetter := mocks.NewMockWishlistShareByHashRepository(ctrl)
+	mockShareRevoker := mocks.NewMockRevokeWishlistShareRepository(ctrl)
+	mockReservations := mocks.NewMockReservationStatusReader(ctrl)
+	mockPreferences := mocks.NewMockPreferencesReader(ctrl)
+	mockEmailGuard := mocks.NewMockVerifiedEmailGuard(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	ownWishlist := &domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}
+	owner := &domain.Customer{ID: "customer_123"}
+	shared := &domain.Preferences{CustomerID: "customer_123", WishlistVisibility: domain.WishlistVisibilityShared}
+
+	tests := []struct {
+		name              string
+		currentCustomerID string
+		expiresAt         *time.Time
+		policy            domain.Policy
+		setupMocks        func()
+		expectedError     error
+	}{
+		{
+			name:              "creates a share and only stores the token hash",
+			currentCustomerID: "customer_123",
+			expiresAt:         &future,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockPreferences.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(shared, nil)
+				mockTokenGen.EXPECT().Generate().Return("share_token", nil)
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockIDGen.EXPECT().Generate().Return("share_123", nil)
+				mockShareStorer.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, share *domain.WishlistShare) error {
+						assert.Equal(t, "share_123", share.ID)
+						assert.Equal(t, "wishlist_123", share.WishlistID)
+						assert.Equal(t, "customer_123", share.CustomerID)
+						assert.Equal(t, "token_hash", share.TokenHash)
+						assert.Equal(t, &future, share.ExpiresAt)
+						return nil
+					})
+			},
+		},
+		{
+			name:              "expiry in the past",
+			currentCustomerID: "customer_123",
+			expiresAt:         &past,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockPreferences.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(shared, nil)
+			},
+			expectedError: &e.ValidationError{Field: "expires_at", Err: "must be in the future"},
+		},
+		{
+			name:              "unverified email",
+			currentCustomerID: "customer_123",
+			expiresAt:         &future,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(e.NewForbiddenError("email address is not verified"))
+			},
+			expectedError: e.NewForbiddenError("email address is not verified"),
+		},
+		{
+			name:              "private wishlists",
+			currentCustomerID: "customer_123",
+			expiresAt:         &future,
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(nil)
+				mockPreferences.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(&domain.Preferences{CustomerID: "customer_123", WishlistVisibility: domain.WishlistVisibilityPrivate}, nil)
+			},
+			expectedError: e.NewForbiddenError("wishlists are private, set wishlist_visibility to shared in your preferences to share them"),
+		},
+		{
+			name:              "wishlist of another customer",
+			currentCustomerID: "customer_123",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "other_123"}, nil)
+			},
+			expectedError: e.NewNotFoundError("wishlist"),
+		},
+		{
+			name:              "another customer",
+			currentCustomerID: "other_123",
+			setupMocks:        func() {},
+			expectedError:     e.NewUnauthorizedError(),
+		},
+		{
+			name:              "support creates it for an unverified owner",
+			currentCustomerID: "support_123",
+			expiresAt:         &future,
+			policy:            mockPolicy,
+			setupMocks: func() {
+				mockPolicy.EXPECT().Authorize(gomock.Any(), "support_123", domain.PermissionWishlistWrite, "customer_123").Return(nil)
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(ownWishlist, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockEmailGuard.EXPECT().EnsureVerified(owner).Return(e.NewForbiddenError("email address is not verified"))
+			},
+			expectedError: e.NewForbiddenError("email address is not verified"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			policy := tt.policy
+			if policy == nil {
+				policy = ownerOnlyPolicy(ctrl)
+			}
+
+			uc := usecase.NewWishlistShareUseCase(
+				mockCustomerGetter,
+				mockWishlistGetter,
+				mockProductGetter,
+				mockIDGen,
+				mockTokenGen,
+				mockTokenHasher,
+				mockShareStorer,
+				mockShareLister,
+				mockShareGetter,
+				mockShareRevoker,
+				mockReservations,
+				mockPreferences,
+				mockEmailGuard,
+				policy,
+			)
+
+			share, err := uc.CreateWishlistShare(context.Background(), tt.currentCustomerID, "customer_123", "wishlist_123", tt.expiresAt)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, share)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "share_token", share.Token)
+			assert.Equal(t, "share_123", share.ID)
+		})
+	}
+}
+
+func TestWishlistShareUseCase_RevokeWishlistShare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockProductGetter := mocks.NewMockGetProductUseCase(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockShareStorer := mocks.NewMockWishlistShareCreationRepository(ctrl)
+	mockShareLister := mocks.NewMockListWishlistSharesRepository(ctrl)
+	mockShareGetter := mocks.NewMockWishlistShareByHashRepository(ctrl)
+	mockShareRevoker := mocks.NewMockRevokeWishlistShareRepository(ctrl)
+	mockReservations := mocks.NewMockReservationStatusReader(ctrl)
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "revokes the share",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}, nil)
+				mockShareRevoker.EXPECT().Revoke(gomock.Any(), "wishlist_123", "share_123", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name: "unknown share",
+			setupMocks: func() {
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}, nil)
+				mockShareRevoker.EXPECT().Revoke(gomock.Any(), "wishlist_123", "share_123", gomock.Any()).Return(false, nil)
+			},
+			expectedError: e.NewNotFoundError("wishlist share"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistShareUseCase(
+				mockCustomerGetter,
+				mockWishlistGetter,
+				mockProductGetter,
+				mockIDGen,
+				mockTokenGen,
+				mockTokenHasher,
+				mockShareStorer,
+				mockShareLister,
+				mockShareGetter,
+				mockShareRevoker,
+				mockReservations,
+				nil,
+				nil,
+				ownerOnlyPolicy(ctrl),
+			)
+
+			err := uc.RevokeWishlistShare(context.Background(), "customer_123", "customer_123", "wishlist_123", "share_123")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWishlistShareUseCase_ShowSharedWishlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerGetter := mocks.NewMockGetCustomerByIDRepository(ctrl)
+	mockWishlistGetter := mocks.NewMockWishlistByIdRepository(ctrl)
+	mockProductGetter := mocks.NewMockGetProductUseCase(ctrl)
+	mockIDGen := mocks.NewMockIDGenerator(ctrl)
+	mockTokenGen := mocks.NewMockTokenGenerator(ctrl)
+	mockTokenHasher := mocks.NewMockHasher(ctrl)
+	mockShareStorer := mocks.NewMockWishlistShareCreationRepository(ctrl)
+	mockShareLister := mocks.NewMockListWishlistSharesRepository(ctrl)
+	mockShareGetter := mocks.NewMockWishlistShareByHashRepository(ctrl)
+	mockShareRevoker := mocks.NewMockRevokeWishlistShareRepository(ctrl)
+	mockReservations := mocks.NewMockReservationStatusReader(ctrl)
+	mockPreferences := mocks.NewMockPreferencesReader(ctrl)
+
+	past := time.Now().Add(-time.Minute)
+	share := &domain.WishlistShare{ID: "share_123", WishlistID: "wishlist_123", CustomerID: "customer_123"}
+	owner := &domain.Customer{ID: "customer_123", Name: "John", Email: "john@example.com"}
+	shared := domain.DefaultPreferences("customer_123")
+
+	tests := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "shows the wishlist without the owner email and with the reservations",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockShareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(share, nil)
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{
+					ID:         "wishlist_123",
+					CustomerId: "customer_123",
+					Title:      "Birthday",
+					Items:      []domain.WishlistItem{{ProductID: "product_1", Quantity: 2}},
+				}, nil)
+				mockPreferences.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(shared, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(owner, nil)
+				mockReservations.EXPECT().GetReservationStatuses(gomock.Any(), "viewer_123", gomock.Any()).Return(map[string]*domain.ReservationStatus{
+					"product_1": {Reserved: true, ReservedBy: "Mary"},
+				}, nil)
+				mockProductGetter.EXPECT().Execute(gomock.Any(), "product_1").Return(&domain.Product{ID: "product_1", Name: "Chair"}, nil)
+			},
+		},
+		{
+			name: "unknown or revoked token",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockShareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("shared wishlist"),
+		},
+		{
+			name: "expired token",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockShareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(&domain.WishlistShare{ID: "share_123", WishlistID: "wishlist_123", CustomerID: "customer_123", ExpiresAt: &past}, nil)
+			},
+			expectedError: e.NewNotFoundError("shared wishlist"),
+		},
+		{
+			name: "owner made their wishlists private",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockShareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(share, nil)
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}, nil)
+				mockPreferences.EXPECT().
+					GetCustomerPreferences(gomock.Any(), "customer_123").
+					Return(&domain.Preferences{CustomerID: "customer_123", WishlistVisibility: domain.WishlistVisibilityPrivate}, nil)
+			},
+			expectedError: e.NewNotFoundError("shared wishlist"),
+		},
+		{
+			name: "owner deleted",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockShareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(share, nil)
+				mockWishlistGetter.EXPECT().GetById(gomock.Any(), "wishlist_123").Return(&domain.Wishlist{ID: "wishlist_123", CustomerId: "customer_123"}, nil)
+				mockPreferences.EXPECT().GetCustomerPreferences(gomock.Any(), "customer_123").Return(shared, nil)
+				mockCustomerGetter.EXPECT().GetByID(gomock.Any(), "customer_123").Return(nil, nil)
+			},
+			expectedError: e.NewNotFoundError("shared wishlist"),
+		},
+		{
+			name: "repository error",
+			setupMocks: func() {
+				mockTokenHasher.EXPECT().Hash("share_token").Return("token_hash", nil)
+				mockShareGetter.EXPECT().GetActiveByHash(gomock.Any(), "token_hash").Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			uc := usecase.NewWishlistShareUseCase(
+				mockCustomerGetter,
+				mockWishlistGetter,
+				mockProductGetter,
+				mockIDGen,
+				mockTokenGen,
+				mockTokenHasher,
+				mockShareStorer,
+				mockShareLister,
+				mockShareGetter,
+				mockShareRevoker,
+				mockReservations,
+				mockPreferences,
+				nil,
+				ownerOnlyPolicy(ctrl),
+			)
+
+			wishlist, err := uc.ShowSharedWishlist(context.Background(), "viewer_123", "share_token")
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, wishlist)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "Birthday", wishlist.Title)
+			assert.Equal(t, "John", wishlist.Customer.Name)
+			assert.Empty(t, wishlist.Customer.Email)
+			assert.Len(t, wishlist.Items, 1)
+			assert.Equal(t, 2, wishlist.Items[0].Quantity)
+			assert.Equal(t, "Chair", wishlist.Items[0].Product.Name)
+			assert.Equal(t, &domain.ReservationStatus{Reserved: true, ReservedBy: "Mary"}, wishlist.Items[0].Reservation)
+		})
+	}
+}